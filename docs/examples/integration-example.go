@@ -46,7 +46,11 @@ func selectGenerator(column *schema.Column, ctx *generator.Context) (generator.G
 	if len(column.Rules) > 0 {
 		// Get base generator for fallback
 		baseGen, _ := getBaseGenerator(column, ctx)
-		return generator.NewRulesGenerator(column.Rules, baseGen), nil
+		rulesGen, err := generator.NewRulesGenerator(column.Rules, baseGen)
+		if err != nil {
+			return nil, err
+		}
+		return rulesGen, nil
 	}
 
 	// Priority 2: Distribution (for weighted/statistical generation)
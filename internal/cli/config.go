@@ -175,11 +175,12 @@ func setDefaults() {
 func validateConfig(cfg *Config) error {
 	// Validate format
 	validFormats := map[string]bool{
-		"sql":  true,
-		"copy": true,
+		"sql":     true,
+		"copy":    true,
+		"parquet": true,
 	}
 	if !validFormats[cfg.DefaultFormat] {
-		return fmt.Errorf("invalid default_format '%s', must be one of: sql, copy", cfg.DefaultFormat)
+		return fmt.Errorf("invalid default_format '%s', must be one of: sql, copy, parquet", cfg.DefaultFormat)
 	}
 
 	// Validate row count
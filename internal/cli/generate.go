@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
@@ -25,6 +27,20 @@ func NewGenerateCommand() *cobra.Command {
 		format         string
 		jobs           int
 		validateOutput bool
+		rowGroupSize   int64
+		parquetCodec   string
+		timestampUnit  string
+		dialect        string
+		compress       string
+		splitSize      string
+		splitPerTable  bool
+		schemaOnly     bool
+		dataOnly       bool
+		includeTables  []string
+		excludeTables  []string
+		noCreateDB     bool
+		targetSchema   string
+		spillRows      int
 	)
 
 	cmd := &cobra.Command{
@@ -45,12 +61,31 @@ You can use a pre-built template with --template or provide a custom schema with
   # Generate with COPY format
   datagen generate -i schema.json -o dump.sql --format copy
 
+  # Generate one Parquet file per table into a directory
+  datagen generate -i schema.json -o out/ --format parquet --parquet-compression zstd
+
   # Generate from template with custom parameters
   datagen generate --template saas --param tenants=500 -o dump.sql
 
   # Generate with deterministic seed
   datagen generate -i schema.json -o dump.sql --seed 12345
 
+  # Write only the DDL, or only the data of selected tables
+  datagen generate -i schema.json -o schema.sql --schema-only
+  datagen generate -i schema.json -o orders.sql --data-only -t 'order*' -T order_audit
+
+  # Load into an existing (e.g. managed) database under the "staging" schema
+  datagen generate -i schema.json -o dump.sql --no-create-database --target-schema staging
+
+  # Compress the dump (also detected from a .gz or .zst extension)
+  datagen generate -i schema.json -o dump.sql.zst
+
+  # Split into 1GB parts plus an index script (restore with psql -f dump.sql)
+  datagen generate -i schema.json -o dump.sql.gz --split-size 1GB
+
+  # Generate a MySQL or SQLite dump from the same schema
+  datagen generate -i schema.json -o dump.mysql.sql --dialect mysql
+
   # Generate with parallel workers
   datagen generate -i schema.json -o dump.sql --jobs 8
 
@@ -69,14 +104,59 @@ You can use a pre-built template with --template or provide a custom schema with
 			if format == "" {
 				format = "sql" // Default format
 			}
-			validFormats := map[string]bool{"sql": true, "copy": true}
+			validFormats := map[string]bool{"sql": true, "copy": true, "parquet": true}
 			if !validFormats[format] {
-				return fmt.Errorf("invalid format %q, must be one of: sql, copy", format)
+				return fmt.Errorf("invalid format %q, must be one of: sql, copy, parquet", format)
+			}
+			if format == "parquet" && (outputFile == "" || outputFile == "-") {
+				return fmt.Errorf("parquet format requires --output <directory>")
+			}
+
+			// Validate dialect
+			if dialect == "" {
+				dialect = "postgres"
+			}
+			validDialects := map[string]bool{"postgres": true, "mysql": true, "sqlite": true}
+			if !validDialects[dialect] {
+				return fmt.Errorf("invalid dialect %q, must be one of: postgres, mysql, sqlite", dialect)
+			}
+			if dialect != "postgres" && format != "sql" {
+				return fmt.Errorf("--dialect %s requires --format sql", dialect)
+			}
+
+			// Validate output mode and table filters
+			if schemaOnly && dataOnly {
+				return fmt.Errorf("--schema-only and --data-only cannot be used together")
+			}
+			if schemaOnly && format == "parquet" {
+				return fmt.Errorf("--schema-only does not apply to parquet format")
+			}
+			tableFilter := &pipeline.TableFilter{Include: includeTables, Exclude: excludeTables}
+			if err := tableFilter.Validate(); err != nil {
+				return err
+			}
+			if spillRows < 0 {
+				return fmt.Errorf("--spill-rows cannot be negative")
+			}
+
+			// Validate compression and splitting
+			if compress != "" && compress != "gzip" && compress != "zstd" && compress != "none" {
+				return fmt.Errorf("invalid compression %q, must be one of: gzip, zstd, none", compress)
+			}
+			splitBytes, err := pgdump.ParseSize(splitSize)
+			if err != nil {
+				return fmt.Errorf("invalid --split-size: %w", err)
+			}
+			splitting := splitBytes > 0 || splitPerTable
+			if format == "parquet" && (compress != "" || splitting) {
+				return fmt.Errorf("--compress and --split-* do not apply to parquet format, use --parquet-compression")
+			}
+			if splitting && (outputFile == "" || outputFile == "-") {
+				return fmt.Errorf("--split-size and --split-per-table require --output <file>")
 			}
 
 			// Open input (stdin, file, or template)
 			var input *os.File
-			var err error
 
 			if templateName != "" {
 				// Load template
@@ -134,16 +214,29 @@ You can use a pre-built template with --template or provide a custom schema with
 				defer input.Close()
 			}
 
-			// Open output (stdout or file)
-			var output *os.File
-			if outputFile == "" || outputFile == "-" {
+			// Open output (stdout, file, or directory for parquet)
+			var output io.Writer
+			var dumpOutput *pgdump.Output
+			if format == "parquet" {
+				// Parquet writes one file per table inside the output directory
+			} else if (outputFile == "" || outputFile == "-") && (compress == "" || compress == "none") {
 				output = os.Stdout
 			} else {
-				output, err = os.Create(outputFile)
+				path := outputFile
+				if path == "" {
+					path = "-"
+				}
+				dumpOutput, err = pgdump.NewOutput(pgdump.OutputOptions{
+					Path:          path,
+					Compression:   compress,
+					SplitSize:     splitBytes,
+					SplitPerTable: splitPerTable,
+				})
 				if err != nil {
 					return fmt.Errorf("failed to create output file: %w", err)
 				}
-				defer output.Close()
+				defer dumpOutput.Close()
+				output = dumpOutput
 			}
 
 			// Determine number of workers to use
@@ -174,16 +267,80 @@ You can use a pre-built template with --template or provide a custom schema with
 			coordinator := pipeline.NewCoordinator()
 			coordinator.RegisterBasicGenerators()
 			coordinator.RegisterSemanticGenerators()
+			if splitting {
+				coordinator.SetSplitter(dumpOutput)
+			}
+			if schemaOnly {
+				coordinator.SetOutputMode(pipeline.ModeSchemaOnly)
+			} else if dataOnly {
+				coordinator.SetOutputMode(pipeline.ModeDataOnly)
+			}
+			if len(includeTables) > 0 || len(excludeTables) > 0 {
+				coordinator.SetTableFilter(tableFilter)
+			}
+			if noCreateDB {
+				coordinator.SetCreateDatabase(false)
+			}
+			if targetSchema != "" {
+				coordinator.SetTargetSchema(targetSchema)
+			}
+			coordinator.SetSpillThreshold(spillRows)
+			if templateName == "" && inputFile != "" && inputFile != "-" {
+				// Files named in the schema, such as text corpora and dictionaries, are relative to it
+				coordinator.SetBaseDir(filepath.Dir(inputFile))
+			}
 
 			// Execute pipeline with format
 			// Note: Worker pool support will be added in future enhancement
-			if err := coordinator.ExecuteWithFormat(input, output, seed, format); err != nil {
+			if format == "parquet" {
+				writer, err := pgdump.NewParquetWriter(outputFile, pgdump.ParquetOptions{
+					RowGroupSize:  rowGroupSize,
+					Compression:   parquetCodec,
+					TimestampUnit: timestampUnit,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to create parquet writer: %w", err)
+				}
+				if err := coordinator.ExecuteWithWriter(input, writer, seed); err != nil {
+					return fmt.Errorf("generation failed: %w", err)
+				}
+			} else if dialect != "postgres" {
+				writer, err := pgdump.NewDialectWriter(output, dialect)
+				if err != nil {
+					return fmt.Errorf("failed to create %s writer: %w", dialect, err)
+				}
+				if err := coordinator.ExecuteWithWriter(input, writer, seed); err != nil {
+					return fmt.Errorf("generation failed: %w", err)
+				}
+				if dw, ok := writer.(*pgdump.DialectWriter); ok {
+					for _, warning := range dw.Warnings() {
+						LogWarnf("%s: %s", dialect, warning)
+					}
+				}
+			} else if err := coordinator.ExecuteWithFormat(input, output, seed, format); err != nil {
 				return fmt.Errorf("generation failed: %w", err)
 			}
+			for _, warning := range coordinator.Warnings() {
+				LogWarn(warning)
+			}
+
+			// Flush compressed data and write the index script before validating
+			if dumpOutput != nil {
+				if err := dumpOutput.Close(); err != nil {
+					return err
+				}
+				if outputFile != "" && outputFile != "-" {
+					outputFile = dumpOutput.Path()
+				}
+			}
 
 			// Validate output if requested (only for file output, not stdout)
 			if validateOutput {
-				if outputFile == "" || outputFile == "-" {
+				if format == "parquet" {
+					LogWarn("Skipping --validate-output: parquet output contains no SQL")
+				} else if dialect != "postgres" {
+					LogWarnf("Skipping --validate-output: the PostgreSQL parser cannot validate %s output", dialect)
+				} else if outputFile == "" || outputFile == "-" {
 					LogWarn("Cannot validate output when writing to stdout (--validate-output requires --output <file>)")
 				} else {
 					if err := validateGeneratedSQL(outputFile); err != nil {
@@ -207,19 +364,59 @@ You can use a pre-built template with --template or provide a custom schema with
 	cmd.Flags().StringVarP(&inputFile, "input", "i", "", "input schema file (default: stdin)")
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "output SQL file (default: stdout)")
 	cmd.Flags().Int64VarP(&seed, "seed", "s", 0, "random seed for deterministic generation")
-	cmd.Flags().StringVarP(&format, "format", "f", "sql", "output format: sql (INSERT statements), copy (COPY format)")
+	cmd.Flags().StringVarP(&format, "format", "f", "sql", "output format: sql (INSERT statements), copy (COPY format), parquet (one file per table)")
 	cmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "number of parallel workers (default: from config or 4)")
 	cmd.Flags().BoolVar(&validateOutput, "validate-output", false, "validate generated SQL syntax using PostgreSQL parser (requires --output <file>)")
 	cmd.Flags().StringVar(&templateName, "template", "", "use pre-built template (ecommerce, saas, healthcare, finance)")
 	cmd.Flags().StringArrayVar(&templateParams, "param", []string{}, "override template parameters (format: key=value)")
+	cmd.Flags().StringVar(&dialect, "dialect", "postgres", "SQL dialect for DDL and values: postgres, mysql, sqlite (sql format only)")
+	cmd.Flags().BoolVar(&schemaOnly, "schema-only", false, "write only the schema (DDL), no data")
+	cmd.Flags().BoolVar(&dataOnly, "data-only", false, "write only the data, no DDL")
+	cmd.Flags().StringArrayVarP(&includeTables, "table", "t", []string{}, "only write tables matching this glob (repeatable)")
+	cmd.Flags().StringArrayVarP(&excludeTables, "exclude-table", "T", []string{}, "do not write tables matching this glob (repeatable)")
+	cmd.Flags().BoolVar(&noCreateDB, "no-create-database", false, "omit CREATE DATABASE and \\connect (load into the current database)")
+	cmd.Flags().StringVar(&targetSchema, "target-schema", "", "PostgreSQL schema (namespace) for tables without their own (default: public)")
+	cmd.Flags().StringVar(&compress, "compress", "", "compress output: gzip, zstd, none (default: detect from .gz/.zst extension)")
+	cmd.Flags().StringVar(&splitSize, "split-size", "", "split output into numbered files of at most this size (e.g. 500MB, 1GB) with an index script")
+	cmd.Flags().BoolVar(&splitPerTable, "split-per-table", false, "write the data of each table to its own numbered file with an index script")
+	cmd.Flags().IntVar(&spillRows, "spill-rows", pipeline.DefaultSpillRows, "parent rows per key set kept in memory for foreign keys and lookups before spilling to a temp file (0: never spill)")
+	cmd.Flags().Int64Var(&rowGroupSize, "row-group-size", pgdump.DefaultParquetRowGroupSize, "rows per Parquet row group (parquet format only)")
+	cmd.Flags().StringVar(&parquetCodec, "parquet-compression", pgdump.DefaultParquetCompression, "Parquet compression codec: snappy, zstd, none (parquet format only)")
+	cmd.Flags().StringVar(&timestampUnit, "parquet-timestamp-unit", pgdump.DefaultParquetTimestampUnit, "Parquet timestamp unit: ms, us, ns (parquet format only)")
 
 	return cmd
 }
 
-// validateGeneratedSQL validates the SQL in the generated file
+// validateGeneratedSQL validates the SQL in the generated file, following the
+// index script of a split dump and decompressing parts as needed
 func validateGeneratedSQL(filePath string) error {
+	parts, err := pgdump.DumpParts(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read output file: %w", err)
+	}
+
+	for _, part := range parts {
+		if err := validateDumpFile(part); err != nil {
+			if len(parts) > 1 {
+				return fmt.Errorf("%s: %w", part, err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateDumpFile validates the SQL in a single (possibly compressed) dump file
+func validateDumpFile(filePath string) error {
 	// Read the generated SQL file
-	sqlContent, err := os.ReadFile(filePath)
+	file, err := pgdump.OpenDumpFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read output file: %w", err)
+	}
+	defer file.Close()
+
+	sqlContent, err := io.ReadAll(file)
 	if err != nil {
 		return fmt.Errorf("failed to read output file: %w", err)
 	}
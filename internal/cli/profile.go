@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/profile"
+	"github.com/spf13/cobra"
+)
+
+// NewProfileCommand creates the profile command
+func NewProfileCommand() *cobra.Command {
+	var inputFile string
+	var outputFile string
+	var table string
+	var opts profile.Options
+
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Learn column distributions from sample data",
+		Long: `Profile sample data and print the distribution of each column as JSON.
+
+The input is a CSV file with a header line (empty fields are NULL), or, with
+--table, the COPY section of a table in an existing dump (.gz, .zst and
+split dumps are read too).
+
+For each column the profile holds:
+  - its kind: integer, numeric, boolean or text
+  - its null rate
+  - numbers: every value with its share when there are at most --top of
+    them, otherwise a histogram of --bins bins
+  - text: the shares of the --top most frequent values, and the distribution
+    of the lengths of the others
+
+Each distribution can be used as a column generator:
+  {"type": "distribution", "distribution": <distribution>}
+
+Examples:
+  # Profile a CSV file
+  datagen profile --input orders.csv
+
+  # Profile a table of an existing dump
+  datagen profile --input dump.sql.gz --table orders --output orders.profile.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			columns, rows, err := readSample(cmd, inputFile, table)
+			if err != nil {
+				return err
+			}
+
+			p := profile.Build(columns, rows, opts)
+			p.Source = inputFile
+			if table != "" {
+				p.Source = table
+				if inputFile != "" {
+					p.Source = inputFile + ":" + table
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if outputFile != "" {
+				f, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(p); err != nil {
+				return fmt.Errorf("failed to encode profile: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "input", "i", "", "input CSV file, or dump with --table (default: stdin)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "output JSON file (default: stdout)")
+	cmd.Flags().StringVarP(&table, "table", "t", "", "read the COPY section of this table from a dump instead of CSV")
+	cmd.Flags().IntVar(&opts.TopK, "top", profile.DefaultTopK, "most frequent values kept per column")
+	cmd.Flags().IntVar(&opts.Bins, "bins", profile.DefaultBins, "histogram bins of numeric columns with more than --top distinct values")
+
+	return cmd
+}
+
+// readSample reads the sample rows of the profile command from CSV, or from
+// the COPY section of a table in a dump
+func readSample(cmd *cobra.Command, inputFile, table string) ([]string, [][]interface{}, error) {
+	var input io.Reader = cmd.InOrStdin()
+	if inputFile != "" {
+		parts := []string{inputFile}
+		if table != "" {
+			var err error
+			if parts, err = pgdump.DumpParts(inputFile); err != nil {
+				return nil, nil, fmt.Errorf("failed to open input file: %w", err)
+			}
+		}
+
+		readers := make([]io.Reader, len(parts))
+		for i, part := range parts {
+			f, err := pgdump.OpenDumpFile(part)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to open input file: %w", err)
+			}
+			defer f.Close()
+			readers[i] = f
+		}
+		input = io.MultiReader(readers...)
+	}
+
+	if table != "" {
+		return pgdump.ReadCopySection(input, table)
+	}
+	return profile.ReadCSV(input)
+}
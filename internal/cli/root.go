@@ -70,6 +70,7 @@ and deterministic seeds for reproducible datasets.`,
 	cmd.AddCommand(NewVersionCommand())
 	cmd.AddCommand(NewGenerateCommand())
 	cmd.AddCommand(NewValidateCommand())
+	cmd.AddCommand(NewProfileCommand())
 	cmd.AddCommand(newTemplateCmd())
 
 	return cmd
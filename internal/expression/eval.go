@@ -0,0 +1,391 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values are nil (NULL), int64, float64, string, bool or time.Time.
+// NULL propagates like in SQL: arithmetic, concatenation and comparisons
+// involving NULL yield NULL, and if() treats a NULL condition as false.
+
+type node interface {
+	eval(row map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type columnNode struct {
+	name string
+}
+
+func (n *columnNode) eval(row map[string]interface{}) (interface{}, error) {
+	value, ok := row[n.name]
+	if !ok {
+		return nil, fmt.Errorf("column %s has no value", n.name)
+	}
+	return normalize(value), nil
+}
+
+type negateNode struct {
+	operand node
+}
+
+func (n *negateNode) eval(row map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	switch x := numeric(v).(type) {
+	case int64:
+		return -x, nil
+	case float64:
+		return -x, nil
+	}
+	return nil, fmt.Errorf("cannot negate %s", typeName(v))
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(row map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("not expects a boolean, got %s", typeName(v))
+	}
+	return !b, nil
+}
+
+// logicalNode is and/or with SQL's three-valued logic
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicalNode) eval(row map[string]interface{}) (interface{}, error) {
+	left, err := boolean(n.left, row)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit: false and x = false, true or x = true
+	if left != nil && *left != n.and {
+		return *left, nil
+	}
+	right, err := boolean(n.right, row)
+	if err != nil {
+		return nil, err
+	}
+	if right != nil && *right != n.and {
+		return *right, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+	return n.and, nil
+}
+
+// boolean evaluates a condition, returning nil for NULL
+func boolean(n node, row map[string]interface{}) (*bool, error) {
+	v, err := n.eval(row)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("expected a boolean condition, got %s", typeName(v))
+	}
+	return &b, nil
+}
+
+type ifNode struct {
+	cond, then, otherwise node
+}
+
+func (n *ifNode) eval(row map[string]interface{}) (interface{}, error) {
+	cond, err := boolean(n.cond, row)
+	if err != nil {
+		return nil, err
+	}
+	if cond != nil && *cond {
+		return n.then.eval(row)
+	}
+	if n.otherwise == nil {
+		return nil, nil
+	}
+	return n.otherwise.eval(row)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(row map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	switch n.op {
+	case "||":
+		return Text(left) + Text(right), nil
+	case "+", "-", "*", "/", "%":
+		return arithmetic(n.op, left, right)
+	}
+
+	cmp, err := compare(left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "=", "==":
+		return cmp == 0, nil
+	case "!=", "<>":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// arithmetic applies an arithmetic operator. Integers stay integers except
+// for division, which always yields a float.
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	a, b := numeric(left), numeric(right)
+	x, aInt := a.(int64)
+	y, bInt := b.(int64)
+	if aInt && bInt {
+		switch op {
+		case "+":
+			return x + y, nil
+		case "-":
+			return x - y, nil
+		case "*":
+			return x * y, nil
+		case "%":
+			if y == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return x % y, nil
+		}
+	}
+
+	f, aOK := toFloat(a)
+	g, bOK := toFloat(b)
+	if !aOK || !bOK {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, typeName(left), typeName(right))
+	}
+	switch op {
+	case "+":
+		return f + g, nil
+	case "-":
+		return f - g, nil
+	case "*":
+		return f * g, nil
+	case "/":
+		if g == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return f / g, nil
+	default:
+		if g == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(f, g), nil
+	}
+}
+
+// compare orders two non-NULL values: numbers numerically, times
+// chronologically and text alphabetically. Text is converted to a number or
+// time when compared with one.
+func compare(a, b interface{}) (int, error) {
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, nil
+			case !x:
+				return -1, nil
+			default:
+				return 1, nil
+			}
+		}
+	case time.Time:
+		if y, ok := toTime(b); ok {
+			return x.Compare(y), nil
+		}
+	}
+	if y, ok := b.(time.Time); ok {
+		if x, ok := toTime(a); ok {
+			return x.Compare(y), nil
+		}
+	}
+
+	x, aOK := toFloat(numeric(a))
+	y, bOK := toFloat(numeric(b))
+	if !aOK || !bOK {
+		return 0, fmt.Errorf("cannot compare %s with %s", typeName(a), typeName(b))
+	}
+	switch {
+	case x < y:
+		return -1, nil
+	case x > y:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Compare orders two generated values the way expression comparisons do,
+// failing when either is NULL or they cannot be compared
+func Compare(a, b interface{}) (int, error) {
+	a, b = normalize(a), normalize(b)
+	if a == nil || b == nil {
+		return 0, fmt.Errorf("cannot compare null")
+	}
+	return compare(a, b)
+}
+
+// normalize converts a generated value to one of the expression value types
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int:
+		return int64(x)
+	case int8:
+		return int64(x)
+	case int16:
+		return int64(x)
+	case int32:
+		return int64(x)
+	case uint8:
+		return int64(x)
+	case uint16:
+		return int64(x)
+	case uint32:
+		return int64(x)
+	case uint64:
+		return int64(x)
+	case float32:
+		return float64(x)
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return *x
+	}
+	return v
+}
+
+// numeric converts text holding a number to that number; other values are
+// returned unchanged
+func numeric(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return v
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+// timeLayouts are the text formats accepted where a time is expected
+var timeLayouts = []string{"2006-01-02 15:04:05", "2006-01-02", time.RFC3339, time.RFC3339Nano}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, x); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Text formats a value the way || and text() do
+func Text(v interface{}) string {
+	switch x := normalize(v).(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case string:
+		return "text"
+	case bool:
+		return "boolean"
+	case time.Time:
+		return "timestamp"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,330 @@
+// Package expression implements the small language used to derive a column
+// from other columns of the same row, e.g.
+//
+//	first_name || ' ' || last_name
+//	round(price * 0.9, 2)
+//	if(status = 'shipped', add_days(ordered_at, 3), null)
+//
+// Expressions only read the values of the row they are evaluated on: there
+// are no assignments, loops or access to anything outside the row.
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expression is a parsed expression
+type Expression struct {
+	source  string
+	root    node
+	columns []string
+}
+
+// Parse parses an expression
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, seen: make(map[string]bool)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return &Expression{source: source, root: root, columns: p.columns}, nil
+}
+
+// Columns returns the columns the expression reads, in order of appearance
+func (e *Expression) Columns() []string {
+	return e.columns
+}
+
+// Eval evaluates the expression over a row
+func (e *Expression) Eval(row map[string]interface{}) (interface{}, error) {
+	return e.root.eval(row)
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	columns []string
+	seen    map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the given keyword, consuming it if so
+func (p *parser) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokIdent && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// operator consumes the next token if it is one of the given operators
+func (p *parser) operator(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// Precedence, lowest first: or, and, not, comparison, ||, + -, * / %, unary -
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.keyword("not") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.operator("=", "==", "!=", "<>", "<", "<=", ">", ">="); ok {
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseConcat() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.operator("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.operator("-", "+"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return operand, nil
+		}
+		return &negateNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		if !strings.Contains(tok.text, ".") {
+			if n, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+				return &literalNode{value: n}, nil
+			}
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos+1)
+		}
+		return &literalNode{value: f}, nil
+
+	case tokString:
+		return &literalNode{value: tok.text}, nil
+
+	case tokQuotedIdent:
+		return p.column(tok.text), nil
+
+	case tokIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		case "and", "or", "not":
+			return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+		}
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		return p.column(tok.text), nil
+
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos+1)
+		}
+		return inner, nil
+
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+}
+
+// parseCall parses the arguments of a function call
+func (p *parser) parseCall(name token) (node, error) {
+	p.next() // (
+	var args []node
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != tokRParen {
+		return nil, fmt.Errorf("expected ')' at position %d", closing.pos+1)
+	}
+
+	fnName := strings.ToLower(name.text)
+	if fnName == "if" {
+		if len(args) < 2 || len(args) > 3 {
+			return nil, fmt.Errorf("if() takes 2 or 3 arguments, got %d", len(args))
+		}
+		n := &ifNode{cond: args[0], then: args[1]}
+		if len(args) == 3 {
+			n.otherwise = args[2]
+		}
+		return n, nil
+	}
+
+	fn, ok := functions[fnName]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s() at position %d", name.text, name.pos+1)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("%s() takes %s, got %d", fnName, fn.arity(), len(args))
+	}
+	return &callNode{name: fnName, fn: fn, args: args}, nil
+}
+
+// column returns a node reading a column, recording it as a dependency
+func (p *parser) column(name string) node {
+	if !p.seen[name] {
+		p.seen[name] = true
+		p.columns = append(p.columns, name)
+	}
+	return &columnNode{name: name}
+}
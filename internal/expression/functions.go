@@ -0,0 +1,364 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// function is a built-in function. Unless lenient, a NULL argument makes the
+// result NULL without calling it.
+type function struct {
+	minArgs, maxArgs int // maxArgs < 0 for any number
+	lenient          bool
+	call             func(args []interface{}) (interface{}, error)
+}
+
+func (fn *function) arity() string {
+	switch {
+	case fn.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", fn.minArgs)
+	case fn.minArgs == fn.maxArgs && fn.minArgs == 1:
+		return "1 argument"
+	case fn.minArgs == fn.maxArgs:
+		return fmt.Sprintf("%d arguments", fn.minArgs)
+	default:
+		return fmt.Sprintf("%d to %d arguments", fn.minArgs, fn.maxArgs)
+	}
+}
+
+type callNode struct {
+	name string
+	fn   *function
+	args []node
+}
+
+func (n *callNode) eval(row map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil && !n.fn.lenient {
+			return nil, nil
+		}
+		args[i] = v
+	}
+
+	result, err := n.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return result, nil
+}
+
+var functions = map[string]*function{
+	// Text
+	"lower":   {minArgs: 1, maxArgs: 1, call: textFunc(strings.ToLower)},
+	"upper":   {minArgs: 1, maxArgs: 1, call: textFunc(strings.ToUpper)},
+	"trim":    {minArgs: 1, maxArgs: 1, call: textFunc(strings.TrimSpace)},
+	"slug":    {minArgs: 1, maxArgs: 1, call: textFunc(slug)},
+	"length":  {minArgs: 1, maxArgs: 1, call: fnLength},
+	"substr":  {minArgs: 2, maxArgs: 3, call: fnSubstr},
+	"left":    {minArgs: 2, maxArgs: 2, call: fnLeft},
+	"right":   {minArgs: 2, maxArgs: 2, call: fnRight},
+	"replace": {minArgs: 3, maxArgs: 3, call: fnReplace},
+	"concat":  {minArgs: 1, maxArgs: -1, lenient: true, call: fnConcat},
+	"like":    {minArgs: 2, maxArgs: 2, call: fnLike},
+	"text":    {minArgs: 1, maxArgs: 1, call: func(args []interface{}) (interface{}, error) { return Text(args[0]), nil }},
+
+	// Numbers
+	"abs":   {minArgs: 1, maxArgs: 1, call: fnAbs},
+	"round": {minArgs: 1, maxArgs: 2, call: fnRound},
+	"floor": {minArgs: 1, maxArgs: 1, call: floatFunc(math.Floor)},
+	"ceil":  {minArgs: 1, maxArgs: 1, call: floatFunc(math.Ceil)},
+	"int":   {minArgs: 1, maxArgs: 1, call: fnInt},
+	"float": {minArgs: 1, maxArgs: 1, call: fnFloat},
+
+	// Any comparable values
+	"min":      {minArgs: 1, maxArgs: -1, call: extremum(-1)},
+	"max":      {minArgs: 1, maxArgs: -1, call: extremum(1)},
+	"coalesce": {minArgs: 1, maxArgs: -1, lenient: true, call: fnCoalesce},
+	"is_null":  {minArgs: 1, maxArgs: 1, lenient: true, call: fnIsNull},
+
+	// Dates
+	"add_days":     {minArgs: 2, maxArgs: 2, call: addDuration(24 * time.Hour)},
+	"add_hours":    {minArgs: 2, maxArgs: 2, call: addDuration(time.Hour)},
+	"add_minutes":  {minArgs: 2, maxArgs: 2, call: addDuration(time.Minute)},
+	"add_months":   {minArgs: 2, maxArgs: 2, call: fnAddMonths},
+	"days_between": {minArgs: 2, maxArgs: 2, call: fnDaysBetween},
+	"date":         {minArgs: 1, maxArgs: 1, call: fnDate},
+	"year":         {minArgs: 1, maxArgs: 1, call: timePart(func(t time.Time) int { return t.Year() })},
+	"month":        {minArgs: 1, maxArgs: 1, call: timePart(func(t time.Time) int { return int(t.Month()) })},
+	"day":          {minArgs: 1, maxArgs: 1, call: timePart(func(t time.Time) int { return t.Day() })},
+	"hour":         {minArgs: 1, maxArgs: 1, call: timePart(func(t time.Time) int { return t.Hour() })},
+}
+
+func textFunc(f func(string) string) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		return f(Text(args[0])), nil
+	}
+}
+
+// slug lowercases text and joins its words with hyphens
+func slug(s string) string {
+	var sb strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pending && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			pending = false
+			sb.WriteRune(r)
+		} else {
+			pending = true
+		}
+	}
+	return sb.String()
+}
+
+func fnLength(args []interface{}) (interface{}, error) {
+	return int64(len([]rune(Text(args[0])))), nil
+}
+
+// fnSubstr returns count characters from start (1-based), like SQL substr
+func fnSubstr(args []interface{}) (interface{}, error) {
+	runes := []rune(Text(args[0]))
+	start, err := integer(args[1])
+	if err != nil {
+		return nil, err
+	}
+	end := int64(len(runes))
+	if len(args) == 3 {
+		count, err := integer(args[2])
+		if err != nil {
+			return nil, err
+		}
+		end = min(end, start-1+count)
+	}
+	start = max(start-1, 0)
+	if start >= end {
+		return "", nil
+	}
+	return string(runes[start:end]), nil
+}
+
+func fnLeft(args []interface{}) (interface{}, error) {
+	runes := []rune(Text(args[0]))
+	n, err := integer(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return string(runes[:min(max(n, 0), int64(len(runes)))]), nil
+}
+
+func fnRight(args []interface{}) (interface{}, error) {
+	runes := []rune(Text(args[0]))
+	n, err := integer(args[1])
+	if err != nil {
+		return nil, err
+	}
+	n = min(max(n, 0), int64(len(runes)))
+	return string(runes[int64(len(runes))-n:]), nil
+}
+
+func fnReplace(args []interface{}) (interface{}, error) {
+	return strings.ReplaceAll(Text(args[0]), Text(args[1]), Text(args[2])), nil
+}
+
+// fnConcat joins its arguments as text, skipping NULLs
+func fnConcat(args []interface{}) (interface{}, error) {
+	var sb strings.Builder
+	for _, arg := range args {
+		sb.WriteString(Text(arg))
+	}
+	return sb.String(), nil
+}
+
+// fnLike matches text against a SQL LIKE pattern: % is any run of
+// characters, _ any single character and \ escapes them
+func fnLike(args []interface{}) (interface{}, error) {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	pattern := []rune(Text(args[1]))
+	for i := 0; i < len(pattern); i++ {
+		switch r := pattern[i]; {
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteString(".")
+		case r == '\\' && i+1 < len(pattern):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return regexp.MustCompile(sb.String() + "$").MatchString(Text(args[0])), nil
+}
+
+func fnAbs(args []interface{}) (interface{}, error) {
+	switch x := numeric(args[0]).(type) {
+	case int64:
+		if x < 0 {
+			return -x, nil
+		}
+		return x, nil
+	case float64:
+		return math.Abs(x), nil
+	}
+	return nil, fmt.Errorf("expected a number, got %s", typeName(args[0]))
+}
+
+// fnRound rounds to the given number of decimal places (default 0)
+func fnRound(args []interface{}) (interface{}, error) {
+	v := numeric(args[0])
+	if n, ok := v.(int64); ok {
+		return n, nil
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return nil, fmt.Errorf("expected a number, got %s", typeName(args[0]))
+	}
+	places := int64(0)
+	if len(args) == 2 {
+		var err error
+		if places, err = integer(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	scale := math.Pow(10, float64(places))
+	return math.Round(f*scale) / scale, nil
+}
+
+func floatFunc(f func(float64) float64) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		v := numeric(args[0])
+		if n, ok := v.(int64); ok {
+			return n, nil
+		}
+		x, ok := toFloat(v)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %s", typeName(args[0]))
+		}
+		return f(x), nil
+	}
+}
+
+func fnInt(args []interface{}) (interface{}, error) {
+	if b, ok := args[0].(bool); ok {
+		if b {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	return integer(args[0])
+}
+
+func fnFloat(args []interface{}) (interface{}, error) {
+	f, ok := toFloat(numeric(args[0]))
+	if !ok {
+		return nil, fmt.Errorf("expected a number, got %s", typeName(args[0]))
+	}
+	return f, nil
+}
+
+// integer converts a number (or numeric text) to an integer, truncating fractions
+func integer(v interface{}) (int64, error) {
+	switch x := numeric(v).(type) {
+	case int64:
+		return x, nil
+	case float64:
+		return int64(x), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %s", typeName(v))
+}
+
+// extremum returns the smallest (sign -1) or largest (sign 1) argument
+func extremum(sign int) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		best := args[0]
+		for _, arg := range args[1:] {
+			cmp, err := compare(arg, best)
+			if err != nil {
+				return nil, err
+			}
+			if cmp*sign > 0 {
+				best = arg
+			}
+		}
+		return best, nil
+	}
+}
+
+func fnCoalesce(args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnIsNull(args []interface{}) (interface{}, error) {
+	return args[0] == nil, nil
+}
+
+func addDuration(unit time.Duration) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		t, ok := toTime(args[0])
+		if !ok {
+			return nil, fmt.Errorf("expected a timestamp, got %s", typeName(args[0]))
+		}
+		n, ok := toFloat(numeric(args[1]))
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %s", typeName(args[1]))
+		}
+		return t.Add(time.Duration(n * float64(unit))), nil
+	}
+}
+
+func fnAddMonths(args []interface{}) (interface{}, error) {
+	t, ok := toTime(args[0])
+	if !ok {
+		return nil, fmt.Errorf("expected a timestamp, got %s", typeName(args[0]))
+	}
+	n, err := integer(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return t.AddDate(0, int(n), 0), nil
+}
+
+// fnDaysBetween returns the number of whole days from the first time to the second
+func fnDaysBetween(args []interface{}) (interface{}, error) {
+	from, ok := toTime(args[0])
+	to, ok2 := toTime(args[1])
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("expected timestamps, got %s and %s", typeName(args[0]), typeName(args[1]))
+	}
+	return int64(to.Sub(from) / (24 * time.Hour)), nil
+}
+
+func fnDate(args []interface{}) (interface{}, error) {
+	t, ok := toTime(args[0])
+	if !ok {
+		return nil, fmt.Errorf("expected a timestamp, got %s", typeName(args[0]))
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+}
+
+func timePart(part func(time.Time) int) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		t, ok := toTime(args[0])
+		if !ok {
+			return nil, fmt.Errorf("expected a timestamp, got %s", typeName(args[0]))
+		}
+		return int64(part(t)), nil
+	}
+}
@@ -0,0 +1,118 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokQuotedIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists the operator tokens, longest first
+var operators = []string{"||", "<=", ">=", "<>", "!=", "==", "+", "-", "*", "/", "%", "=", "<", ">"}
+
+// tokenize splits an expression into tokens
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+
+		case isDigit(ch) || (ch == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, src[start:i], start})
+
+		case ch == '\'':
+			// SQL string literal: '' is an escaped quote
+			start := i
+			var sb strings.Builder
+			for i++; ; i++ {
+				if i >= len(src) {
+					return nil, fmt.Errorf("unterminated string at position %d", start+1)
+				}
+				if src[i] == '\'' {
+					if i+1 < len(src) && src[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(src[i])
+			}
+			tokens = append(tokens, token{tokString, sb.String(), start})
+
+		case ch == '"':
+			// Quoted column name, for names with spaces or that are keywords
+			end := strings.IndexByte(src[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted name at position %d", i+1)
+			}
+			tokens = append(tokens, token{tokQuotedIdent, src[i+1 : i+1+end], i})
+			i += end + 2
+
+		case isIdentStart(ch):
+			start := i
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i], start})
+
+		case ch == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case ch == ',':
+			tokens = append(tokens, token{tokComma, ",", i})
+			i++
+
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", ch, i+1)
+			}
+			tokens = append(tokens, token{tokOperator, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
@@ -97,6 +97,10 @@ func NewTimestampGenerator() *TimestampGenerator {
 }
 
 func (g *TimestampGenerator) Generate(ctx *Context) (interface{}, error) {
+	if ctx.TimeBounds != nil {
+		return ctx.TimeBounds.pick(ctx.Rand, 365*24*time.Hour), nil
+	}
+
 	// Generate timestamps within the past year
 	now := time.Now()
 	pastYear := now.AddDate(-1, 0, 0)
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// ConditionalGenerator picks a value with weights that depend on columns
+// already generated for the row, or on the parent row (conditional
+// probability table)
+type ConditionalGenerator struct {
+	conditional *schema.Conditional
+	enums       map[*schema.ConditionalEntry]*WeightedEnumGenerator
+}
+
+// NewConditionalGenerator creates a generator for a conditional probability table
+func NewConditionalGenerator(conditional *schema.Conditional) *ConditionalGenerator {
+	enums := make(map[*schema.ConditionalEntry]*WeightedEnumGenerator, len(conditional.Entries))
+	for _, entry := range conditional.Entries {
+		enums[entry] = NewWeightedEnumGenerator(entry.Weights)
+	}
+	return &ConditionalGenerator{conditional: conditional, enums: enums}
+}
+
+func (g *ConditionalGenerator) Name() string {
+	return "conditional"
+}
+
+func (g *ConditionalGenerator) Generate(ctx *Context) (interface{}, error) {
+	values := make([]interface{}, len(g.conditional.Given))
+	for i, field := range g.conditional.Given {
+		values[i], _ = conditionValue(ctx, field)
+	}
+
+	entry := g.conditional.Match(values)
+	if entry == nil {
+		given := make([]string, len(values))
+		for i, v := range values {
+			given[i] = fmt.Sprintf("%s=%v", g.conditional.Given[i], v)
+		}
+		return nil, fmt.Errorf("no conditional weights for %s (add a \"*\" entry for other values)", strings.Join(given, ", "))
+	}
+	return g.enums[entry].Generate(ctx)
+}
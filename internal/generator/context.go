@@ -15,6 +15,16 @@ type Context struct {
 	ColumnName string
 	RowIndex   int
 
+	// RowData holds the values generated so far for the current row
+	RowData map[string]interface{}
+
+	// ParentValue, when set, returns a column of the parent row the current row
+	// references in the given table
+	ParentValue func(table, column string) (interface{}, bool)
+
+	// TimeBounds, when set, constrains timestamp generators (temporal constraints)
+	TimeBounds *TimeBounds
+
 	// Custom data storage
 	data map[string]interface{}
 }
@@ -47,11 +57,14 @@ func (c *Context) Get(key string) (interface{}, bool) {
 func (c *Context) Clone() *Context {
 	// Create new context with same random state
 	newCtx := &Context{
-		Rand:       rand.New(rand.NewSource(c.Rand.Int63())),
-		TableName:  c.TableName,
-		ColumnName: c.ColumnName,
-		RowIndex:   c.RowIndex,
-		data:       make(map[string]interface{}),
+		Rand:        rand.New(rand.NewSource(c.Rand.Int63())),
+		TableName:   c.TableName,
+		ColumnName:  c.ColumnName,
+		RowIndex:    c.RowIndex,
+		RowData:     c.RowData,
+		ParentValue: c.ParentValue,
+		TimeBounds:  c.TimeBounds,
+		data:        make(map[string]interface{}),
 	}
 
 	// Copy custom data
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// CopulaGenerator draws correlated values for a group of columns with a
+// Gaussian copula: correlated standard normal values are drawn through the
+// Cholesky factor of the correlation matrix, then mapped onto each column's
+// marginal distribution through its quantile function
+type CopulaGenerator struct {
+	columns   []string
+	marginals []*schema.DistributionConfig
+	sorted    [][]float64 // sorted values of empirical marginals
+	factor    [][]float64
+}
+
+// NewCopulaGenerator creates a generator for a correlation group
+func NewCopulaGenerator(corr *schema.Correlation) (*CopulaGenerator, error) {
+	factor, err := corr.Cholesky()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &CopulaGenerator{
+		columns:   corr.Columns,
+		marginals: make([]*schema.DistributionConfig, len(corr.Columns)),
+		sorted:    make([][]float64, len(corr.Columns)),
+		factor:    factor,
+	}
+	for i, name := range corr.Columns {
+		marginal := corr.Marginals[name]
+		if marginal == nil {
+			return nil, fmt.Errorf("column %s has no marginal distribution", name)
+		}
+		if err := schema.ValidateMarginal(marginal); err != nil {
+			return nil, fmt.Errorf("column %s: %w", name, err)
+		}
+		g.marginals[i] = marginal
+		g.sorted[i] = empiricalValues(marginal)
+	}
+	return g, nil
+}
+
+func (g *CopulaGenerator) Name() string {
+	return "copula"
+}
+
+// Generate returns the values of the group's columns, by column name
+func (g *CopulaGenerator) Generate(ctx *Context) (interface{}, error) {
+	return g.Sample(ctx), nil
+}
+
+// Sample draws one value per column of the group, by column name
+func (g *CopulaGenerator) Sample(ctx *Context) map[string]float64 {
+	n := len(g.columns)
+	independent := make([]float64, n)
+	for i := range independent {
+		independent[i] = ctx.Rand.NormFloat64()
+	}
+
+	values := make(map[string]float64, n)
+	for i, name := range g.columns {
+		z := 0.0
+		for k := 0; k <= i; k++ {
+			z += g.factor[i][k] * independent[k]
+		}
+		values[name] = marginalQuantile(g.marginals[i], g.sorted[i], z)
+	}
+	return values
+}
+
+// marginalQuantile maps a standard normal value onto a marginal distribution
+// (sorted holds the values of an empirical marginal)
+func marginalQuantile(marginal *schema.DistributionConfig, sorted []float64, z float64) float64 {
+	switch marginal.Type {
+	case "normal":
+		return clamp(*marginal.Mean+*marginal.StdDev*z, marginal)
+	case "log_normal":
+		return clamp(math.Exp(*marginal.Mean+*marginal.StdDev*z), marginal)
+	case "uniform":
+		min, max := toFloat64(marginal.Min), toFloat64(marginal.Max)
+		return min + (max-min)*normalCDF(z)
+	default: // empirical
+		return empiricalQuantile(marginal, sorted, normalCDF(z))
+	}
+}
+
+// empiricalQuantile returns the value of an empirical distribution at a
+// cumulative probability p: interpolated between its sorted observed values,
+// or within the histogram bin p falls in (bins are taken in order)
+func empiricalQuantile(config *schema.DistributionConfig, sorted []float64, p float64) float64 {
+	if len(sorted) > 0 {
+		pos := p * float64(len(sorted)-1)
+		lower := int(pos)
+		if lower >= len(sorted)-1 {
+			return sorted[len(sorted)-1]
+		}
+		return sorted[lower] + (sorted[lower+1]-sorted[lower])*(pos-float64(lower))
+	}
+
+	total := 0.0
+	for _, bin := range config.Bins {
+		total += bin.Weight
+	}
+	target := p * total
+	cumulative := 0.0
+	last := 0.0
+	for _, bin := range config.Bins {
+		if bin.Weight <= 0 {
+			continue
+		}
+		if cumulative+bin.Weight > target {
+			return bin.Min + (bin.Max-bin.Min)*(target-cumulative)/bin.Weight
+		}
+		cumulative += bin.Weight
+		last = bin.Max
+	}
+	return last
+}
+
+// empiricalValues returns the sorted values of an empirical distribution, or nil
+func empiricalValues(config *schema.DistributionConfig) []float64 {
+	if config.Type != "empirical" {
+		return nil
+	}
+	sorted := append([]float64(nil), config.Values...)
+	sort.Float64s(sorted)
+	return sorted
+}
+
+// clamp keeps a value within the min and max of its distribution, when set
+func clamp(value float64, config *schema.DistributionConfig) float64 {
+	if config.Min != nil {
+		value = math.Max(value, toFloat64(config.Min))
+	}
+	if config.Max != nil {
+		value = math.Min(value, toFloat64(config.Max))
+	}
+	return value
+}
+
+// normalCDF is the cumulative distribution function of the standard normal distribution
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// Dictionary holds the rows of a reference data file
+type Dictionary struct {
+	columns []string
+	rows    [][]string
+}
+
+// LoadDictionary reads the reference data file of a dictionary
+func LoadDictionary(path string, config *schema.Dictionary) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary: %w", err)
+	}
+	defer f.Close()
+
+	dict, err := NewDictionary(f, config)
+	if err != nil {
+		return nil, fmt.Errorf("dictionary %s: %w", path, err)
+	}
+	return dict, nil
+}
+
+// NewDictionary reads reference data: CSV or TSV with a header line, or one
+// value per line (a column named "value") for other files. Empty fields are
+// NULL.
+func NewDictionary(r io.Reader, config *schema.Dictionary) (*Dictionary, error) {
+	if !config.Tabular() {
+		dict := &Dictionary{columns: []string{"value"}}
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+				dict.rows = append(dict.rows, []string{line})
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read dictionary: %w", err)
+		}
+		if len(dict.rows) == 0 {
+			return nil, fmt.Errorf("dictionary has no values")
+		}
+		return dict, nil
+	}
+
+	reader := csv.NewReader(r)
+	if strings.HasSuffix(strings.ToLower(config.File), ".tsv") {
+		reader.Comma = '\t'
+		reader.LazyQuotes = true
+	}
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("dictionary has no header line")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	// Spreadsheets often save CSV with a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("dictionary has no rows")
+	}
+	return &Dictionary{columns: header, rows: rows}, nil
+}
+
+// Len returns the number of rows of the dictionary
+func (d *Dictionary) Len() int {
+	return len(d.rows)
+}
+
+// Column returns the index of a column of the dictionary. An empty name
+// selects the column named fallback, or the first column when there is none.
+func (d *Dictionary) Column(name, fallback string) (int, error) {
+	if name == "" {
+		name = fallback
+		if d.index(name) < 0 {
+			return 0, nil
+		}
+	}
+	if i := d.index(name); i >= 0 {
+		return i, nil
+	}
+	return 0, fmt.Errorf("dictionary has no column '%s' (has %s)", name, strings.Join(d.columns, ", "))
+}
+
+func (d *Dictionary) index(name string) int {
+	for i, col := range d.columns {
+		if strings.EqualFold(strings.TrimSpace(col), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Value returns a field of a row, nil when it is empty
+func (d *Dictionary) Value(row, column int) interface{} {
+	if d.rows[row][column] == "" {
+		return nil
+	}
+	return d.rows[row][column]
+}
+
+// DictionarySampler draws rows of a dictionary, uniformly or by the weight
+// column, with or without replacement
+type DictionarySampler struct {
+	dict        *Dictionary
+	weights     []float64 // nil for uniform draws
+	cumulative  []float64 // running totals of the weights, with replacement
+	replacement bool
+
+	order []int // without replacement: the rows in draw order
+	drawn int   // rows of order drawn so far
+}
+
+// NewDictionarySampler creates a sampler of the rows of a dictionary. Rows of
+// zero weight are never drawn.
+func NewDictionarySampler(dict *Dictionary, weightColumn string, replacement bool) (*DictionarySampler, error) {
+	s := &DictionarySampler{dict: dict, replacement: replacement}
+	if weightColumn == "" {
+		return s, nil
+	}
+
+	column, err := dict.Column(weightColumn, "")
+	if err != nil {
+		return nil, err
+	}
+	s.weights = make([]float64, len(dict.rows))
+	s.cumulative = make([]float64, len(dict.rows))
+	total := 0.0
+	for i, row := range dict.rows {
+		w, err := strconv.ParseFloat(strings.TrimSpace(row[column]), 64)
+		if err != nil || w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+			return nil, fmt.Errorf("dictionary weight '%s' of row %d must be a number of at least 0, got %q", weightColumn, i+1, row[column])
+		}
+		s.weights[i] = w
+		total += w
+		s.cumulative[i] = total
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("dictionary weight '%s' needs a positive weight", weightColumn)
+	}
+	return s, nil
+}
+
+// Draw returns the index of the next row drawn. Without replacement it fails
+// once every row has been drawn.
+func (s *DictionarySampler) Draw(ctx *Context) (int, error) {
+	if s.replacement {
+		if s.weights == nil {
+			return ctx.Rand.Intn(len(s.dict.rows)), nil
+		}
+		total := s.cumulative[len(s.cumulative)-1]
+		i := sort.SearchFloat64s(s.cumulative, ctx.Rand.Float64()*total)
+		// Skip rows of zero weight that share the running total
+		for i < len(s.weights)-1 && s.weights[i] == 0 {
+			i++
+		}
+		return i, nil
+	}
+
+	if s.order == nil {
+		s.order = s.drawOrder(ctx)
+	}
+	if s.drawn >= len(s.order) {
+		return 0, fmt.Errorf("dictionary rows are used up after %d draws without replacement; sample with replacement or generate fewer rows", len(s.order))
+	}
+	if s.weights == nil {
+		// Shuffle as rows are drawn
+		j := s.drawn + ctx.Rand.Intn(len(s.order)-s.drawn)
+		s.order[s.drawn], s.order[j] = s.order[j], s.order[s.drawn]
+	}
+	s.drawn++
+	return s.order[s.drawn-1], nil
+}
+
+// drawOrder lists the rows that can be drawn without replacement. Weighted
+// rows are sorted by u^(1/w) for a uniform u (Efraimidis and Spirakis), which
+// draws each next row with a probability proportional to its weight among
+// the rows left.
+func (s *DictionarySampler) drawOrder(ctx *Context) []int {
+	if s.weights == nil {
+		order := make([]int, len(s.dict.rows))
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	}
+
+	var order []int
+	keys := make([]float64, len(s.weights))
+	for i, w := range s.weights {
+		if w > 0 {
+			keys[i] = math.Log(ctx.Rand.Float64()) / w
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return keys[order[a]] > keys[order[b]]
+	})
+	return order
+}
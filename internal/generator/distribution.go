@@ -12,11 +12,17 @@ import (
 // DistributionGenerator generates values based on weighted distributions
 type DistributionGenerator struct {
 	config *schema.DistributionConfig
+	sorted []float64              // sorted values of an empirical distribution
+	length *DistributionGenerator // lengths of random empirical text
 }
 
 // NewDistributionGenerator creates a distribution-based generator
 func NewDistributionGenerator(config *schema.DistributionConfig) *DistributionGenerator {
-	return &DistributionGenerator{config: config}
+	g := &DistributionGenerator{config: config, sorted: empiricalValues(config)}
+	if config.Length != nil {
+		g.length = NewDistributionGenerator(config.Length)
+	}
+	return g
 }
 
 func (g *DistributionGenerator) Name() string {
@@ -24,6 +30,10 @@ func (g *DistributionGenerator) Name() string {
 }
 
 func (g *DistributionGenerator) Generate(ctx *Context) (interface{}, error) {
+	if g.config.NullRate > 0 && ctx.Rand.Float64() < g.config.NullRate {
+		return nil, nil
+	}
+
 	switch g.config.Type {
 	case "weighted":
 		return g.generateWeighted(ctx)
@@ -33,6 +43,8 @@ func (g *DistributionGenerator) Generate(ctx *Context) (interface{}, error) {
 		return g.generatePoisson(ctx)
 	case "zipf":
 		return g.generateZipf(ctx)
+	case "empirical":
+		return g.generateEmpirical(ctx)
 	default:
 		return nil, fmt.Errorf("unknown distribution type: %s", g.config.Type)
 	}
@@ -146,9 +158,55 @@ func (g *DistributionGenerator) generateZipf(ctx *Context) (interface{}, error)
 		max = int(toFloat64(g.config.Max))
 	}
 
+	return ZipfValue(ctx.Rand, *g.config.Alpha, min, max), nil
+}
+
+// generateEmpirical draws a number from observed values or histogram bins,
+// or text from the shares of observed values, with random strings of an
+// observed length for the values not listed
+func (g *DistributionGenerator) generateEmpirical(ctx *Context) (interface{}, error) {
+	if len(g.sorted) > 0 || len(g.config.Bins) > 0 {
+		return empiricalQuantile(g.config, g.sorted, ctx.Rand.Float64()), nil
+	}
+
+	if g.length != nil {
+		total := 0.0
+		for _, weight := range g.config.Weights {
+			total += toFloat64(weight)
+		}
+		if ctx.Rand.Float64() >= total {
+			length, err := g.length.Generate(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("invalid length distribution: %w", err)
+			}
+			n, _ := length.(float64)
+			return randomLetters(ctx, int(math.Max(0, math.Round(n)))), nil
+		}
+	}
+	if len(g.config.Weights) == 0 {
+		return nil, fmt.Errorf("empirical distribution needs 'values', 'bins' or 'weights'")
+	}
+	return g.generateWeighted(ctx)
+}
+
+// randomLetters returns a string of n random lower case letters
+func randomLetters(ctx *Context, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + ctx.Rand.Intn(26))
+	}
+	return string(b)
+}
+
+// ZipfValue draws an integer in [min, max] from a Zipf distribution with
+// exponent alpha (> 1); min is the most frequent value
+func ZipfValue(rng *rand.Rand, alpha float64, min, max int) int {
+	if max <= min {
+		return min
+	}
 	// Use Go's built-in Zipf generator
-	zipf := rand.NewZipf(ctx.Rand, *g.config.Alpha, 1.0, uint64(max-min))
-	return int(zipf.Uint64()) + min, nil
+	zipf := rand.NewZipf(rng, alpha, 1.0, uint64(max-min))
+	return int(zipf.Uint64()) + min
 }
 
 // weightedValue represents a value with its weight
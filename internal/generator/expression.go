@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"github.com/NhaLeTruc/datagen-cli/internal/expression"
+)
+
+// ExpressionGenerator derives a value from other columns of the current row
+type ExpressionGenerator struct {
+	expr *expression.Expression
+}
+
+// NewExpressionGenerator parses an expression such as "first_name || ' ' || last_name"
+func NewExpressionGenerator(source string) (*ExpressionGenerator, error) {
+	expr, err := expression.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return &ExpressionGenerator{expr: expr}, nil
+}
+
+// Generate evaluates the expression over ctx.RowData
+func (g *ExpressionGenerator) Generate(ctx *Context) (interface{}, error) {
+	return g.expr.Eval(ctx.RowData)
+}
+
+func (g *ExpressionGenerator) Name() string {
+	return "expression"
+}
+
+// Columns returns the columns the expression reads
+func (g *ExpressionGenerator) Columns() []string {
+	return g.expr.Columns()
+}
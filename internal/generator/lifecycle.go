@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// LifecycleGenerator walks a status column through the states of a lifecycle,
+// timing each transition with the dwell distribution of the state it leaves
+type LifecycleGenerator struct {
+	lifecycle *schema.Lifecycle
+	dwell     map[string][]float64 // sorted values of empirical dwell distributions
+}
+
+// NewLifecycleGenerator creates a generator for a lifecycle
+func NewLifecycleGenerator(lifecycle *schema.Lifecycle) (*LifecycleGenerator, error) {
+	if errs := lifecycle.Validate(); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	dwell := make(map[string][]float64)
+	for name, state := range lifecycle.States {
+		if state.Dwell != nil {
+			dwell[name] = empiricalValues(state.Dwell)
+		}
+	}
+	return &LifecycleGenerator{lifecycle: lifecycle, dwell: dwell}, nil
+}
+
+func (g *LifecycleGenerator) Name() string {
+	return "lifecycle"
+}
+
+// Generate returns the state the row ends in
+func (g *LifecycleGenerator) Generate(ctx *Context) (interface{}, error) {
+	state, _, err := g.Walk(ctx)
+	return state, err
+}
+
+// Walk moves the row from the initial state until it stays in a state,
+// returning that state and the value of every state timestamp column: the
+// time the state was entered, or nil for states never reached. Without a
+// start time (NULL), all timestamps are nil.
+func (g *LifecycleGenerator) Walk(ctx *Context) (string, map[string]interface{}, error) {
+	var at *time.Time
+	switch start, _ := conditionValue(ctx, g.lifecycle.Start); v := start.(type) {
+	case time.Time:
+		at = &v
+	case nil:
+	default:
+		return "", nil, fmt.Errorf("lifecycle start %s is not a timestamp: %v", g.lifecycle.Start, start)
+	}
+
+	timestamps := make(map[string]interface{})
+	for _, state := range g.lifecycle.States {
+		if state.Timestamp != "" {
+			timestamps[state.Timestamp] = nil
+		}
+	}
+
+	current := g.lifecycle.Initial
+	for {
+		state := g.lifecycle.States[current]
+		if state.Timestamp != "" && at != nil {
+			timestamps[state.Timestamp] = *at
+		}
+
+		next := g.next(ctx, state)
+		if next == "" {
+			return current, timestamps, nil
+		}
+		if at != nil {
+			hours := math.Max(0, marginalQuantile(state.Dwell, g.dwell[current], ctx.Rand.NormFloat64()))
+			entered := at.Add(time.Duration(hours * float64(time.Hour))).Truncate(time.Second)
+			at = &entered
+		}
+		current = next
+	}
+}
+
+// next picks the state a row moves on to, or "" when it stays
+func (g *LifecycleGenerator) next(ctx *Context, state *schema.LifecycleState) string {
+	r := ctx.Rand.Float64()
+	for _, name := range state.NextStates() {
+		if r < state.Transitions[name] {
+			return name
+		}
+		r -= state.Transitions[name]
+	}
+	return ""
+}
@@ -8,16 +8,27 @@ import (
 
 // RulesGenerator generates values based on conditional business rules
 type RulesGenerator struct {
-	rules        []*schema.BusinessRule
+	rules         []*schema.BusinessRule
+	conditions    []*schema.Condition
 	baseGenerator Generator // Fallback generator if no rules match
 }
 
 // NewRulesGenerator creates a rules-based generator
-func NewRulesGenerator(rules []*schema.BusinessRule, baseGen Generator) *RulesGenerator {
+func NewRulesGenerator(rules []*schema.BusinessRule, baseGen Generator) (*RulesGenerator, error) {
+	conditions := make([]*schema.Condition, len(rules))
+	for i, rule := range rules {
+		cond, err := schema.ParseCondition(rule.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: invalid condition: %w", i+1, err)
+		}
+		conditions[i] = cond
+	}
+
 	return &RulesGenerator{
-		rules:        rules,
+		rules:         rules,
+		conditions:    conditions,
 		baseGenerator: baseGen,
-	}
+	}, nil
 }
 
 func (g *RulesGenerator) Name() string {
@@ -25,16 +36,11 @@ func (g *RulesGenerator) Name() string {
 }
 
 func (g *RulesGenerator) Generate(ctx *Context) (interface{}, error) {
-	// Evaluate rules in order until one matches
-	for _, rule := range g.rules {
-		if g.evaluateCondition(ctx, rule.Condition) {
-			// Apply the "then" action
-			return g.applyAction(ctx, rule.Then)
-		}
+	if value, applied, err := g.Apply(ctx); applied || err != nil {
+		return value, err
 	}
 
-	// If no rule matched and there's an else, use it
-	// Or fall back to base generator
+	// No rule matched: fall back to base generator
 	if g.baseGenerator != nil {
 		return g.baseGenerator.Generate(ctx)
 	}
@@ -42,27 +48,34 @@ func (g *RulesGenerator) Generate(ctx *Context) (interface{}, error) {
 	return nil, fmt.Errorf("no matching rule and no base generator")
 }
 
-// evaluateCondition checks if a condition matches the current row context
-func (g *RulesGenerator) evaluateCondition(ctx *Context, condition map[string]interface{}) bool {
-	if len(condition) == 0 {
-		return true // Empty condition always matches
-	}
-
-	// Check each condition field
-	for field, expectedValue := range condition {
-		// Get the actual value from the current row context
-		actualValue, exists := ctx.RowData[field]
-		if !exists {
-			return false // Field doesn't exist in row
+// Apply evaluates the rules in order and applies the "then" of the first one
+// that matches. A rule with an "else" ends the evaluation either way. It
+// reports false when no rule applied.
+func (g *RulesGenerator) Apply(ctx *Context) (interface{}, bool, error) {
+	for i, rule := range g.rules {
+		if g.conditions[i].Matches(func(field string) (interface{}, bool) { return conditionValue(ctx, field) }) {
+			value, err := g.applyAction(ctx, rule.Then)
+			return value, true, err
 		}
-
-		// Compare values (simplified comparison, could be extended)
-		if !valuesMatch(actualValue, expectedValue) {
-			return false
+		if rule.Else != nil {
+			value, err := g.applyAction(ctx, rule.Else)
+			return value, true, err
 		}
 	}
+	return nil, false, nil
+}
 
-	return true // All conditions matched
+// conditionValue reads a column of the current row, or of a parent row for "table.column"
+func conditionValue(ctx *Context, field string) (interface{}, bool) {
+	table, column := schema.ConditionReference(field)
+	if table == "" {
+		value, ok := ctx.RowData[column]
+		return value, ok
+	}
+	if ctx.ParentValue == nil {
+		return nil, false
+	}
+	return ctx.ParentValue(table, column)
 }
 
 // applyAction applies the action specified in a rule
@@ -83,22 +96,29 @@ func (g *RulesGenerator) applyAction(ctx *Context, action map[string]interface{}
 		return generateInRange(ctx, min, max)
 	}
 
+	if _, ok := action["distribution"]; ok {
+		config, err := schema.ActionDistribution(action)
+		if err != nil {
+			return nil, err
+		}
+		return NewDistributionGenerator(config).Generate(ctx)
+	}
+
+	if weights, ok := action["weights"].(map[string]interface{}); ok {
+		// Weighted enum: pick one of the values
+		config := &schema.DistributionConfig{Type: "weighted", Weights: weights}
+		return NewDistributionGenerator(config).Generate(ctx)
+	}
+
 	if generator, ok := action["generator"].(string); ok {
 		// Use a specific generator
-		config := action["config"].(map[string]interface{})
+		config, _ := action["config"].(map[string]interface{})
 		return generateWithConfig(ctx, generator, config)
 	}
 
 	return nil, fmt.Errorf("invalid action format")
 }
 
-// valuesMatch compares two values for equality
-// Supports string, numeric, and boolean comparisons
-func valuesMatch(actual, expected interface{}) bool {
-	// Simple type-agnostic comparison
-	return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", expected)
-}
-
 // generateInRange generates a value within a specified range
 func generateInRange(ctx *Context, min, max interface{}) (interface{}, error) {
 	// Convert to float64 for range calculation
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"strings"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 )
@@ -251,6 +252,9 @@ func NewCreatedAtGenerator() *CreatedAtGenerator {
 
 func (g *CreatedAtGenerator) Generate(ctx *Context) (interface{}, error) {
 	faker := gofakeit.New(ctx.Rand.Int63())
+	if ctx.TimeBounds != nil {
+		return faker.DateRange(ctx.TimeBounds.Range(365 * 24 * time.Hour)), nil
+	}
 	return faker.DateRange(
 		faker.Date().AddDate(-1, 0, 0),
 		faker.Date(),
@@ -269,6 +273,9 @@ func NewUpdatedAtGenerator() *UpdatedAtGenerator {
 
 func (g *UpdatedAtGenerator) Generate(ctx *Context) (interface{}, error) {
 	faker := gofakeit.New(ctx.Rand.Int63())
+	if ctx.TimeBounds != nil {
+		return faker.DateRange(ctx.TimeBounds.Range(90 * 24 * time.Hour)), nil
+	}
 	return faker.DateRange(
 		faker.Date().AddDate(0, -3, 0),
 		faker.Date(),
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"math/rand"
+	"time"
+)
+
+// TimeBounds restricts the timestamps a generator produces, e.g. to keep
+// updated_at after created_at. A zero After or Before leaves that side open.
+type TimeBounds struct {
+	After  time.Time
+	Before time.Time
+}
+
+// Range returns the interval to generate in, extending an open side by window
+func (b *TimeBounds) Range(window time.Duration) (time.Time, time.Time) {
+	start, end := b.After, b.Before
+	if start.IsZero() {
+		start = end.Add(-window)
+	}
+	if end.IsZero() {
+		end = start.Add(window)
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
+
+// Contains reports whether t lies within the bounds
+func (b *TimeBounds) Contains(t time.Time) bool {
+	if !b.After.IsZero() && t.Before(b.After) {
+		return false
+	}
+	if !b.Before.IsZero() && t.After(b.Before) {
+		return false
+	}
+	return true
+}
+
+// pick returns a random time within the bounds
+func (b *TimeBounds) pick(rng *rand.Rand, window time.Duration) time.Time {
+	start, end := b.Range(window)
+	span := end.Sub(start)
+	if span <= 0 {
+		return start
+	}
+	return start.Add(time.Duration(rng.Int63n(int64(span))))
+}
@@ -0,0 +1,313 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// Default counts of the text modes
+var (
+	defaultWordCount      = schema.CountRange{Min: 3, Max: 8}
+	defaultSentenceCount  = schema.CountRange{Min: 1, Max: 3}
+	defaultParagraphCount = schema.CountRange{Min: 1, Max: 3}
+	defaultReviewCount    = schema.CountRange{Min: 2, Max: 4}
+	defaultSentenceWords  = schema.CountRange{Min: 6, Max: 14}
+	defaultParagraphSize  = schema.CountRange{Min: 3, Max: 6}
+	defaultSentiment      = map[string]float64{"positive": 0.6, "neutral": 0.25, "negative": 0.15}
+)
+
+// StructuredTextGenerator writes text with a realistic structure (see
+// schema.TextConfig), cut at a word to fit the column's length
+type StructuredTextGenerator struct {
+	config *schema.TextConfig
+	chain  *MarkovChain
+}
+
+// NewStructuredTextGenerator creates a text generator. Markov text needs the
+// chain trained on its corpus.
+func NewStructuredTextGenerator(config *schema.TextConfig, chain *MarkovChain) (*StructuredTextGenerator, error) {
+	if config.Mode == "markov" && chain == nil {
+		return nil, fmt.Errorf("markov text needs a chain trained on its corpus")
+	}
+	return &StructuredTextGenerator{config: config, chain: chain}, nil
+}
+
+func (g *StructuredTextGenerator) Name() string {
+	return "text"
+}
+
+func (g *StructuredTextGenerator) Generate(ctx *Context) (interface{}, error) {
+	var text string
+	switch g.config.Mode {
+	case "words":
+		text = strings.Join(loremSentenceWords(ctx, pick(ctx, g.config.Count, defaultWordCount)), " ")
+	case "paragraphs":
+		paragraphs := make([]string, pick(ctx, g.config.Count, defaultParagraphCount))
+		for i := range paragraphs {
+			paragraphs[i] = g.loremSentences(ctx, pick(ctx, g.config.Sentences, defaultParagraphSize))
+		}
+		text = strings.Join(paragraphs, "\n\n")
+	case "markov":
+		sentences := make([]string, pick(ctx, g.config.Count, defaultSentenceCount))
+		for i := range sentences {
+			sentences[i] = g.chain.Sentence(ctx)
+		}
+		text = strings.Join(sentences, " ")
+	case "product_title":
+		text = productTitle(ctx)
+	case "review":
+		text = g.review(ctx)
+	case "support_ticket":
+		text = supportTicket(ctx)
+	default:
+		text = g.loremSentences(ctx, pick(ctx, g.config.Count, defaultSentenceCount))
+	}
+	return truncateText(text, g.config.MaxLength), nil
+}
+
+// loremSentences writes sentences of lorem ipsum words
+func (g *StructuredTextGenerator) loremSentences(ctx *Context, n int) string {
+	sentences := make([]string, n)
+	for i := range sentences {
+		words := loremSentenceWords(ctx, pick(ctx, g.config.Words, defaultSentenceWords))
+		for j := 0; j < len(words)-2; j++ {
+			if ctx.Rand.Intn(10) == 0 {
+				words[j] += ","
+			}
+		}
+		sentences[i] = capitalize(strings.Join(words, " ")) + "."
+	}
+	return strings.Join(sentences, " ")
+}
+
+func loremSentenceWords(ctx *Context, n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = loremWords[ctx.Rand.Intn(len(loremWords))]
+	}
+	return words
+}
+
+// productTitle writes a product title: brand, adjective, product and,
+// sometimes, a variant
+func productTitle(ctx *Context) string {
+	p := products[ctx.Rand.Intn(len(products))]
+	title := fmt.Sprintf("%s %s %s", choose(ctx, productBrands), choose(ctx, productAdjectives), p.name)
+	if ctx.Rand.Intn(5) < 3 {
+		title += ", " + choose(ctx, p.variants)
+	}
+	return title
+}
+
+// review writes a review of a product: distinct sentences of one sentiment
+func (g *StructuredTextGenerator) review(ctx *Context) string {
+	weights := g.config.Sentiment
+	if weights == nil {
+		weights = defaultSentiment
+	}
+	names := make([]string, 0, len(weights))
+	total := 0.0
+	for name, w := range weights {
+		names = append(names, name)
+		total += w
+	}
+	sort.Strings(names)
+	sentiment := names[len(names)-1]
+	r := ctx.Rand.Float64() * total
+	for _, name := range names {
+		if r < weights[name] {
+			sentiment = name
+			break
+		}
+		r -= weights[name]
+	}
+
+	product := strings.ToLower(products[ctx.Rand.Intn(len(products))].name)
+	candidates := reviewSentences[sentiment]
+	order := ctx.Rand.Perm(len(candidates))
+	n := min(pick(ctx, g.config.Count, defaultReviewCount), len(candidates))
+	sentences := make([]string, n)
+	for i := range sentences {
+		sentences[i] = fillSlots(ctx, candidates[order[i]], map[string][]string{
+			"product": {product},
+			"aspect":  reviewAspects,
+		})
+	}
+	return strings.Join(sentences, " ")
+}
+
+// supportTicket writes a support request: greeting, issue, detail, request
+// and a signed closing
+func supportTicket(ctx *Context) string {
+	parts := make([]string, len(ticketParts))
+	for i, options := range ticketParts {
+		parts[i] = fillSlots(ctx, choose(ctx, options), ticketSlots)
+	}
+	names := append(append([]string(nil), maleFirstNames...), femaleFirstNames...)
+	last := len(parts) - 1
+	return fmt.Sprintf("%s\n%s\n%s\n%s", parts[0], strings.Join(parts[1:last], " "), parts[last], choose(ctx, names))
+}
+
+// fillSlots replaces each {slot} of a template with one of its values;
+// {order} becomes an order number and {code} an error code
+func fillSlots(ctx *Context, template string, slots map[string][]string) string {
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(template, '{')
+		end := strings.IndexByte(template, '}')
+		if open < 0 || end < open {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:open])
+		switch slot := template[open+1 : end]; slot {
+		case "order":
+			b.WriteString(fillPattern(ctx, "1#####"))
+		case "code":
+			b.WriteString(fillPattern(ctx, "E-###"))
+		default:
+			b.WriteString(choose(ctx, slots[slot]))
+		}
+		template = template[end+1:]
+	}
+}
+
+// MarkovChain predicts the next word of a sentence from the words before it,
+// as learnt from a corpus
+type MarkovChain struct {
+	order  int
+	starts [][]string          // opening words of the corpus sentences
+	next   map[string][]string // words following each state, once per occurrence
+}
+
+// LoadMarkovChain trains a chain on a corpus file
+func LoadMarkovChain(path string, order int) (*MarkovChain, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corpus: %w", err)
+	}
+	defer f.Close()
+
+	chain, err := NewMarkovChain(f, order)
+	if err != nil {
+		return nil, fmt.Errorf("corpus %s: %w", path, err)
+	}
+	return chain, nil
+}
+
+// NewMarkovChain trains a chain on a corpus, read as words split into
+// sentences ending with '.', '!' or '?'. Each state is the order words before
+// the next one.
+func NewMarkovChain(r io.Reader, order int) (*MarkovChain, error) {
+	if order < 1 {
+		return nil, fmt.Errorf("markov order must be at least 1")
+	}
+	chain := &MarkovChain{order: order, next: make(map[string][]string)}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	scanner.Split(bufio.ScanWords)
+	var sentence []string
+	flush := func() {
+		if len(sentence) == 0 {
+			return
+		}
+		chain.starts = append(chain.starts, sentence[:min(order, len(sentence))])
+		for i := order; i < len(sentence); i++ {
+			state := strings.Join(sentence[i-order:i], " ")
+			chain.next[state] = append(chain.next[state], sentence[i])
+		}
+		sentence = nil
+	}
+	for scanner.Scan() {
+		sentence = append(sentence, scanner.Text())
+		if endsSentence(scanner.Text()) {
+			flush()
+		}
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read corpus: %w", err)
+	}
+	if len(chain.starts) == 0 {
+		return nil, fmt.Errorf("corpus has no text")
+	}
+	return chain, nil
+}
+
+// maxSentenceWords stops sentences of corpora without sentence punctuation
+const maxSentenceWords = 60
+
+// Sentence writes a sentence by walking the chain from the opening words of
+// a corpus sentence
+func (m *MarkovChain) Sentence(ctx *Context) string {
+	start := m.starts[ctx.Rand.Intn(len(m.starts))]
+	words := append([]string(nil), start...)
+	for len(words) >= m.order && len(words) < maxSentenceWords && !endsSentence(words[len(words)-1]) {
+		options := m.next[strings.Join(words[len(words)-m.order:], " ")]
+		if len(options) == 0 {
+			break
+		}
+		words = append(words, options[ctx.Rand.Intn(len(options))])
+	}
+
+	sentence := strings.Join(words, " ")
+	if !endsSentence(sentence) {
+		sentence = strings.TrimRight(sentence, ",;:") + "."
+	}
+	return sentence
+}
+
+// endsSentence reports whether a word ends a sentence, allowing for closing
+// quotes and brackets after the punctuation
+func endsSentence(word string) bool {
+	word = strings.TrimRight(word, "\"')]”’")
+	return strings.HasSuffix(word, ".") || strings.HasSuffix(word, "!") || strings.HasSuffix(word, "?")
+}
+
+// truncateText cuts text to at most max characters (0 for no limit), at the
+// end of a word when there is one
+func truncateText(text string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)
+	cut := max
+	if !unicode.IsSpace(runes[max]) {
+		for i := max - 1; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+}
+
+// pick draws a count from a range, or from the mode's default range
+func pick(ctx *Context, r *schema.CountRange, def schema.CountRange) int {
+	if r == nil {
+		r = &def
+	}
+	return r.Min + ctx.Rand.Intn(r.Max-r.Min+1)
+}
+
+func choose(ctx *Context, options []string) string {
+	return options[ctx.Rand.Intn(len(options))]
+}
+
+// capitalize upper cases the first letter of a sentence
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,119 @@
+package generator
+
+// loremWords are the words of lorem ipsum text
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+	"ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+	"ex", "ea", "commodo", "consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "eu", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat",
+	"cupidatat", "non", "proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim",
+	"id", "est", "laborum", "praesent", "vitae", "arcu", "tincidunt", "morbi", "tristique", "senectus",
+}
+
+// product is a kind of product with the variants of its titles
+type product struct {
+	name     string
+	variants []string
+}
+
+var (
+	productBrands = []string{
+		"Nordlys", "Acme", "Kestrel", "Lumen", "Brightwave", "Oakridge", "Veloce", "Tidewater", "Summit", "Pebble & Co",
+	}
+	productAdjectives = []string{
+		"Premium", "Classic", "Ultra-Light", "Wireless", "Compact", "Ergonomic", "Waterproof", "Organic", "Vintage", "Smart",
+		"Heavy-Duty", "Portable",
+	}
+	products = []product{
+		{"Backpack", []string{"20L", "30L", "Black", "Navy"}},
+		{"Headphones", []string{"Black", "White", "Noise Cancelling"}},
+		{"Coffee Maker", []string{"12-Cup", "Stainless Steel"}},
+		{"Running Shoes", []string{"Men's", "Women's", "Size 9", "Size 10"}},
+		{"Desk Lamp", []string{"LED", "Matte Black"}},
+		{"Water Bottle", []string{"750ml", "1L"}},
+		{"Yoga Mat", []string{"6mm", "Non-Slip"}},
+		{"Office Chair", []string{"Mesh", "Grey"}},
+		{"Phone Case", []string{"Clear", "Leather"}},
+		{"Bluetooth Speaker", []string{"20W", "Blue"}},
+		{"T-Shirt", []string{"Cotton", "Size M", "Size L"}},
+		{"Chef's Knife", []string{"8-inch", "Damascus Steel"}},
+	}
+)
+
+// reviewSentences are the sentences of reviews by sentiment; {product} is a
+// product name and {aspect} something a review comments on
+var (
+	reviewAspects = []string{
+		"quality", "price", "delivery", "packaging", "battery life", "build", "design", "customer service", "size", "comfort",
+	}
+	reviewSentences = map[string][]string{
+		"positive": {
+			"Absolutely love this {product}!",
+			"The {aspect} is excellent.",
+			"Works exactly as described.",
+			"Great value for the price.",
+			"Arrived quickly and well packaged.",
+			"I would definitely buy this again.",
+			"Highly recommend it to anyone looking for a {product}.",
+			"The {aspect} exceeded my expectations.",
+		},
+		"neutral": {
+			"The {product} does what it says.",
+			"The {aspect} is okay, nothing special.",
+			"Delivery took a bit longer than expected.",
+			"Decent for the price.",
+			"It works, but the {aspect} could be better.",
+			"Not bad overall.",
+			"About what I expected from a {product}.",
+		},
+		"negative": {
+			"Very disappointed with this {product}.",
+			"The {aspect} is poor.",
+			"Stopped working after two weeks.",
+			"Not worth the money.",
+			"Arrived damaged and customer service was unhelpful.",
+			"I would not recommend it.",
+			"The {aspect} is much worse than the pictures suggest.",
+			"Returned it the next day.",
+		},
+	}
+)
+
+// Parts of support tickets, in order; {slot}s are filled from ticketSlots,
+// {order} with an order number and {code} with an error code
+var (
+	ticketParts = [][]string{
+		{"Hi,", "Hello,", "Hi support team,", "Good morning,"},
+		{
+			"I can't log in to my account since {when}.",
+			"The {feature} page shows error {code} when I {action}.",
+			"I was charged twice for order #{order}.",
+			"My order #{order} hasn't arrived yet.",
+			"The app crashes whenever I {action}.",
+			"I didn't receive the password reset email.",
+			"The export to {format} is missing data since {when}.",
+		},
+		{
+			"I've already tried clearing my cache and restarting.",
+			"This started after {when}.",
+			"It happens on both {browser} and the mobile app.",
+			"Several colleagues have the same problem.",
+			"I've attached a screenshot.",
+		},
+		{
+			"Could you please look into this?",
+			"Can you help me resolve this as soon as possible?",
+			"Please let me know what I should do.",
+			"Could you issue a refund?",
+		},
+		{"Thanks,", "Thank you,", "Best regards,", "Kind regards,"},
+	}
+	ticketSlots = map[string][]string{
+		"when":    {"this morning", "yesterday", "last Friday", "the last update"},
+		"feature": {"billing", "dashboard", "settings", "reports", "checkout"},
+		"action":  {"upload a file", "save my changes", "open the settings", "check out", "export a report"},
+		"format":  {"CSV", "PDF", "Excel"},
+		"browser": {"Chrome", "Firefox", "Safari", "Edge"},
+	}
+)
@@ -24,16 +24,21 @@ func NewTimeSeriesGenerator(startTime, endTime time.Time, interval time.Duration
 }
 
 func (g *TimeSeriesGenerator) Generate(ctx *Context) (interface{}, error) {
+	var timestamp time.Time
 	switch g.pattern {
-	case "uniform":
-		return g.generateUniform(ctx), nil
 	case "business_hours":
-		return g.generateBusinessHours(ctx), nil
+		timestamp = g.generateBusinessHours(ctx)
 	case "daily_peak":
-		return g.generateDailyPeak(ctx), nil
+		timestamp = g.generateDailyPeak(ctx)
 	default:
-		return g.generateUniform(ctx), nil
+		timestamp = g.generateUniform(ctx)
+	}
+
+	// Values outside the temporal bounds are redrawn within them
+	if b := ctx.TimeBounds; b != nil && !b.Contains(timestamp) {
+		timestamp = b.pick(ctx.Rand, g.endTime.Sub(g.startTime))
 	}
+	return timestamp, nil
 }
 
 func (g *TimeSeriesGenerator) generateUniform(ctx *Context) time.Time {
@@ -0,0 +1,215 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// TupleGenerator generates the values of several related columns at once,
+// keyed by role (see schema.TupleRoles)
+type TupleGenerator interface {
+	Name() string
+	GenerateTuple(ctx *Context) (map[string]interface{}, error)
+}
+
+// NewTupleGenerator creates the multi-column generator of a tuple
+func NewTupleGenerator(tuple *schema.Tuple) (TupleGenerator, error) {
+	switch tuple.Generator {
+	case "address", "geo":
+		return newAddressTupleGenerator(tuple.Generator, tuple.Countries)
+	case "person":
+		return &PersonTupleGenerator{}, nil
+	case "credit_card":
+		return &CreditCardTupleGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tuple generator: %s", tuple.Generator)
+	}
+}
+
+// AddressTupleGenerator draws a street, city, region, postal code and
+// coordinates of one country
+type AddressTupleGenerator struct {
+	name    string
+	locales []*locale
+}
+
+func newAddressTupleGenerator(name string, countries []string) (*AddressTupleGenerator, error) {
+	g := &AddressTupleGenerator{name: name}
+	if len(countries) == 0 {
+		g.locales = locales
+		return g, nil
+	}
+
+	for _, code := range countries {
+		var found *locale
+		for _, l := range locales {
+			if strings.EqualFold(l.code, code) {
+				found = l
+			}
+		}
+		if found == nil {
+			known := make([]string, len(locales))
+			for i, l := range locales {
+				known[i] = l.code
+			}
+			return nil, fmt.Errorf("no address data for country %q (use %s)", code, strings.Join(known, ", "))
+		}
+		g.locales = append(g.locales, found)
+	}
+	return g, nil
+}
+
+func (g *AddressTupleGenerator) Name() string {
+	return g.name
+}
+
+func (g *AddressTupleGenerator) GenerateTuple(ctx *Context) (map[string]interface{}, error) {
+	l := g.locales[ctx.Rand.Intn(len(g.locales))]
+	city := l.cities[ctx.Rand.Intn(len(l.cities))]
+
+	street := l.streets[ctx.Rand.Intn(len(l.streets))]
+	number := 1 + ctx.Rand.Intn(250)
+	line := fmt.Sprintf("%s %d", street, number)
+	if l.numberFirst {
+		line = fmt.Sprintf("%d %s", number, street)
+	}
+
+	// Within about 10km of the city centre
+	lat := city.lat + (ctx.Rand.Float64()-0.5)*0.18
+	lng := city.lng + (ctx.Rand.Float64()-0.5)*0.18/math.Cos(city.lat*math.Pi/180)
+
+	return map[string]interface{}{
+		"street":       line,
+		"city":         city.name,
+		"state":        city.region,
+		"postal_code":  fillPattern(ctx, city.postal),
+		"country":      l.country,
+		"country_code": l.code,
+		"latitude":     math.Round(lat*1e6) / 1e6,
+		"longitude":    math.Round(lng*1e6) / 1e6,
+	}, nil
+}
+
+// fillPattern replaces '#' with random digits and '?' with random upper case letters
+func fillPattern(ctx *Context, pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '#':
+			b.WriteByte(byte('0' + ctx.Rand.Intn(10)))
+		case '?':
+			b.WriteByte(byte('A' + ctx.Rand.Intn(26)))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// PersonTupleGenerator draws a person whose first name matches their gender
+// and whose email and username are made of their name
+type PersonTupleGenerator struct{}
+
+func (g *PersonTupleGenerator) Name() string {
+	return "person"
+}
+
+func (g *PersonTupleGenerator) GenerateTuple(ctx *Context) (map[string]interface{}, error) {
+	gender, names := "male", maleFirstNames
+	if ctx.Rand.Intn(2) == 0 {
+		gender, names = "female", femaleFirstNames
+	}
+	first := names[ctx.Rand.Intn(len(names))]
+	last := lastNames[ctx.Rand.Intn(len(lastNames))]
+
+	local := asciiLower(first) + "." + asciiLower(last)
+	if ctx.Rand.Intn(2) == 0 {
+		local += fmt.Sprintf("%d", 1+ctx.Rand.Intn(99))
+	}
+	username := asciiLower(first[:1]+last) + fmt.Sprintf("%d", ctx.Rand.Intn(1000))
+
+	return map[string]interface{}{
+		"first_name": first,
+		"last_name":  last,
+		"full_name":  first + " " + last,
+		"gender":     gender,
+		"email":      local + "@" + emailDomains[ctx.Rand.Intn(len(emailDomains))],
+		"username":   username,
+	}, nil
+}
+
+// asciiLower lower cases a name for use in an email address, dropping
+// accents and anything but letters and digits
+func asciiLower(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case r == 'ü':
+			b.WriteString("ue")
+		case r == 'é' || r == 'è':
+			b.WriteByte('e')
+		}
+	}
+	return b.String()
+}
+
+// cardExpiryYear is the first year card expiry dates fall in. It is fixed,
+// rather than taken from the clock, so that output only depends on the seed.
+const cardExpiryYear = 2026
+
+// CreditCardTupleGenerator draws a card number valid for its brand (prefix,
+// length and Luhn check digit) with an expiry date and security code
+type CreditCardTupleGenerator struct{}
+
+func (g *CreditCardTupleGenerator) Name() string {
+	return "credit_card"
+}
+
+func (g *CreditCardTupleGenerator) GenerateTuple(ctx *Context) (map[string]interface{}, error) {
+	brand := cardBrands[ctx.Rand.Intn(len(cardBrands))]
+
+	digits := []byte(brand.prefixes[ctx.Rand.Intn(len(brand.prefixes))])
+	for len(digits) < brand.length-1 {
+		digits = append(digits, byte('0'+ctx.Rand.Intn(10)))
+	}
+	digits = append(digits, luhnDigit(digits))
+
+	year := cardExpiryYear + ctx.Rand.Intn(6)
+	month := time.Month(1 + ctx.Rand.Intn(12))
+	cvv := make([]byte, brand.cvv)
+	for i := range cvv {
+		cvv[i] = byte('0' + ctx.Rand.Intn(10))
+	}
+
+	return map[string]interface{}{
+		"brand":  brand.name,
+		"number": string(digits),
+		"expiry": fmt.Sprintf("%02d/%02d", int(month), year%100),
+		// Cards are valid until the end of their expiry month
+		"expiry_date": time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC),
+		"cvv":         string(cvv),
+	}, nil
+}
+
+// luhnDigit returns the check digit that makes a number pass the Luhn check
+func luhnDigit(digits []byte) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
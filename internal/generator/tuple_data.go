@@ -0,0 +1,137 @@
+package generator
+
+// locale holds the reference data addresses of one country are drawn from
+type locale struct {
+	code        string // ISO 3166-1 alpha-2
+	country     string
+	numberFirst bool // "12 Main Street" rather than "Hauptstraße 12"
+	streets     []string
+	cities      []localeCity
+}
+
+// localeCity is a city with its region, the pattern of its postal codes
+// ('#' a digit, '?' an upper case letter) and its coordinates
+type localeCity struct {
+	name     string
+	region   string
+	postal   string
+	lat, lng float64
+}
+
+var locales = []*locale{
+	{
+		code: "US", country: "United States", numberFirst: true,
+		streets: []string{"Main Street", "Oak Avenue", "Maple Drive", "Park Avenue", "Washington Street", "Lake Road", "Cedar Lane", "Elm Street"},
+		cities: []localeCity{
+			{"New York", "NY", "100##", 40.7128, -74.0060},
+			{"Los Angeles", "CA", "900##", 34.0522, -118.2437},
+			{"Chicago", "IL", "606##", 41.8781, -87.6298},
+			{"Houston", "TX", "770##", 29.7604, -95.3698},
+			{"Seattle", "WA", "981##", 47.6062, -122.3321},
+		},
+	},
+	{
+		code: "CA", country: "Canada", numberFirst: true,
+		streets: []string{"King Street", "Queen Street", "Yonge Street", "Rue Sainte-Catherine", "Granville Street", "Bank Street"},
+		cities: []localeCity{
+			{"Toronto", "ON", "M#? #?#", 43.6532, -79.3832},
+			{"Montreal", "QC", "H#? #?#", 45.5017, -73.5673},
+			{"Vancouver", "BC", "V#? #?#", 49.2827, -123.1207},
+		},
+	},
+	{
+		code: "GB", country: "United Kingdom", numberFirst: true,
+		streets: []string{"High Street", "Station Road", "Church Lane", "Victoria Road", "Green Lane", "Mill Lane"},
+		cities: []localeCity{
+			{"London", "England", "SW# #??", 51.5074, -0.1278},
+			{"Manchester", "England", "M# #??", 53.4808, -2.2426},
+			{"Edinburgh", "Scotland", "EH# #??", 55.9533, -3.1883},
+		},
+	},
+	{
+		code: "FR", country: "France", numberFirst: true,
+		streets: []string{"Rue de la République", "Avenue Victor Hugo", "Rue Pasteur", "Boulevard Voltaire", "Rue du Moulin", "Place de la Mairie"},
+		cities: []localeCity{
+			{"Paris", "Île-de-France", "750##", 48.8566, 2.3522},
+			{"Lyon", "Auvergne-Rhône-Alpes", "6900#", 45.7640, 4.8357},
+			{"Marseille", "Provence-Alpes-Côte d'Azur", "130##", 43.2965, 5.3698},
+		},
+	},
+	{
+		code: "DE", country: "Germany",
+		streets: []string{"Hauptstraße", "Bahnhofstraße", "Schillerstraße", "Gartenstraße", "Lindenstraße", "Goethestraße"},
+		cities: []localeCity{
+			{"Berlin", "Berlin", "10###", 52.5200, 13.4050},
+			{"Munich", "Bavaria", "80###", 48.1351, 11.5820},
+			{"Hamburg", "Hamburg", "20###", 53.5511, 9.9937},
+		},
+	},
+	{
+		code: "ES", country: "Spain",
+		streets: []string{"Calle Mayor", "Calle Real", "Avenida de la Constitución", "Calle del Sol", "Plaza de España"},
+		cities: []localeCity{
+			{"Madrid", "Madrid", "280##", 40.4168, -3.7038},
+			{"Barcelona", "Catalonia", "080##", 41.3874, 2.1686},
+			{"Valencia", "Valencia", "460##", 39.4699, -0.3763},
+		},
+	},
+	{
+		code: "JP", country: "Japan", numberFirst: true,
+		streets: []string{"Ginza", "Shibuya", "Umeda", "Namba", "Sakae", "Tenjin"},
+		cities: []localeCity{
+			{"Tokyo", "Tokyo", "1##-####", 35.6762, 139.6503},
+			{"Osaka", "Osaka", "5##-####", 34.6937, 135.5023},
+			{"Nagoya", "Aichi", "4##-####", 35.1815, 136.9066},
+		},
+	},
+	{
+		code: "AU", country: "Australia", numberFirst: true,
+		streets: []string{"George Street", "Collins Street", "Queen Street", "Hay Street", "King William Street"},
+		cities: []localeCity{
+			{"Sydney", "NSW", "20##", -33.8688, 151.2093},
+			{"Melbourne", "VIC", "30##", -37.8136, 144.9631},
+			{"Brisbane", "QLD", "40##", -27.4698, 153.0251},
+		},
+	},
+	{
+		code: "BR", country: "Brazil",
+		streets: []string{"Rua das Flores", "Avenida Paulista", "Rua São João", "Avenida Brasil", "Rua XV de Novembro"},
+		cities: []localeCity{
+			{"São Paulo", "SP", "01###-###", -23.5505, -46.6333},
+			{"Rio de Janeiro", "RJ", "20###-###", -22.9068, -43.1729},
+			{"Belo Horizonte", "MG", "30###-###", -19.9167, -43.9345},
+		},
+	},
+}
+
+var (
+	maleFirstNames = []string{
+		"James", "John", "Robert", "Michael", "David", "William", "Thomas", "Daniel", "Matthew", "Lucas",
+		"Hiroshi", "Pierre", "Hans", "Carlos", "Liam", "Noah", "Oliver", "Mateo", "Ethan", "Samuel",
+	}
+	femaleFirstNames = []string{
+		"Mary", "Patricia", "Jennifer", "Linda", "Elizabeth", "Sarah", "Emma", "Olivia", "Sophia", "Ada",
+		"Yuki", "Marie", "Anna", "Lucia", "Grace", "Chloe", "Isabella", "Mia", "Amelia", "Hannah",
+	}
+	lastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Martin", "Lovelace",
+		"Wilson", "Anderson", "Taylor", "Moore", "Clark", "Lewis", "Walker", "Young", "Allen", "King",
+		"Tanaka", "Dubois", "Müller", "Rossi", "Silva", "Nguyen", "Kim", "Patel", "Novak", "Jensen",
+	}
+	emailDomains = []string{"example.com", "example.org", "example.net", "mail.example", "inbox.example"}
+)
+
+// cardBrand describes the numbers of a card brand
+type cardBrand struct {
+	name     string
+	prefixes []string
+	length   int
+	cvv      int // digits of the security code
+}
+
+var cardBrands = []cardBrand{
+	{"Visa", []string{"4"}, 16, 3},
+	{"Mastercard", []string{"51", "52", "53", "54", "55"}, 16, 3},
+	{"American Express", []string{"34", "37"}, 15, 4},
+	{"Discover", []string{"6011", "65"}, 16, 3},
+}
@@ -0,0 +1,124 @@
+package pgdump
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadCopySection reads the rows of a table from the COPY section of a dump.
+// The table is matched by name, with or without its schema. Values are
+// strings, or nil for NULL.
+func ReadCopySection(r io.Reader, table string) ([]string, [][]interface{}, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var columns []string
+	var rows [][]interface{}
+	inSection := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !inSection {
+			name, cols, ok := parseCopyHeader(line)
+			if ok && (name == table || name[strings.LastIndex(name, ".")+1:] == table) {
+				columns, inSection = cols, true
+			}
+			continue
+		}
+		if line == "\\." {
+			return columns, rows, nil
+		}
+		row := ParseCopyRow(line)
+		if len(row) != len(columns) {
+			return nil, nil, fmt.Errorf("COPY row %d of %s has %d values, expected %d", len(rows)+1, table, len(row), len(columns))
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+	if inSection {
+		return nil, nil, fmt.Errorf("COPY section of %s is not terminated by \\.", table)
+	}
+	return nil, nil, fmt.Errorf("no COPY section for table %s", table)
+}
+
+// parseCopyHeader reads the table (unquoted, "schema.table" when qualified)
+// and the columns of a "COPY table (columns) FROM stdin;" line
+func parseCopyHeader(line string) (string, []string, bool) {
+	if !strings.HasPrefix(line, "COPY ") || !strings.HasSuffix(line, ") FROM stdin;") {
+		return "", nil, false
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(line, "COPY "), ") FROM stdin;")
+	open := strings.Index(body, " (")
+	if open < 0 {
+		return "", nil, false
+	}
+	return strings.Join(splitIdentifiers(body[:open], '.'), "."), splitIdentifiers(body[open+2:], ','), true
+}
+
+// splitIdentifiers splits a list of identifiers, removing their quotes
+func splitIdentifiers(list string, sep rune) []string {
+	var idents []string
+	var current strings.Builder
+	quoted := false
+	runes := []rune(list)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '"' && quoted && i+1 < len(runes) && runes[i+1] == '"':
+			current.WriteRune('"')
+			i++
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			idents = append(idents, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(idents, strings.TrimSpace(current.String()))
+}
+
+// ParseCopyRow splits a COPY data line into its values, reversing the
+// escapes of FormatCopyRow; \N is returned as nil
+func ParseCopyRow(line string) []interface{} {
+	fields := strings.Split(line, "\t")
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		if field == "\\N" {
+			continue
+		}
+		values[i] = unescapeCopyString(field)
+	}
+	return values
+}
+
+// unescapeCopyString reverses escapeCopyString
+func unescapeCopyString(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var result strings.Builder
+	result.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			result.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			result.WriteByte('\n')
+		case 'r':
+			result.WriteByte('\r')
+		case 't':
+			result.WriteByte('\t')
+		default:
+			result.WriteByte(s[i])
+		}
+	}
+	return result.String()
+}
@@ -10,7 +10,8 @@ import (
 
 // COPYWriter writes PostgreSQL dump in COPY format
 type COPYWriter struct {
-	w io.Writer
+	w     io.Writer
+	names map[string]string
 }
 
 // NewCOPYWriter creates a new COPY format writer
@@ -26,26 +27,50 @@ func (cw *COPYWriter) WriteSchema(s *schema.Schema) error {
 	fmt.Fprintf(cw.w, "-- Generated by datagen\n")
 	fmt.Fprintf(cw.w, "--\n\n")
 
-	// Write database creation
-	fmt.Fprintf(cw.w, "CREATE DATABASE %s WITH ENCODING = '%s';\n\n",
-		EscapeIdentifier(s.Database.Name), s.Database.Encoding)
+	// Write database creation (skipped for managed databases and test transactions)
+	if s.Database.ShouldCreateDatabase() {
+		fmt.Fprintf(cw.w, "CREATE DATABASE %s WITH ENCODING = '%s';\n\n",
+			EscapeIdentifier(s.Database.Name), s.Database.Encoding)
 
-	fmt.Fprintf(cw.w, "\\connect %s\n\n", s.Database.Name)
+		fmt.Fprintf(cw.w, "\\connect %s\n\n", s.Database.Name)
+	}
+
+	// Write namespaces and qualify table names with them
+	writeNamespaces(cw.w, s, EscapeIdentifier, true)
+	cw.names = qualifiedTableNames(s, EscapeIdentifier)
 
-	// Write CREATE TABLE statements
-	for tableName, table := range s.Tables {
-		if err := cw.WriteCreateTable(tableName, table); err != nil {
+	// Write CREATE TABLE statements (referenced tables first)
+	for _, tableName := range schema.TableOrder(s) {
+		if err := cw.WriteCreateTable(tableName, s.Tables[tableName]); err != nil {
 			return err
 		}
 		fmt.Fprintf(cw.w, "\n")
 	}
 
+	// Foreign keys that close a cycle are checked at commit time
+	writeDeferredForeignKeys(cw.w, s, cw.names, EscapeIdentifier)
+
+	return nil
+}
+
+// WriteDataHeader writes the header of a data-only dump (no DDL)
+func (cw *COPYWriter) WriteDataHeader(s *schema.Schema) error {
+	fmt.Fprintf(cw.w, "--\n")
+	fmt.Fprintf(cw.w, "-- PostgreSQL database dump (COPY format, data only)\n")
+	fmt.Fprintf(cw.w, "-- Generated by datagen\n")
+	fmt.Fprintf(cw.w, "--\n\n")
+
+	if s.Database.ShouldCreateDatabase() {
+		fmt.Fprintf(cw.w, "\\connect %s\n\n", s.Database.Name)
+	}
+	writeNamespaces(cw.w, s, EscapeIdentifier, false)
+	cw.names = qualifiedTableNames(s, EscapeIdentifier)
 	return nil
 }
 
 // WriteCreateTable writes a CREATE TABLE statement
 func (cw *COPYWriter) WriteCreateTable(tableName string, table *schema.Table) error {
-	fmt.Fprintf(cw.w, "CREATE TABLE %s (\n", EscapeIdentifier(tableName))
+	fmt.Fprintf(cw.w, "CREATE TABLE %s (\n", cw.tableName(tableName))
 
 	// Write columns
 	for i, col := range table.Columns {
@@ -79,7 +104,7 @@ func (cw *COPYWriter) WriteCreateTable(tableName string, table *schema.Table) er
 			indexName = fmt.Sprintf("%s_%s_idx", tableName, strings.Join(idx.Columns, "_"))
 		}
 		fmt.Fprintf(cw.w, "CREATE INDEX %s ON %s (%s);\n",
-			EscapeIdentifier(indexName), EscapeIdentifier(tableName), FormatIdentifierList(idx.Columns))
+			EscapeIdentifier(indexName), cw.tableName(tableName), FormatIdentifierList(idx.Columns))
 	}
 
 	// Write unique constraints
@@ -89,7 +114,7 @@ func (cw *COPYWriter) WriteCreateTable(tableName string, table *schema.Table) er
 			constraintName = fmt.Sprintf("%s_%s_key", tableName, strings.Join(uc.Columns, "_"))
 		}
 		fmt.Fprintf(cw.w, "ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s);\n",
-			EscapeIdentifier(tableName), EscapeIdentifier(constraintName), FormatIdentifierList(uc.Columns))
+			cw.tableName(tableName), EscapeIdentifier(constraintName), FormatIdentifierList(uc.Columns))
 	}
 
 	return nil
@@ -98,10 +123,39 @@ func (cw *COPYWriter) WriteCreateTable(tableName string, table *schema.Table) er
 // WriteCopyHeader writes the COPY FROM stdin header
 func (cw *COPYWriter) WriteCopyHeader(tableName string, columns []string) error {
 	fmt.Fprintf(cw.w, "COPY %s (%s) FROM stdin;\n",
-		EscapeIdentifier(tableName), FormatIdentifierList(columns))
+		cw.tableName(tableName), FormatIdentifierList(columns))
 	return nil
 }
 
+// WriteUpdate writes an UPDATE statement setting columns of the row
+// identified by its key columns
+func (cw *COPYWriter) WriteUpdate(tableName string, setColumns, keyColumns []string, row map[string]interface{}) error {
+	fmt.Fprintf(cw.w, "UPDATE %s SET %s WHERE %s;\n", cw.tableName(tableName),
+		assignmentList(setColumns, row, EscapeIdentifier, FormatValue, ", "),
+		assignmentList(keyColumns, row, EscapeIdentifier, FormatValue, " AND "))
+	return nil
+}
+
+// BeginTransaction starts the data section of the dump
+func (cw *COPYWriter) BeginTransaction() error {
+	writeBeginTransaction(cw.w)
+	return nil
+}
+
+// CommitTransaction ends the data section of the dump
+func (cw *COPYWriter) CommitTransaction() error {
+	writeCommitTransaction(cw.w)
+	return nil
+}
+
+// tableName returns the escaped, namespace-qualified name of a table
+func (cw *COPYWriter) tableName(tableName string) string {
+	if name, ok := cw.names[tableName]; ok {
+		return name
+	}
+	return EscapeIdentifier(tableName)
+}
+
 // WriteCopyRow writes a single data row in COPY format (tab-separated values)
 func (cw *COPYWriter) WriteCopyRow(columns []string, row map[string]interface{}) error {
 	fmt.Fprintf(cw.w, "%s\n", FormatCopyRow(columns, row))
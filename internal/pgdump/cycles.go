@@ -0,0 +1,76 @@
+package pgdump
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// writeDeferredForeignKeys writes the foreign keys marked with
+// "cycle_break": "deferred" as DEFERRABLE INITIALLY DEFERRED constraints.
+// They are added after every table exists since the tables reference each other.
+func writeDeferredForeignKeys(w io.Writer, s *schema.Schema, names map[string]string, ident func(string) string) {
+	written := false
+	for _, tableName := range schema.TableOrder(s) {
+		for _, fk := range s.Tables[tableName].ForeignKeys {
+			parent, exists := s.Tables[fk.ReferencedTable]
+			if fk.CycleBreak != schema.CycleBreakDeferred || !exists {
+				continue
+			}
+			refColumns := fk.ReferencedColumns
+			if len(refColumns) == 0 {
+				refColumns = parent.PrimaryKeyColumns()
+			}
+
+			constraint := tableName + "_" + strings.Join(fk.Columns, "_") + "_fkey"
+			fmt.Fprintf(w, "ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s) DEFERRABLE INITIALLY DEFERRED;\n",
+				lookupName(names, tableName, ident), ident(constraint), identList(fk.Columns, ident),
+				lookupName(names, fk.ReferencedTable, ident), identList(refColumns, ident))
+			written = true
+		}
+	}
+
+	if written {
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+// writeBeginTransaction opens a transaction whose constraint checks run at commit
+func writeBeginTransaction(w io.Writer) {
+	fmt.Fprintf(w, "BEGIN;\n")
+	fmt.Fprintf(w, "SET CONSTRAINTS ALL DEFERRED;\n\n")
+}
+
+// writeCommitTransaction commits the transaction opened by writeBeginTransaction
+func writeCommitTransaction(w io.Writer) {
+	fmt.Fprintf(w, "\nCOMMIT;\n")
+}
+
+// assignmentList formats "column = value" pairs for the given columns of a row
+func assignmentList(columns []string, row map[string]interface{}, ident func(string) string, format func(interface{}) string, sep string) string {
+	pairs := make([]string, len(columns))
+	for i, col := range columns {
+		pairs[i] = ident(col) + " = " + format(row[col])
+	}
+	return strings.Join(pairs, sep)
+}
+
+// identList formats a comma-separated list of identifiers
+func identList(idents []string, ident func(string) string) string {
+	formatted := make([]string, len(idents))
+	for i, name := range idents {
+		formatted[i] = ident(name)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// lookupName returns the statement name of a table, falling back to the
+// identifier itself
+func lookupName(names map[string]string, tableName string, ident func(string) string) string {
+	if name, ok := names[tableName]; ok {
+		return name
+	}
+	return ident(tableName)
+}
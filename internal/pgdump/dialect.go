@@ -0,0 +1,348 @@
+package pgdump
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// Dialect translates PostgreSQL schema definitions and values into another SQL dialect
+type Dialect interface {
+	// Name returns the dialect name (mysql, sqlite)
+	Name() string
+
+	// Preamble returns statements written before the first CREATE TABLE
+	Preamble(db schema.DatabaseConfig) []string
+
+	// QuoteIdentifier quotes a table or column name
+	QuoteIdentifier(ident string) string
+
+	// MapColumn translates a column definition. inlinePK reports that the
+	// primary key is declared on the column itself (SQLite rowid aliases).
+	MapColumn(table *schema.Table, col *schema.Column) (definition string, inlinePK bool, warnings []string)
+
+	// MapDefault translates a column DEFAULT expression.
+	// An empty result means the default is dropped.
+	MapDefault(col *schema.Column) (string, []string)
+
+	// MapIndex reports whether an index can be created in the dialect.
+	// Indexes it cannot create are skipped with a warning.
+	MapIndex(table *schema.Table, name string, idx *schema.Index) (bool, []string)
+
+	// FormatValue formats a generated value as a SQL literal
+	FormatValue(val interface{}) string
+}
+
+// NewDialect returns the dialect with the given name
+func NewDialect(name string) (Dialect, error) {
+	switch name {
+	case "mysql":
+		return &MySQLDialect{}, nil
+	case "sqlite":
+		return &SQLiteDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported dialect: %s (must be one of: postgres, mysql, sqlite)", name)
+	}
+}
+
+// MySQLDialect translates PostgreSQL constructs to MySQL 8
+type MySQLDialect struct{}
+
+func (d *MySQLDialect) Name() string {
+	return "mysql"
+}
+
+func (d *MySQLDialect) Preamble(db schema.DatabaseConfig) []string {
+	if !db.ShouldCreateDatabase() {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s CHARACTER SET utf8mb4;", d.QuoteIdentifier(db.Name)),
+		fmt.Sprintf("USE %s;", d.QuoteIdentifier(db.Name)),
+	}
+}
+
+func (d *MySQLDialect) QuoteIdentifier(ident string) string {
+	return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+}
+
+func (d *MySQLDialect) MapColumn(table *schema.Table, col *schema.Column) (string, bool, []string) {
+	baseType, precision, scale, isArray := parsePostgresType(col.Type)
+	if isArray {
+		return "JSON", false, []string{fmt.Sprintf("column %s: array type %s stored as JSON", col.Name, col.Type)}
+	}
+
+	var warnings []string
+	switch baseType {
+	case "serial", "smallserial", "bigserial":
+		intType := map[string]string{"serial": "INT", "smallserial": "SMALLINT", "bigserial": "BIGINT"}[baseType]
+		if !isKeyColumn(table, col.Name) {
+			warnings = append(warnings, fmt.Sprintf("column %s: MySQL requires AUTO_INCREMENT columns to be indexed, add it to the primary key or a unique constraint", col.Name))
+		}
+		return intType + " AUTO_INCREMENT", false, warnings
+	case "smallint", "int2":
+		return "SMALLINT", false, nil
+	case "integer", "int", "int4":
+		return "INT", false, nil
+	case "bigint", "int8":
+		return "BIGINT", false, nil
+	case "real", "float4":
+		return "FLOAT", false, nil
+	case "double precision", "float8":
+		return "DOUBLE", false, nil
+	case "numeric", "decimal":
+		if precision == 0 {
+			warnings = append(warnings, fmt.Sprintf("column %s: unconstrained %s mapped to DECIMAL(65,30)", col.Name, baseType))
+			return "DECIMAL(65,30)", false, warnings
+		}
+		if precision > 65 {
+			warnings = append(warnings, fmt.Sprintf("column %s: precision %d exceeds MySQL maximum of 65", col.Name, precision))
+			precision = 65
+		}
+		return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale), false, warnings
+	case "money":
+		return "DECIMAL(19,2)", false, nil
+	case "varchar", "character varying":
+		if precision == 0 {
+			warnings = append(warnings, fmt.Sprintf("column %s: varchar without length mapped to TEXT", col.Name))
+			return "TEXT", false, warnings
+		}
+		return fmt.Sprintf("VARCHAR(%d)", precision), false, nil
+	case "char", "character":
+		if precision == 0 {
+			precision = 1
+		}
+		return fmt.Sprintf("CHAR(%d)", precision), false, nil
+	case "text":
+		return "TEXT", false, nil
+	case "boolean", "bool":
+		return "BOOLEAN", false, nil
+	case "date":
+		return "DATE", false, nil
+	case "time", "time without time zone":
+		return "TIME(6)", false, nil
+	case "timestamp", "timestamp without time zone":
+		return "DATETIME(6)", false, nil
+	case "timestamptz", "timestamp with time zone":
+		warnings = append(warnings, fmt.Sprintf("column %s: %s mapped to DATETIME(6), time zone offsets are not stored", col.Name, col.Type))
+		return "DATETIME(6)", false, warnings
+	case "interval":
+		warnings = append(warnings, fmt.Sprintf("column %s: MySQL has no interval type, stored as VARCHAR(64)", col.Name))
+		return "VARCHAR(64)", false, warnings
+	case "uuid":
+		return "CHAR(36)", false, nil
+	case "json", "jsonb":
+		return "JSON", false, nil
+	case "bytea":
+		return "LONGBLOB", false, nil
+	case "inet", "cidr":
+		return "VARCHAR(43)", false, nil
+	case "macaddr":
+		return "VARCHAR(17)", false, nil
+	case "xml":
+		return "LONGTEXT", false, nil
+	default:
+		warnings = append(warnings, fmt.Sprintf("column %s: type %s has no MySQL equivalent, stored as TEXT", col.Name, col.Type))
+		return "TEXT", false, warnings
+	}
+}
+
+func (d *MySQLDialect) MapDefault(col *schema.Column) (string, []string) {
+	def, ok := translateDefault(col.DefaultValue)
+	if !ok {
+		return "", []string{fmt.Sprintf("column %s: default %q is PostgreSQL-specific and was dropped", col.Name, col.DefaultValue)}
+	}
+	if def == "CURRENT_TIMESTAMP" {
+		// DATETIME(6) columns need a matching fractional precision
+		def = "CURRENT_TIMESTAMP(6)"
+	}
+	return def, nil
+}
+
+func (d *MySQLDialect) MapIndex(table *schema.Table, name string, idx *schema.Index) (bool, []string) {
+	switch idx.Type {
+	case "", "btree":
+	case "hash":
+		// InnoDB builds hash indexes as B-trees
+		return true, []string{fmt.Sprintf("index %s: hash index created as a regular index", name)}
+	default:
+		return false, []string{fmt.Sprintf("index %s: %s indexes are not supported by MySQL and were skipped", name, idx.Type)}
+	}
+
+	for _, column := range idx.Columns {
+		col := table.Column(column)
+		if col == nil {
+			continue
+		}
+		def, _, _ := d.MapColumn(table, col)
+		switch mapped := strings.Fields(def)[0]; mapped {
+		case "JSON", "TEXT", "LONGTEXT", "LONGBLOB":
+			// MySQL needs a prefix length to index these, which PostgreSQL indexes do not carry
+			return false, []string{fmt.Sprintf("index %s: column %s is stored as %s, which MySQL cannot index; index skipped", name, column, mapped)}
+		}
+	}
+	return true, nil
+}
+
+func (d *MySQLDialect) FormatValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		// MySQL treats backslash as an escape character in string literals
+		escaped := strings.ReplaceAll(v, "\\", "\\\\")
+		escaped = strings.ReplaceAll(escaped, "'", "''")
+		escaped = strings.ReplaceAll(escaped, "\x00", "\\0")
+		return "'" + escaped + "'"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case []interface{}, []string, []int64, map[string]interface{}:
+		return d.FormatValue(jsonText(v))
+	default:
+		return FormatValue(val)
+	}
+}
+
+// SQLiteDialect translates PostgreSQL constructs to SQLite 3
+type SQLiteDialect struct{}
+
+func (d *SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+func (d *SQLiteDialect) Preamble(db schema.DatabaseConfig) []string {
+	// SQLite has one database per file, so there is nothing to create or connect to
+	return []string{"PRAGMA foreign_keys = ON;"}
+}
+
+func (d *SQLiteDialect) QuoteIdentifier(ident string) string {
+	return "\"" + strings.ReplaceAll(ident, "\"", "\"\"") + "\""
+}
+
+func (d *SQLiteDialect) MapColumn(table *schema.Table, col *schema.Column) (string, bool, []string) {
+	baseType, _, _, isArray := parsePostgresType(col.Type)
+	if isArray {
+		return "TEXT", false, []string{fmt.Sprintf("column %s: array type %s stored as JSON text", col.Name, col.Type)}
+	}
+
+	switch baseType {
+	case "serial", "smallserial", "bigserial":
+		// Only a single-column INTEGER PRIMARY KEY is an auto-incrementing rowid alias
+		if pk := table.PrimaryKeyColumns(); len(pk) == 1 && pk[0] == col.Name {
+			return "INTEGER PRIMARY KEY AUTOINCREMENT", true, nil
+		}
+		return "INTEGER", false, []string{fmt.Sprintf("column %s: SQLite only auto-increments a single-column INTEGER PRIMARY KEY, values are written explicitly", col.Name)}
+	case "smallint", "int2", "integer", "int", "int4", "bigint", "int8":
+		return "INTEGER", false, nil
+	case "real", "float4", "double precision", "float8":
+		return "REAL", false, nil
+	case "numeric", "decimal", "money":
+		return "NUMERIC", false, nil
+	case "varchar", "character varying", "char", "character", "text", "uuid", "xml",
+		"inet", "cidr", "macaddr":
+		return "TEXT", false, nil
+	case "boolean", "bool":
+		return "INTEGER", false, nil
+	case "date", "time", "time without time zone", "timestamp", "timestamp without time zone":
+		return "TEXT", false, nil
+	case "timestamptz", "timestamp with time zone":
+		return "TEXT", false, []string{fmt.Sprintf("column %s: %s stored as ISO-8601 text without time zone", col.Name, col.Type)}
+	case "json", "jsonb":
+		return "TEXT", false, nil
+	case "bytea":
+		return "BLOB", false, nil
+	default:
+		return "TEXT", false, []string{fmt.Sprintf("column %s: type %s has no SQLite equivalent, stored as TEXT", col.Name, col.Type)}
+	}
+}
+
+func (d *SQLiteDialect) MapDefault(col *schema.Column) (string, []string) {
+	def, ok := translateDefault(col.DefaultValue)
+	if !ok {
+		return "", []string{fmt.Sprintf("column %s: default %q is PostgreSQL-specific and was dropped", col.Name, col.DefaultValue)}
+	}
+	switch strings.ToUpper(def) {
+	case "TRUE":
+		return "1", nil
+	case "FALSE":
+		return "0", nil
+	}
+	return def, nil
+}
+
+func (d *SQLiteDialect) MapIndex(table *schema.Table, name string, idx *schema.Index) (bool, []string) {
+	if idx.Type != "" && idx.Type != "btree" {
+		return true, []string{fmt.Sprintf("index %s: %s index created as a regular index", name, idx.Type)}
+	}
+	return true, nil
+}
+
+func (d *SQLiteDialect) FormatValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		// SQLite string literals only escape single quotes
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05") + "'"
+	case []interface{}, []string, []int64, map[string]interface{}:
+		return d.FormatValue(jsonText(v))
+	default:
+		return FormatValue(val)
+	}
+}
+
+// translateDefault converts portable DEFAULT expressions.
+// It returns false for PostgreSQL-specific expressions (sequences, casts, functions).
+func translateDefault(def string) (string, bool) {
+	trimmed := strings.TrimSpace(def)
+	lower := strings.ToLower(trimmed)
+
+	switch lower {
+	case "now()", "current_timestamp", "localtimestamp", "transaction_timestamp()":
+		return "CURRENT_TIMESTAMP", true
+	case "current_date":
+		return "CURRENT_DATE", true
+	case "true", "false", "null":
+		return strings.ToUpper(lower), true
+	}
+
+	if _, err := strconv.ParseFloat(trimmed, 64); err == nil {
+		return trimmed, true
+	}
+
+	// Plain string literals without a ::type cast
+	if strings.HasPrefix(trimmed, "'") && strings.HasSuffix(trimmed, "'") && !strings.Contains(trimmed, "::") {
+		return trimmed, true
+	}
+
+	return "", false
+}
+
+// isKeyColumn reports whether a column is the first column of a primary key or unique constraint
+func isKeyColumn(table *schema.Table, column string) bool {
+	if pk := table.PrimaryKeyColumns(); len(pk) > 0 && pk[0] == column {
+		return true
+	}
+	for _, uc := range table.UniqueConstraints {
+		if len(uc.Columns) > 0 && uc.Columns[0] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonText serializes arrays and documents for dialects without native array types
+func jsonText(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
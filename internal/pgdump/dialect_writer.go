@@ -0,0 +1,233 @@
+package pgdump
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// DialectWriter writes INSERT-format dumps for non-PostgreSQL databases
+type DialectWriter struct {
+	w        io.Writer
+	dialect  Dialect
+	warnings []string
+	seen     map[string]bool
+	names    map[string]string
+}
+
+// NewDialectWriter creates a writer for the named dialect.
+// "postgres" (or an empty name) returns the regular SQLWriter.
+func NewDialectWriter(w io.Writer, dialect string) (Writer, error) {
+	if dialect == "" || dialect == "postgres" {
+		return NewSQLWriter(w), nil
+	}
+
+	d, err := NewDialect(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &DialectWriter{w: w, dialect: d, seen: make(map[string]bool)}, nil
+}
+
+// Warnings returns the constructs that could not be translated exactly
+func (dw *DialectWriter) Warnings() []string {
+	return dw.warnings
+}
+
+// WriteSchema writes the complete schema in the target dialect
+func (dw *DialectWriter) WriteSchema(s *schema.Schema) error {
+	// Write header comment
+	fmt.Fprintf(dw.w, "--\n")
+	fmt.Fprintf(dw.w, "-- %s database dump\n", dialectTitle(dw.dialect.Name()))
+	fmt.Fprintf(dw.w, "-- Generated by datagen\n")
+	fmt.Fprintf(dw.w, "--\n\n")
+
+	for _, stmt := range dw.dialect.Preamble(s.Database) {
+		fmt.Fprintf(dw.w, "%s\n", stmt)
+	}
+	fmt.Fprintf(dw.w, "\n")
+	dw.setTableNames(s)
+
+	// PostgreSQL-only objects have no equivalent in the target dialect
+	if len(s.Extensions) > 0 {
+		dw.warn(fmt.Sprintf("extensions %v are PostgreSQL-specific and were skipped", s.Extensions))
+	}
+	if len(s.Sequences) > 0 {
+		dw.warn(fmt.Sprintf("%d standalone sequence(s) are not supported by %s and were skipped", len(s.Sequences), dw.dialect.Name()))
+	}
+	if len(s.CustomTypes) > 0 {
+		dw.warn(fmt.Sprintf("%d custom type(s) are not supported by %s and were skipped", len(s.CustomTypes), dw.dialect.Name()))
+	}
+
+	// Write CREATE TABLE statements (referenced tables first)
+	for _, tableName := range schema.TableOrder(s) {
+		if err := dw.WriteCreateTable(tableName, s.Tables[tableName]); err != nil {
+			return err
+		}
+		fmt.Fprintf(dw.w, "\n")
+	}
+
+	return nil
+}
+
+// WriteDataHeader writes the header of a data-only dump (no DDL)
+func (dw *DialectWriter) WriteDataHeader(s *schema.Schema) error {
+	fmt.Fprintf(dw.w, "--\n")
+	fmt.Fprintf(dw.w, "-- %s database dump (data only)\n", dialectTitle(dw.dialect.Name()))
+	fmt.Fprintf(dw.w, "-- Generated by datagen\n")
+	fmt.Fprintf(dw.w, "--\n\n")
+	dw.setTableNames(s)
+	return nil
+}
+
+// WriteCreateTable writes a CREATE TABLE statement with translated column types.
+// Untranslatable constructs are recorded as warnings and written as SQL comments.
+func (dw *DialectWriter) WriteCreateTable(tableName string, table *schema.Table) error {
+	var tableWarnings []string
+	var lines []string
+	inlinePK := false
+
+	for _, col := range table.Columns {
+		definition, inline, warnings := dw.dialect.MapColumn(table, col)
+		tableWarnings = append(tableWarnings, warnings...)
+		inlinePK = inlinePK || inline
+
+		line := fmt.Sprintf("    %s %s", dw.dialect.QuoteIdentifier(col.Name), definition)
+		if !col.Nullable {
+			line += " NOT NULL"
+		}
+		if col.DefaultValue != "" {
+			def, warnings := dw.dialect.MapDefault(col)
+			tableWarnings = append(tableWarnings, warnings...)
+			if def != "" {
+				line += " DEFAULT " + def
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	// Write primary key (unless declared inline on the column)
+	if pk := table.PrimaryKeyColumns(); len(pk) > 0 && !inlinePK {
+		lines = append(lines, fmt.Sprintf("    PRIMARY KEY (%s)", dw.identifierList(pk)))
+	}
+
+	// Unique constraints are declared inline since SQLite cannot add them with ALTER TABLE
+	for _, uc := range table.UniqueConstraints {
+		if uc.Name != "" {
+			lines = append(lines, fmt.Sprintf("    CONSTRAINT %s UNIQUE (%s)",
+				dw.dialect.QuoteIdentifier(uc.Name), dw.identifierList(uc.Columns)))
+		} else {
+			lines = append(lines, fmt.Sprintf("    UNIQUE (%s)", dw.identifierList(uc.Columns)))
+		}
+	}
+
+	if len(table.CheckConstraints) > 0 {
+		tableWarnings = append(tableWarnings, fmt.Sprintf("%d CHECK constraint(s) use PostgreSQL expressions and were skipped", len(table.CheckConstraints)))
+	}
+
+	for _, warning := range tableWarnings {
+		fmt.Fprintf(dw.w, "-- WARNING: %s\n", warning)
+		dw.warn(fmt.Sprintf("table %s: %s", tableName, warning))
+	}
+
+	fmt.Fprintf(dw.w, "CREATE TABLE %s (\n%s\n);\n", dw.tableName(tableName), strings.Join(lines, ",\n"))
+
+	// Write indexes
+	for _, idx := range table.Indexes {
+		indexName := idx.Name
+		if indexName == "" {
+			indexName = fmt.Sprintf("%s_%s_idx", tableName, strings.Join(idx.Columns, "_"))
+		}
+		ok, warnings := dw.dialect.MapIndex(table, indexName, idx)
+		for _, warning := range warnings {
+			fmt.Fprintf(dw.w, "-- WARNING: %s\n", warning)
+			dw.warn(fmt.Sprintf("table %s: %s", tableName, warning))
+		}
+		if !ok {
+			continue
+		}
+
+		unique := ""
+		if idx.Unique {
+			unique = "UNIQUE "
+		}
+		fmt.Fprintf(dw.w, "CREATE %sINDEX %s ON %s (%s);\n", unique,
+			dw.dialect.QuoteIdentifier(indexName), dw.tableName(tableName), dw.identifierList(idx.Columns))
+	}
+
+	return nil
+}
+
+// WriteInsert writes an INSERT statement for a single row
+func (dw *DialectWriter) WriteInsert(tableName string, columns []string, row map[string]interface{}) error {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = dw.dialect.FormatValue(row[col])
+	}
+
+	fmt.Fprintf(dw.w, "INSERT INTO %s (%s) VALUES (%s);\n",
+		dw.tableName(tableName), dw.identifierList(columns), strings.Join(values, ", "))
+	return nil
+}
+
+// WriteUpdate writes an UPDATE statement setting columns of the row
+// identified by its key columns
+func (dw *DialectWriter) WriteUpdate(tableName string, setColumns, keyColumns []string, row map[string]interface{}) error {
+	fmt.Fprintf(dw.w, "UPDATE %s SET %s WHERE %s;\n", dw.tableName(tableName),
+		assignmentList(setColumns, row, dw.dialect.QuoteIdentifier, dw.dialect.FormatValue, ", "),
+		assignmentList(keyColumns, row, dw.dialect.QuoteIdentifier, dw.dialect.FormatValue, " AND "))
+	return nil
+}
+
+// setTableNames maps tables to their bare names; MySQL and SQLite have no
+// PostgreSQL-style namespaces, so tables are written unqualified
+func (dw *DialectWriter) setTableNames(s *schema.Schema) {
+	if namespaces := s.Namespaces(); len(namespaces) > 0 {
+		dw.warn(fmt.Sprintf("namespaces %v are not supported by %s, tables are written unqualified", namespaces, dw.dialect.Name()))
+	}
+	dw.names = make(map[string]string, len(s.Tables))
+	for tableName := range s.Tables {
+		_, name := s.QualifiedTable(tableName)
+		dw.names[tableName] = dw.dialect.QuoteIdentifier(name)
+	}
+}
+
+// tableName returns the quoted name of a table
+func (dw *DialectWriter) tableName(tableName string) string {
+	if name, ok := dw.names[tableName]; ok {
+		return name
+	}
+	return dw.dialect.QuoteIdentifier(tableName)
+}
+
+// identifierList quotes and joins identifiers for the target dialect
+func (dw *DialectWriter) identifierList(idents []string) string {
+	quoted := make([]string, len(idents))
+	for i, ident := range idents {
+		quoted[i] = dw.dialect.QuoteIdentifier(ident)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// warn records a warning once
+func (dw *DialectWriter) warn(msg string) {
+	if dw.seen[msg] {
+		return
+	}
+	dw.seen[msg] = true
+	dw.warnings = append(dw.warnings, msg)
+}
+
+// dialectTitle returns the display name of a dialect
+func dialectTitle(name string) string {
+	switch name {
+	case "mysql":
+		return "MySQL"
+	case "sqlite":
+		return "SQLite"
+	default:
+		return name
+	}
+}
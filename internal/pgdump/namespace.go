@@ -0,0 +1,49 @@
+package pgdump
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// qualifiedTableNames maps each table of the schema to the name used in
+// statements, qualified with its namespace when one is configured
+func qualifiedTableNames(s *schema.Schema, ident func(string) string) map[string]string {
+	names := make(map[string]string, len(s.Tables))
+	for tableName := range s.Tables {
+		ns, name := s.QualifiedTable(tableName)
+		if ns == "" {
+			names[tableName] = ident(name)
+		} else {
+			names[tableName] = ident(ns) + "." + ident(name)
+		}
+	}
+	return names
+}
+
+// writeNamespaces writes CREATE SCHEMA statements for the namespaces used by
+// the schema (when create is set) and points search_path at the default one
+func writeNamespaces(w io.Writer, s *schema.Schema, ident func(string) string, create bool) {
+	written := false
+	if create {
+		for _, ns := range s.Namespaces() {
+			fmt.Fprintf(w, "CREATE SCHEMA IF NOT EXISTS %s;\n", ident(ns))
+			written = true
+		}
+	}
+
+	if ns := s.Database.Schema; ns != "" && ns != schema.DefaultNamespace {
+		fmt.Fprintf(w, "SET search_path TO %s, public;\n", ident(ns))
+		written = true
+	}
+
+	if written {
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+// plainIdentifier returns an identifier unchanged
+func plainIdentifier(ident string) string {
+	return ident
+}
@@ -0,0 +1,417 @@
+package pgdump
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Splitter is implemented by outputs that can roll over to a new file
+// between statements
+type Splitter interface {
+	// BeginTable is called before the data of each table is written
+	BeginTable(tableName string) error
+
+	// NeedsSplit reports whether the current file has reached its size limit
+	NeedsSplit() bool
+
+	// Split closes the current file and continues in the next one
+	Split() error
+}
+
+// OutputOptions configures file output
+type OutputOptions struct {
+	// Path of the dump file (or of the index script when splitting).
+	// "-" writes a single stream to stdout.
+	Path string
+
+	// Compression: "gzip", "zstd", "none", or empty to detect from Path
+	Compression string
+
+	// SplitSize is the maximum uncompressed size of each part in bytes (0 = no limit)
+	SplitSize int64
+
+	// SplitPerTable starts a new part for the data of every table
+	SplitPerTable bool
+}
+
+// Output writes a dump to one file or to numbered part files, optionally
+// compressing each file as it streams. When splitting, Close writes an index
+// script that includes every part in order.
+type Output struct {
+	opts        OutputOptions
+	compression string
+	parts       []string
+
+	file    *os.File
+	comp    io.WriteCloser
+	w       *bufio.Writer
+	active  bool
+	closed  bool
+	written int64
+}
+
+// NewOutput creates the output file (or the first part) described by opts
+func NewOutput(opts OutputOptions) (*Output, error) {
+	compression := opts.Compression
+	if compression == "" {
+		compression = DetectCompression(opts.Path)
+	}
+	switch compression {
+	case "gzip", "zstd", "none":
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s (must be one of: gzip, zstd, none)", compression)
+	}
+
+	// An extension naming another compression would mislabel the file
+	if detected := DetectCompression(opts.Path); detected != "none" && detected != compression {
+		return nil, fmt.Errorf("output %s has a %s extension but compression is %s", opts.Path, detected, compression)
+	}
+
+	if opts.SplitSize < 0 {
+		return nil, fmt.Errorf("split size must be positive, got %d", opts.SplitSize)
+	}
+	if opts.Path == "-" && (opts.SplitSize > 0 || opts.SplitPerTable) {
+		return nil, fmt.Errorf("split output requires a file path, not stdout")
+	}
+
+	// Keep the file name in line with its content so readers can detect it
+	if ext := compressionExt(compression); ext != "" && opts.Path != "-" && !strings.HasSuffix(opts.Path, ext) {
+		opts.Path += ext
+	}
+
+	o := &Output{opts: opts, compression: compression}
+	if err := o.open(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Write writes to the current file
+func (o *Output) Write(p []byte) (int, error) {
+	n, err := o.w.Write(p)
+	o.written += int64(n)
+	return n, err
+}
+
+// BeginTable starts a new part for the table when splitting per table
+func (o *Output) BeginTable(tableName string) error {
+	if o.opts.SplitPerTable && o.written > 0 {
+		return o.Split()
+	}
+	return nil
+}
+
+// NeedsSplit reports whether the current part exceeded the split size
+func (o *Output) NeedsSplit() bool {
+	return o.opts.SplitSize > 0 && o.written >= o.opts.SplitSize
+}
+
+// Split finishes the current part and opens the next one
+func (o *Output) Split() error {
+	if !o.splitting() {
+		return nil
+	}
+	if err := o.closePart(); err != nil {
+		return err
+	}
+	return o.open()
+}
+
+// Path returns the dump file, or the index script when splitting
+func (o *Output) Path() string {
+	if o.splitting() {
+		return IndexPath(o.opts.Path, o.compression)
+	}
+	return o.opts.Path
+}
+
+// Parts returns the files written so far
+func (o *Output) Parts() []string {
+	return o.parts
+}
+
+// Close finishes the current file and, when splitting, writes the index script
+func (o *Output) Close() error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+
+	if err := o.closePart(); err != nil {
+		return err
+	}
+	if !o.splitting() {
+		return nil
+	}
+	return o.writeIndex()
+}
+
+// splitting reports whether output is written as numbered parts
+func (o *Output) splitting() bool {
+	return o.opts.SplitSize > 0 || o.opts.SplitPerTable
+}
+
+// open creates the next file and its compressor
+func (o *Output) open() error {
+	path := o.opts.Path
+	if o.splitting() {
+		path = partPath(o.opts.Path, o.compression, len(o.parts)+1)
+	}
+
+	var dst io.Writer = os.Stdout
+	o.file = nil
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		o.file = file
+		dst = file
+	}
+
+	o.comp = nil
+	switch o.compression {
+	case "gzip":
+		o.comp = gzip.NewWriter(dst)
+		dst = o.comp
+	case "zstd":
+		enc, err := zstd.NewWriter(dst)
+		if err != nil {
+			if o.file != nil {
+				o.file.Close()
+			}
+			return fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		o.comp = enc
+		dst = enc
+	}
+
+	o.w = bufio.NewWriterSize(dst, 64*1024)
+	o.active = true
+	o.written = 0
+	o.parts = append(o.parts, path)
+	return nil
+}
+
+// closePart flushes the compressor and closes the current file
+func (o *Output) closePart() error {
+	if !o.active {
+		return nil
+	}
+
+	err := o.w.Flush()
+	if o.comp != nil {
+		if closeErr := o.comp.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if o.file != nil {
+		if closeErr := o.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	o.file = nil
+	o.active = false
+
+	if err != nil {
+		return fmt.Errorf("failed to finish output file: %w", err)
+	}
+	return nil
+}
+
+// writeIndex writes a psql script that includes all parts in order
+func (o *Output) writeIndex() error {
+	indexPath := IndexPath(o.opts.Path, o.compression)
+
+	var b strings.Builder
+	b.WriteString("--\n")
+	b.WriteString("-- datagen split dump index\n")
+	fmt.Fprintf(&b, "-- Restore with: psql -f %s\n", filepath.Base(indexPath))
+	if o.compression != "none" {
+		fmt.Fprintf(&b, "-- Parts are %s-compressed; decompress them next to this script first\n", o.compression)
+	}
+	b.WriteString("--\n\n")
+	for _, part := range o.parts {
+		fmt.Fprintf(&b, "\\i %s\n", strings.TrimSuffix(filepath.Base(part), compressionExt(o.compression)))
+	}
+
+	if err := os.WriteFile(indexPath, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write index script: %w", err)
+	}
+	return nil
+}
+
+// DetectCompression returns the compression implied by a file extension
+func DetectCompression(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gz":
+		return "gzip"
+	case ".zst":
+		return "zstd"
+	default:
+		return "none"
+	}
+}
+
+// IndexPath returns the index script path for a split dump
+// (e.g. "dump.sql.gz" → "dump.sql")
+func IndexPath(path, compression string) string {
+	return strings.TrimSuffix(path, compressionExt(compression))
+}
+
+// partPath returns the path of a numbered part (e.g. "dump.sql.gz" → "dump-0001.sql.gz")
+func partPath(path, compression string, n int) string {
+	compExt := compressionExt(compression)
+	base := strings.TrimSuffix(path, compExt)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	return fmt.Sprintf("%s-%04d%s%s", stem, n, ext, compExt)
+}
+
+// compressionExt returns the file extension used by a compression
+func compressionExt(compression string) string {
+	switch compression {
+	case "gzip":
+		return ".gz"
+	case "zstd":
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// ParseSize parses a human-readable size such as "500MB" or "1GB" into bytes
+func ParseSize(s string) (int64, error) {
+	trimmed := strings.ToUpper(strings.TrimSpace(s))
+	if trimmed == "" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		factor int64
+	}{
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+
+	factor := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(trimmed, u.suffix) {
+			factor = u.factor
+			trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, u.suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid size %q (examples: 500MB, 1GB)", s)
+	}
+	return int64(value * float64(factor)), nil
+}
+
+// OpenDumpFile opens a dump file, decompressing it based on its extension
+func OpenDumpFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch DetectCompression(path) {
+	case "gzip":
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read gzip file %s: %w", path, err)
+		}
+		return &dumpReader{Reader: gz, closers: []io.Closer{gz, file}}, nil
+	case "zstd":
+		dec, err := zstd.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read zstd file %s: %w", path, err)
+		}
+		rc := dec.IOReadCloser()
+		return &dumpReader{Reader: rc, closers: []io.Closer{rc, file}}, nil
+	default:
+		return file, nil
+	}
+}
+
+// DumpParts returns the files that make up a dump. For a split dump's index
+// script this is every part referenced with \i (compressed or not); otherwise
+// it is the file itself.
+func DumpParts(path string) ([]string, error) {
+	if DetectCompression(path) != "none" {
+		return []string{path}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dir := filepath.Dir(path)
+	var parts []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "\\i ") {
+			continue
+		}
+
+		name := strings.TrimSpace(strings.TrimPrefix(line, "\\i "))
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		resolved, err := resolvePart(name)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, resolved)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 0 {
+		return []string{path}, nil
+	}
+	return parts, nil
+}
+
+// resolvePart finds a part on disk, allowing for a compressed extension
+func resolvePart(name string) (string, error) {
+	for _, candidate := range []string{name, name + ".gz", name + ".zst"} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("dump part %s not found", name)
+}
+
+// dumpReader closes a decompressor together with its underlying file
+type dumpReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *dumpReader) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if closeErr := c.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,592 @@
+package pgdump
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"github.com/parquet-go/parquet-go/compress/uncompressed"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+)
+
+const (
+	// DefaultParquetRowGroupSize is the default number of rows per Parquet row group
+	DefaultParquetRowGroupSize = 100000
+
+	// DefaultParquetCompression is the default Parquet compression codec
+	DefaultParquetCompression = "snappy"
+
+	// DefaultParquetTimestampUnit is the default unit for Parquet timestamps
+	DefaultParquetTimestampUnit = "us"
+)
+
+// ParquetOptions configures the Parquet writer
+type ParquetOptions struct {
+	// RowGroupSize is the maximum number of rows buffered per row group
+	RowGroupSize int64
+
+	// Compression codec: "snappy", "zstd" or "none"
+	Compression string
+
+	// TimestampUnit for timestamp columns: "ms", "us" or "ns"
+	TimestampUnit string
+}
+
+// DefaultParquetOptions returns the default Parquet writer options
+func DefaultParquetOptions() ParquetOptions {
+	return ParquetOptions{
+		RowGroupSize:  DefaultParquetRowGroupSize,
+		Compression:   DefaultParquetCompression,
+		TimestampUnit: DefaultParquetTimestampUnit,
+	}
+}
+
+// ParquetWriter writes each table as a separate Apache Parquet file.
+// Files are named <table>.parquet inside the output directory.
+type ParquetWriter struct {
+	dir   string
+	opts  ParquetOptions
+	codec compress.Codec
+	unit  parquet.TimeUnit
+
+	// Current table state
+	file    *os.File
+	writer  *parquet.Writer
+	columns []*parquetColumn
+}
+
+// parquetColumn describes how a schema column maps to a Parquet field
+type parquetColumn struct {
+	name  string
+	kind  parquetKind
+	scale int
+	array bool
+}
+
+// parquetKind is the physical/logical Parquet representation of a column
+type parquetKind int
+
+const (
+	parquetString parquetKind = iota
+	parquetBoolean
+	parquetInt32
+	parquetInt64
+	parquetFloat
+	parquetDouble
+	parquetDecimal64
+	parquetDecimal128
+	parquetDate
+	parquetTimestamp
+	parquetUUID
+	parquetBytes
+)
+
+// NewParquetWriter creates a new Parquet writer that stores files in dir
+func NewParquetWriter(dir string, opts ParquetOptions) (*ParquetWriter, error) {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = DefaultParquetRowGroupSize
+	}
+	if opts.Compression == "" {
+		opts.Compression = DefaultParquetCompression
+	}
+	if opts.TimestampUnit == "" {
+		opts.TimestampUnit = DefaultParquetTimestampUnit
+	}
+
+	var codec compress.Codec
+	switch opts.Compression {
+	case "snappy":
+		codec = &snappy.Codec{}
+	case "zstd":
+		codec = &zstd.Codec{}
+	case "none":
+		codec = &uncompressed.Codec{}
+	default:
+		return nil, fmt.Errorf("unsupported parquet compression: %s (must be one of: snappy, zstd, none)", opts.Compression)
+	}
+
+	var unit parquet.TimeUnit
+	switch opts.TimestampUnit {
+	case "ms":
+		unit = parquet.Millisecond
+	case "us":
+		unit = parquet.Microsecond
+	case "ns":
+		unit = parquet.Nanosecond
+	default:
+		return nil, fmt.Errorf("unsupported parquet timestamp unit: %s (must be one of: ms, us, ns)", opts.TimestampUnit)
+	}
+
+	return &ParquetWriter{
+		dir:   dir,
+		opts:  opts,
+		codec: codec,
+		unit:  unit,
+	}, nil
+}
+
+// WriteSchema prepares the output directory.
+// Parquet has no DDL, so table structure is written with each file's footer.
+func (pw *ParquetWriter) WriteSchema(s *schema.Schema) error {
+	if err := os.MkdirAll(pw.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return nil
+}
+
+// BeginTable creates <table>.parquet and derives its Parquet schema
+func (pw *ParquetWriter) BeginTable(tableName string, table *schema.Table) error {
+	if pw.writer != nil {
+		return fmt.Errorf("table %s: previous table was not finished", tableName)
+	}
+
+	group := parquet.Group{}
+	pw.columns = make([]*parquetColumn, 0, len(table.Columns))
+	for _, col := range table.Columns {
+		pc, node, err := pw.mapColumn(col)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tableName, err)
+		}
+		group[col.Name] = node
+		pw.columns = append(pw.columns, pc)
+	}
+
+	file, err := os.Create(filepath.Join(pw.dir, tableName+".parquet"))
+	if err != nil {
+		return fmt.Errorf("failed to create parquet file: %w", err)
+	}
+
+	pw.file = file
+	pw.writer = parquet.NewWriter(file,
+		parquet.NewSchema(tableName, group),
+		parquet.Compression(pw.codec),
+		parquet.MaxRowsPerRowGroup(pw.opts.RowGroupSize),
+	)
+	return nil
+}
+
+// WriteRow converts a generated row and appends it to the current table.
+// Row groups are flushed automatically once RowGroupSize rows are buffered.
+func (pw *ParquetWriter) WriteRow(row map[string]interface{}) error {
+	if pw.writer == nil {
+		return fmt.Errorf("no table in progress")
+	}
+
+	record := make(map[string]interface{}, len(pw.columns))
+	for _, pc := range pw.columns {
+		val, err := pw.convertValue(pc, row[pc.name])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", pc.name, err)
+		}
+		record[pc.name] = val
+	}
+
+	return pw.writer.Write(record)
+}
+
+// EndTable flushes the last row group and writes the Parquet footer
+func (pw *ParquetWriter) EndTable() error {
+	if pw.writer == nil {
+		return nil
+	}
+
+	err := pw.writer.Close()
+	if closeErr := pw.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// A file without its footer cannot be read
+		os.Remove(pw.file.Name())
+	}
+
+	pw.writer = nil
+	pw.file = nil
+	pw.columns = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to finish parquet file: %w", err)
+	}
+	return nil
+}
+
+// AbortTable closes the current table without writing its footer and
+// removes the partial file
+func (pw *ParquetWriter) AbortTable() error {
+	if pw.writer == nil {
+		return nil
+	}
+
+	pw.file.Close()
+	err := os.Remove(pw.file.Name())
+
+	pw.writer = nil
+	pw.file = nil
+	pw.columns = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to remove partial parquet file: %w", err)
+	}
+	return nil
+}
+
+// mapColumn maps a PostgreSQL column type to a Parquet node
+func (pw *ParquetWriter) mapColumn(col *schema.Column) (*parquetColumn, parquet.Node, error) {
+	baseType, precision, scale, isArray := parsePostgresType(col.Type)
+	pc := &parquetColumn{name: col.Name, scale: scale, array: isArray}
+
+	var node parquet.Node
+	switch baseType {
+	case "smallint", "int2", "integer", "int", "int4", "serial", "smallserial":
+		pc.kind = parquetInt32
+		node = parquet.Int(32)
+	case "bigint", "int8", "bigserial":
+		pc.kind = parquetInt64
+		node = parquet.Int(64)
+	case "real", "float4":
+		pc.kind = parquetFloat
+		node = parquet.Leaf(parquet.FloatType)
+	case "double precision", "float8":
+		pc.kind = parquetDouble
+		node = parquet.Leaf(parquet.DoubleType)
+	case "numeric", "decimal", "money":
+		if baseType == "money" {
+			precision, scale = 18, 2
+			pc.scale = scale
+		}
+		switch {
+		case precision == 0:
+			// Unconstrained numeric has no fixed scale, fall back to double
+			pc.kind = parquetDouble
+			node = parquet.Leaf(parquet.DoubleType)
+		case precision <= 18:
+			pc.kind = parquetDecimal64
+			node = parquet.Decimal(scale, precision, parquet.Int64Type)
+		case precision <= 38:
+			pc.kind = parquetDecimal128
+			node = parquet.Decimal(scale, precision, parquet.FixedLenByteArrayType(16))
+		default:
+			return nil, nil, fmt.Errorf("column %s: numeric precision %d exceeds parquet limit of 38", col.Name, precision)
+		}
+	case "boolean", "bool":
+		pc.kind = parquetBoolean
+		node = parquet.Leaf(parquet.BooleanType)
+	case "date":
+		pc.kind = parquetDate
+		node = parquet.Date()
+	case "timestamp", "timestamp without time zone":
+		pc.kind = parquetTimestamp
+		node = parquet.TimestampAdjusted(pw.unit, false)
+	case "timestamptz", "timestamp with time zone":
+		pc.kind = parquetTimestamp
+		node = parquet.TimestampAdjusted(pw.unit, true)
+	case "uuid":
+		pc.kind = parquetUUID
+		node = parquet.UUID()
+	case "bytea":
+		pc.kind = parquetBytes
+		node = parquet.Leaf(parquet.ByteArrayType)
+	default:
+		// Text, JSON/JSONB, network, geometric and other types are stored as
+		// UTF8 strings so that any Parquet reader can decode them
+		pc.kind = parquetString
+		node = parquet.String()
+	}
+
+	if isArray {
+		node = parquet.List(node)
+	}
+	if col.Nullable {
+		node = parquet.Optional(node)
+	}
+
+	return pc, node, nil
+}
+
+// convertValue converts a generated value to the Go representation
+// expected by the Parquet column
+func (pw *ParquetWriter) convertValue(pc *parquetColumn, val interface{}) (interface{}, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	if !pc.array {
+		return pw.convertScalar(pc, val)
+	}
+
+	// Arrays accept any slice; a scalar becomes a single-element list
+	var items []interface{}
+	switch v := val.(type) {
+	case []interface{}:
+		items = v
+	case []string:
+		for _, s := range v {
+			items = append(items, s)
+		}
+	case []int64:
+		for _, n := range v {
+			items = append(items, n)
+		}
+	default:
+		items = []interface{}{v}
+	}
+
+	list := make([]interface{}, len(items))
+	for i, item := range items {
+		converted, err := pw.convertScalar(pc, item)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = converted
+	}
+	return list, nil
+}
+
+// convertScalar converts a single non-array value
+func (pw *ParquetWriter) convertScalar(pc *parquetColumn, val interface{}) (interface{}, error) {
+	switch pc.kind {
+	case parquetInt32:
+		n, err := parquetInt(val)
+		if err != nil {
+			return nil, err
+		}
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, fmt.Errorf("value %d overflows int32", n)
+		}
+		return int32(n), nil
+
+	case parquetInt64:
+		return parquetInt(val)
+
+	case parquetFloat:
+		f, err := parquetFloat64(val)
+		return float32(f), err
+
+	case parquetDouble:
+		return parquetFloat64(val)
+
+	case parquetDecimal64:
+		unscaled, err := parquetUnscaled(val, pc.scale)
+		if err != nil {
+			return nil, err
+		}
+		if !unscaled.IsInt64() {
+			return nil, fmt.Errorf("value %v overflows decimal", val)
+		}
+		return unscaled.Int64(), nil
+
+	case parquetDecimal128:
+		unscaled, err := parquetUnscaled(val, pc.scale)
+		if err != nil {
+			return nil, err
+		}
+		return decimalBytes(unscaled)
+
+	case parquetBoolean:
+		switch v := val.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		default:
+			return nil, fmt.Errorf("cannot convert %T to boolean", val)
+		}
+
+	case parquetDate:
+		t, err := parquetTime(val)
+		if err != nil {
+			return nil, err
+		}
+		days := t.UTC().Truncate(24*time.Hour).Unix() / 86400
+		return int32(days), nil
+
+	case parquetTimestamp:
+		t, err := parquetTime(val)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnixNano() / int64(pw.unit.Duration()), nil
+
+	case parquetUUID:
+		s := strings.ReplaceAll(fmt.Sprintf("%v", val), "-", "")
+		var id [16]byte
+		if len(s) != 32 {
+			return nil, fmt.Errorf("invalid uuid %q", val)
+		}
+		for i := 0; i < 16; i++ {
+			b, err := strconv.ParseUint(s[i*2:i*2+2], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid uuid %q", val)
+			}
+			id[i] = byte(b)
+		}
+		return id, nil
+
+	case parquetBytes:
+		if b, ok := val.([]byte); ok {
+			return b, nil
+		}
+		return []byte(fmt.Sprintf("%v", val)), nil
+
+	default:
+		switch v := val.(type) {
+		case string:
+			return v, nil
+		case time.Time:
+			return v.Format("2006-01-02 15:04:05"), nil
+		case map[string]interface{}, []interface{}:
+			// JSON documents are stored as their serialized text
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		default:
+			return fmt.Sprintf("%v", v), nil
+		}
+	}
+}
+
+// parsePostgresType splits a PostgreSQL type into base type, precision, scale
+// and whether it is an array (e.g. "numeric(10,2)" → "numeric", 10, 2, false)
+func parsePostgresType(typeName string) (string, int, int, bool) {
+	t := strings.ToLower(strings.TrimSpace(typeName))
+
+	isArray := false
+	if strings.HasSuffix(t, "[]") {
+		isArray = true
+		t = strings.TrimSpace(strings.TrimSuffix(t, "[]"))
+	}
+
+	precision, scale := 0, 0
+	if open := strings.Index(t, "("); open >= 0 {
+		end := strings.Index(t, ")")
+		if end < open {
+			end = len(t)
+		}
+		params := t[open+1 : end]
+		rest := ""
+		if end < len(t) {
+			rest = strings.TrimSpace(t[end+1:])
+		}
+		t = strings.TrimSpace(strings.TrimSpace(t[:open]) + " " + rest)
+		parts := strings.Split(params, ",")
+		precision, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+		if len(parts) > 1 {
+			scale, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+		}
+	}
+
+	return strings.TrimSpace(t), precision, scale, isArray
+}
+
+// parquetInt converts a generated value to int64
+func parquetInt(val interface{}) (int64, error) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return int64(v), nil
+	case float64:
+		return int64(v), nil
+	case float32:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to integer", val)
+	}
+}
+
+// parquetFloat64 converts a generated value to float64
+func parquetFloat64(val interface{}) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		n, err := parquetInt(val)
+		return float64(n), err
+	}
+}
+
+// parquetUnscaled returns the unscaled integer of a decimal value
+func parquetUnscaled(val interface{}, scale int) (*big.Int, error) {
+	r := new(big.Rat)
+	switch v := val.(type) {
+	case string:
+		if _, ok := r.SetString(v); !ok {
+			return nil, fmt.Errorf("invalid decimal %q", v)
+		}
+	case float64:
+		r.SetFloat64(v)
+	case float32:
+		r.SetFloat64(float64(v))
+	default:
+		n, err := parquetInt(val)
+		if err != nil {
+			return nil, err
+		}
+		r.SetInt64(n)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+
+	// Round half away from zero
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+	return q, nil
+}
+
+// decimalBytes encodes an unscaled decimal as 16-byte big-endian two's complement
+func decimalBytes(n *big.Int) ([]byte, error) {
+	if n.BitLen() > 127 {
+		return nil, fmt.Errorf("value %s overflows decimal(38)", n.String())
+	}
+
+	v := new(big.Int).Set(n)
+	if v.Sign() < 0 {
+		v.Add(v, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+
+	out := make([]byte, 16)
+	v.FillBytes(out)
+	return out, nil
+}
+
+// parquetTime converts a generated value to time.Time
+func parquetTime(val interface{}) (time.Time, error) {
+	switch v := val.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", v)
+	default:
+		return time.Time{}, fmt.Errorf("cannot convert %T to timestamp", val)
+	}
+}
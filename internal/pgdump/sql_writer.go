@@ -10,7 +10,8 @@ import (
 
 // SQLWriter writes PostgreSQL dump in SQL format
 type SQLWriter struct {
-	w io.Writer
+	w     io.Writer
+	names map[string]string
 }
 
 // NewSQLWriter creates a new SQL format writer
@@ -26,26 +27,50 @@ func (sw *SQLWriter) WriteSchema(s *schema.Schema) error {
 	fmt.Fprintf(sw.w, "-- Generated by datagen\n")
 	fmt.Fprintf(sw.w, "--\n\n")
 
-	// Write database creation
-	fmt.Fprintf(sw.w, "CREATE DATABASE %s WITH ENCODING = '%s';\n\n",
-		s.Database.Name, s.Database.Encoding)
+	// Write database creation (skipped for managed databases and test transactions)
+	if s.Database.ShouldCreateDatabase() {
+		fmt.Fprintf(sw.w, "CREATE DATABASE %s WITH ENCODING = '%s';\n\n",
+			s.Database.Name, s.Database.Encoding)
 
-	fmt.Fprintf(sw.w, "\\connect %s\n\n", s.Database.Name)
+		fmt.Fprintf(sw.w, "\\connect %s\n\n", s.Database.Name)
+	}
+
+	// Write namespaces and qualify table names with them
+	writeNamespaces(sw.w, s, plainIdentifier, true)
+	sw.names = qualifiedTableNames(s, plainIdentifier)
 
-	// Write CREATE TABLE statements
-	for tableName, table := range s.Tables {
-		if err := sw.WriteCreateTable(tableName, table); err != nil {
+	// Write CREATE TABLE statements (referenced tables first)
+	for _, tableName := range schema.TableOrder(s) {
+		if err := sw.WriteCreateTable(tableName, s.Tables[tableName]); err != nil {
 			return err
 		}
 		fmt.Fprintf(sw.w, "\n")
 	}
 
+	// Foreign keys that close a cycle are checked at commit time
+	writeDeferredForeignKeys(sw.w, s, sw.names, plainIdentifier)
+
+	return nil
+}
+
+// WriteDataHeader writes the header of a data-only dump (no DDL)
+func (sw *SQLWriter) WriteDataHeader(s *schema.Schema) error {
+	fmt.Fprintf(sw.w, "--\n")
+	fmt.Fprintf(sw.w, "-- PostgreSQL database dump (data only)\n")
+	fmt.Fprintf(sw.w, "-- Generated by datagen\n")
+	fmt.Fprintf(sw.w, "--\n\n")
+
+	if s.Database.ShouldCreateDatabase() {
+		fmt.Fprintf(sw.w, "\\connect %s\n\n", s.Database.Name)
+	}
+	writeNamespaces(sw.w, s, plainIdentifier, false)
+	sw.names = qualifiedTableNames(s, plainIdentifier)
 	return nil
 }
 
 // WriteCreateTable writes a CREATE TABLE statement
 func (sw *SQLWriter) WriteCreateTable(tableName string, table *schema.Table) error {
-	fmt.Fprintf(sw.w, "CREATE TABLE %s (\n", tableName)
+	fmt.Fprintf(sw.w, "CREATE TABLE %s (\n", sw.tableName(tableName))
 
 	// Write columns
 	for i, col := range table.Columns {
@@ -75,13 +100,13 @@ func (sw *SQLWriter) WriteCreateTable(tableName string, table *schema.Table) err
 	// Write indexes
 	for _, idx := range table.Indexes {
 		fmt.Fprintf(sw.w, "CREATE INDEX %s ON %s (%s);\n",
-			idx.Name, tableName, strings.Join(idx.Columns, ", "))
+			idx.Name, sw.tableName(tableName), strings.Join(idx.Columns, ", "))
 	}
 
 	// Write unique constraints
 	for _, uc := range table.UniqueConstraints {
 		fmt.Fprintf(sw.w, "ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s);\n",
-			tableName, uc.Name, strings.Join(uc.Columns, ", "))
+			sw.tableName(tableName), uc.Name, strings.Join(uc.Columns, ", "))
 	}
 
 	return nil
@@ -90,7 +115,7 @@ func (sw *SQLWriter) WriteCreateTable(tableName string, table *schema.Table) err
 // WriteInsert writes an INSERT statement for a single row
 func (sw *SQLWriter) WriteInsert(tableName string, columns []string, row map[string]interface{}) error {
 	fmt.Fprintf(sw.w, "INSERT INTO %s (%s) VALUES (",
-		tableName, strings.Join(columns, ", "))
+		sw.tableName(tableName), strings.Join(columns, ", "))
 
 	for i, col := range columns {
 		val := row[col]
@@ -105,6 +130,35 @@ func (sw *SQLWriter) WriteInsert(tableName string, columns []string, row map[str
 	return nil
 }
 
+// WriteUpdate writes an UPDATE statement setting columns of the row
+// identified by its key columns
+func (sw *SQLWriter) WriteUpdate(tableName string, setColumns, keyColumns []string, row map[string]interface{}) error {
+	fmt.Fprintf(sw.w, "UPDATE %s SET %s WHERE %s;\n", sw.tableName(tableName),
+		assignmentList(setColumns, row, plainIdentifier, FormatValue, ", "),
+		assignmentList(keyColumns, row, plainIdentifier, FormatValue, " AND "))
+	return nil
+}
+
+// BeginTransaction starts the data section of the dump
+func (sw *SQLWriter) BeginTransaction() error {
+	writeBeginTransaction(sw.w)
+	return nil
+}
+
+// CommitTransaction ends the data section of the dump
+func (sw *SQLWriter) CommitTransaction() error {
+	writeCommitTransaction(sw.w)
+	return nil
+}
+
+// tableName returns the (namespace-qualified) name of a table
+func (sw *SQLWriter) tableName(tableName string) string {
+	if name, ok := sw.names[tableName]; ok {
+		return name
+	}
+	return tableName
+}
+
 // formatValue formats a value for SQL using the escape module
 func (sw *SQLWriter) formatValue(val interface{}) string {
 	return FormatValue(val)
@@ -130,8 +184,12 @@ func (sw *SQLWriter) WriteBatchInsert(tableName string, columns []string, rows [
 		batch := rows[i:end]
 
 		// Write INSERT statement header
+		name, ok := sw.names[tableName]
+		if !ok {
+			name = EscapeIdentifier(tableName)
+		}
 		fmt.Fprintf(sw.w, "INSERT INTO %s (%s) VALUES\n",
-			EscapeIdentifier(tableName), FormatIdentifierList(columns))
+			name, FormatIdentifierList(columns))
 
 		// Write value rows
 		for j, row := range batch {
@@ -26,6 +26,39 @@ type COPYRowWriter interface {
 	WriteCopyFooter() error
 }
 
+// TableWriter interface for writers that store each table separately
+// (e.g. columnar formats such as Parquet)
+type TableWriter interface {
+	Writer
+	BeginTable(tableName string, table *schema.Table) error
+	WriteRow(row map[string]interface{}) error
+	EndTable() error
+
+	// AbortTable discards the table in progress, removing its partial output
+	AbortTable() error
+}
+
+// DataOnlyWriter interface for writers that can start a dump without any DDL
+type DataOnlyWriter interface {
+	Writer
+	WriteDataHeader(s *schema.Schema) error
+}
+
+// UpdateWriter interface for writers that can set columns of rows already
+// written (used to close foreign key cycles after loading)
+type UpdateWriter interface {
+	Writer
+	WriteUpdate(tableName string, setColumns, keyColumns []string, row map[string]interface{}) error
+}
+
+// TransactionWriter interface for writers that can load data in a single
+// transaction with deferred constraint checks
+type TransactionWriter interface {
+	Writer
+	BeginTransaction() error
+	CommitTransaction() error
+}
+
 // NewWriter creates a writer based on the specified format
 func NewWriter(output io.Writer, format string) (Writer, error) {
 	switch format {
@@ -33,6 +66,8 @@ func NewWriter(output io.Writer, format string) (Writer, error) {
 		return NewSQLWriter(output), nil
 	case "copy":
 		return NewCOPYWriter(output), nil
+	case "parquet":
+		return nil, fmt.Errorf("parquet format writes one file per table, use NewParquetWriter with an output directory")
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
@@ -49,3 +84,27 @@ func IsCOPYRowWriter(w Writer) (COPYRowWriter, bool) {
 	cw, ok := w.(COPYRowWriter)
 	return cw, ok
 }
+
+// IsDataOnlyWriter checks if a writer can write a data-only dump header
+func IsDataOnlyWriter(w Writer) (DataOnlyWriter, bool) {
+	dw, ok := w.(DataOnlyWriter)
+	return dw, ok
+}
+
+// IsUpdateWriter checks if a writer supports UPDATE statements
+func IsUpdateWriter(w Writer) (UpdateWriter, bool) {
+	uw, ok := w.(UpdateWriter)
+	return uw, ok
+}
+
+// IsTransactionWriter checks if a writer can wrap data in a transaction
+func IsTransactionWriter(w Writer) (TransactionWriter, bool) {
+	tw, ok := w.(TransactionWriter)
+	return tw, ok
+}
+
+// IsTableWriter checks if a writer stores each table separately
+func IsTableWriter(w Writer) (TableWriter, bool) {
+	tw, ok := w.(TableWriter)
+	return tw, ok
+}
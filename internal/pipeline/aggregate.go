@@ -0,0 +1,266 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// numericScale matches the scale of numeric(p,s) and decimal(p,s) types
+var numericScale = regexp.MustCompile(`^(?:numeric|decimal)\s*\(\s*\d+\s*,\s*(\d+)\s*\)$`)
+
+// aggregateColumn is a parent column summarising the child rows that reference it
+type aggregateColumn struct {
+	table  string // parent table
+	column *schema.Column
+	agg    *schema.Aggregate
+	fk     *schema.ForeignKey // child foreign key to the parent
+	states map[int]*aggregateState
+}
+
+// aggregateState accumulates the child values of one parent row
+type aggregateState struct {
+	count    int
+	sum      float64
+	min, max float64
+	integral bool // every value was an integer
+}
+
+// planAggregates collects the aggregate columns of the required tables,
+// indexed by the child table whose rows feed them
+func (c *Coordinator) planAggregates(s *schema.Schema, required map[string]bool) {
+	c.aggregates = make(map[string][]*aggregateColumn)
+	for tableName := range required {
+		table := s.Tables[tableName]
+		for _, col := range table.Columns {
+			agg, _ := col.Aggregate()
+			if agg == nil {
+				continue
+			}
+			fk := s.Tables[agg.Table].ForeignKeyFor(agg.Via)
+			c.aggregates[agg.Table] = append(c.aggregates[agg.Table], &aggregateColumn{
+				table: tableName, column: col, agg: agg, fk: fk,
+				states: make(map[int]*aggregateState),
+			})
+			c.keys.Track(tableName, table.PrimaryKeyColumns())
+		}
+	}
+}
+
+// aggregateSources returns the child tables the aggregate columns of a table read
+func aggregateSources(table *schema.Table) []string {
+	var sources []string
+	for _, col := range table.Columns {
+		if agg, _ := col.Aggregate(); agg != nil {
+			sources = append(sources, agg.Table)
+		}
+	}
+	return sources
+}
+
+// checkAggregates verifies that the writer can set aggregate columns after loading
+func checkAggregates(s *schema.Schema, selected map[string]bool, writer pgdump.Writer, mode OutputMode) error {
+	if mode == ModeSchemaOnly {
+		return nil
+	}
+	if _, ok := pgdump.IsUpdateWriter(writer); ok {
+		return nil
+	}
+
+	for tableName := range selected {
+		if sources := aggregateSources(s.Tables[tableName]); len(sources) > 0 {
+			return fmt.Errorf("table %s: aggregate columns require an output format that supports UPDATE statements", tableName)
+		}
+	}
+	return nil
+}
+
+// aggregatePlaceholder is the value an aggregate column is inserted with
+// before its UPDATE statement: zero for NOT NULL columns, NULL otherwise
+func aggregatePlaceholder(col *schema.Column) interface{} {
+	if col.Nullable {
+		return nil
+	}
+	return int64(0)
+}
+
+// resetAggregates discards the values accumulated from a child table before
+// it is generated again
+func (c *Coordinator) resetAggregates(tableName string) {
+	for _, target := range c.aggregates[tableName] {
+		target.states = make(map[int]*aggregateState)
+	}
+}
+
+// accumulateAggregates adds a generated child row to the aggregates of the
+// parent row it references
+func (c *Coordinator) accumulateAggregates(tableName string, row map[string]interface{}) {
+	for _, target := range c.aggregates[tableName] {
+		parent, ok := c.rowParents[target.fk]
+		if !ok {
+			continue
+		}
+
+		value, integral, ok := 1.0, true, true
+		for _, factor := range target.agg.Factors {
+			raw, isColumn := row[factor]
+			if !isColumn {
+				raw = factor
+			}
+			n, isInt, isNumber := toNumber(raw)
+			if !isNumber {
+				ok = false
+				break
+			}
+			value *= n
+			integral = integral && isInt
+		}
+		if !ok {
+			continue // NULL (or non-numeric) values are ignored, like in SQL
+		}
+
+		state := target.states[parent]
+		if state == nil {
+			state = &aggregateState{min: value, max: value, integral: true}
+			target.states[parent] = state
+		}
+		state.count++
+		state.sum += value
+		state.min = math.Min(state.min, value)
+		state.max = math.Max(state.max, value)
+		state.integral = state.integral && integral
+	}
+}
+
+// writeAggregateUpdates sets the aggregate columns of the selected tables
+// once all child rows have been generated
+func (c *Coordinator) writeAggregateUpdates(writer pgdump.Writer, order []string, selected map[string]bool) error {
+	updater, ok := pgdump.IsUpdateWriter(writer)
+	if !ok {
+		return nil
+	}
+
+	byTable := make(map[string][]*aggregateColumn)
+	for _, targets := range c.aggregates {
+		for _, target := range targets {
+			byTable[target.table] = append(byTable[target.table], target)
+		}
+	}
+
+	for _, tableName := range order {
+		targets := byTable[tableName]
+		if !selected[tableName] || len(targets) == 0 {
+			continue
+		}
+		keyColumns := c.schema.Tables[tableName].PrimaryKeyColumns()
+		var setColumns []string
+		for _, col := range c.schema.Tables[tableName].Columns {
+			for _, target := range targets {
+				if target.column == col {
+					setColumns = append(setColumns, col.Name)
+				}
+			}
+		}
+
+		for rowIdx := 0; rowIdx < c.keys.Count(tableName, keyColumns); rowIdx++ {
+			key, _ := c.keys.Get(tableName, keyColumns, rowIdx)
+			row := make(map[string]interface{}, len(keyColumns)+len(targets))
+			for i, col := range keyColumns {
+				row[col] = key[i]
+			}
+			for _, target := range targets {
+				row[target.column.Name] = target.result(rowIdx)
+			}
+
+			if err := updater.WriteUpdate(tableName, setColumns, keyColumns, row); err != nil {
+				return fmt.Errorf("failed to write update: %w", err)
+			}
+
+			if c.splitter != nil && c.splitter.NeedsSplit() {
+				if err := c.splitter.Split(); err != nil {
+					return fmt.Errorf("failed to split output: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// result returns the aggregate value of a parent row, typed for its column.
+// Parents without children get 0 for count and sum and NULL otherwise.
+func (target *aggregateColumn) result(parent int) interface{} {
+	state := target.states[parent]
+	if target.agg.Function == "count" {
+		if state == nil {
+			return int64(0)
+		}
+		return int64(state.count)
+	}
+	if state == nil {
+		if target.agg.Function == "sum" || !target.column.Nullable {
+			return int64(0)
+		}
+		return nil
+	}
+
+	var value float64
+	integral := state.integral
+	switch target.agg.Function {
+	case "sum":
+		value = state.sum
+	case "min":
+		value = state.min
+	case "max":
+		value = state.max
+	case "avg":
+		value = state.sum / float64(state.count)
+		integral = false
+	}
+	return typedNumber(target.column.Type, value, integral)
+}
+
+// typedNumber converts an aggregate to the column's type: integers for integer
+// columns (and integral values), exact decimal text for numeric(p,s) columns
+func typedNumber(columnType string, value float64, integral bool) interface{} {
+	switch columnType {
+	case "integer", "int", "bigint", "smallint":
+		return int64(math.Round(value))
+	}
+	if m := numericScale.FindStringSubmatch(columnType); m != nil {
+		scale, _ := strconv.Atoi(m[1])
+		return strconv.FormatFloat(value, 'f', scale, 64)
+	}
+	if integral {
+		return int64(value)
+	}
+	return value
+}
+
+// toNumber converts a generated value to a number, reporting whether it is
+// an integer
+func toNumber(v interface{}) (float64, bool, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true, true
+	case int32:
+		return float64(n), true, true
+	case int64:
+		return float64(n), true, true
+	case float32:
+		return float64(n), false, true
+	case float64:
+		return n, n == math.Trunc(n), true
+	case string:
+		if i, err := strconv.ParseInt(n, 10, 64); err == nil {
+			return float64(i), true, true
+		}
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return f, false, true
+		}
+	}
+	return 0, false, false
+}
@@ -0,0 +1,170 @@
+package pipeline
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// defaultCardinalityAlpha is the zipf exponent used when none is configured
+const defaultCardinalityAlpha = 1.5
+
+// childPlan assigns every row of a child table to a parent row
+type childPlan struct {
+	fk      *schema.ForeignKey
+	parents []int // index of the parent key for each child row
+}
+
+// PlanChildren decides how many children each of parentCount parents gets and
+// returns the parent index of every child row, grouped by parent. When rowCount
+// is positive the per-parent counts are scaled towards rowCount rows, keeping
+// each within min and max; fewer or more rows result when rowCount is out of
+// reach.
+func PlanChildren(rng *rand.Rand, c *schema.Cardinality, parentCount, rowCount int) []int {
+	if parentCount == 0 {
+		return nil
+	}
+
+	counts := drawCounts(rng, c, parentCount)
+	if rowCount > 0 {
+		counts = scaleCounts(counts, rowCount, c)
+	}
+
+	var parents []int
+	for parent, n := range counts {
+		for j := 0; j < n; j++ {
+			parents = append(parents, parent)
+		}
+	}
+	return parents
+}
+
+// drawCounts draws a child count for each of n parents, leaving the share of
+// parents given by the empty ratio without children
+func drawCounts(rng *rand.Rand, c *schema.Cardinality, n int) []int {
+	draw := cardinalityDrawer(rng, c)
+	counts := make([]int, n)
+	for i := range counts {
+		if c.EmptyRatio > 0 && rng.Float64() < c.EmptyRatio {
+			continue
+		}
+		counts[i] = draw()
+	}
+	return counts
+}
+
+// cardinalityDrawer returns a function drawing a child count in [min, max]
+func cardinalityDrawer(rng *rand.Rand, c *schema.Cardinality) func() int {
+	span := c.Max - c.Min
+	if span <= 0 {
+		return func() int { return c.Min }
+	}
+
+	switch c.Distribution {
+	case "zipf":
+		// Skewed towards min: most parents get few children, a few get many
+		alpha := defaultCardinalityAlpha
+		if c.Alpha != nil {
+			alpha = *c.Alpha
+		}
+		zipf := rand.NewZipf(rng, alpha, 1, uint64(span))
+		return func() int { return c.Min + int(zipf.Uint64()) }
+	case "normal":
+		// Centred between min and max with the bounds three deviations away
+		mean := float64(c.Min+c.Max) / 2
+		stdDev := float64(span) / 6
+		return func() int {
+			n := int(math.Round(rng.NormFloat64()*stdDev + mean))
+			if n < c.Min {
+				return c.Min
+			}
+			if n > c.Max {
+				return c.Max
+			}
+			return n
+		}
+	default:
+		return func() int { return c.Min + rng.Intn(span+1) }
+	}
+}
+
+// scaleCounts scales counts proportionally so they sum to total, using the
+// largest remainder method to keep the result exact. Counts stay within the
+// cardinality's min and max, and parents left empty stay empty unless total
+// cannot be reached otherwise.
+func scaleCounts(counts []int, total int, c *schema.Cardinality) []int {
+	sum := 0
+	for _, n := range counts {
+		sum += n
+	}
+	if sum == total {
+		return counts
+	}
+	empty := make([]bool, len(counts))
+	if sum == 0 {
+		// Every parent came up empty; spread the rows evenly instead
+		for i := range counts {
+			counts[i] = 1
+		}
+		sum = len(counts)
+	} else {
+		for i, n := range counts {
+			empty[i] = n == 0
+		}
+	}
+
+	scaled := make([]int, len(counts))
+	remainders := make([]int, len(counts))
+	fractions := make([]float64, len(counts))
+	for i, n := range counts {
+		exact := float64(n) * float64(total) / float64(sum)
+		scaled[i] = int(exact)
+		fractions[i] = exact - float64(scaled[i])
+		remainders[i] = i
+	}
+
+	sort.SliceStable(remainders, func(a, b int) bool {
+		return fractions[remainders[a]] > fractions[remainders[b]]
+	})
+
+	// Clamp the parents with children to min and max, then move the rows
+	// still missing or in excess between parents that have room: first
+	// within the parents with children, then filling or emptying parents
+	assigned := 0
+	for i := range scaled {
+		if !empty[i] {
+			scaled[i] = max(c.Min, min(c.Max, scaled[i]))
+		}
+		assigned += scaled[i]
+	}
+	adjust := func(fillOrEmpty bool) {
+		for changed := true; changed && assigned != total; {
+			changed = false
+			for _, i := range remainders {
+				if assigned == total || (empty[i] && !fillOrEmpty) {
+					continue
+				}
+				n, grow := scaled[i], max(c.Min, 1)
+				switch {
+				case assigned < total && n == 0 && total-assigned >= grow && grow <= c.Max:
+					scaled[i] = grow
+				case assigned < total && n > 0 && n < c.Max:
+					scaled[i]++
+				case assigned > total && n > c.Min:
+					scaled[i]--
+				case assigned > total && fillOrEmpty && n > 0 && n <= assigned-total:
+					scaled[i] = 0
+				default:
+					continue
+				}
+				assigned += scaled[i] - n
+				changed = true
+			}
+		}
+	}
+	adjust(false)
+	adjust(true)
+	return scaled
+}
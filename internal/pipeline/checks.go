@@ -0,0 +1,851 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/expression"
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	pg_query "github.com/pganalyze/pg_query_go/v6"
+)
+
+// checkBudget is how many times the columns of a row are regenerated to
+// satisfy its CHECK constraints before the row is kept as it is
+const checkBudget = 100
+
+// tableChecks holds the CHECK constraints of a table, compiled for generation.
+// Each constraint is split into the conditions joined by its top-level ANDs.
+// Comparisons of a single column with constants become bounds applied as the
+// column is generated; the remaining conditions are checked once the row is
+// complete, regenerating the columns they read until they hold.
+type tableChecks struct {
+	table       string
+	constraints []*checkStatus
+	conditions  []*checkCondition
+	bounds      map[string]*valueBounds // by column
+}
+
+// checkStatus tracks how generation fared against one CHECK constraint
+type checkStatus struct {
+	label       string
+	unsupported error // why (part of) the constraint is not enforced
+	enforced    int   // conditions that are
+	failures    int   // rows left violating it
+	evalErr     error // first error evaluating it
+}
+
+// checkCondition is one condition of a CHECK constraint, translated to the
+// expression language
+type checkCondition struct {
+	status *checkStatus
+	expr   *expression.Expression
+}
+
+// tableChecks returns the compiled CHECK constraints of a table, or nil when it has none
+func (c *Coordinator) tableChecks(tableName string, table *schema.Table) *tableChecks {
+	if len(table.CheckConstraints) == 0 {
+		return nil
+	}
+	if checks, ok := c.checks[table]; ok {
+		return checks
+	}
+
+	checks := compileChecks(tableName, table)
+	if c.checks == nil {
+		c.checks = make(map[*schema.Table]*tableChecks)
+	}
+	c.checks[table] = checks
+	c.checkOrder = append(c.checkOrder, checks)
+	return checks
+}
+
+func compileChecks(tableName string, table *schema.Table) *tableChecks {
+	checks := &tableChecks{table: tableName, bounds: make(map[string]*valueBounds)}
+	for _, constraint := range table.CheckConstraints {
+		status := &checkStatus{label: "CHECK (" + constraint.Expression + ")"}
+		if constraint.Name != "" {
+			status.label = fmt.Sprintf("%s CHECK (%s)", constraint.Name, constraint.Expression)
+		}
+		checks.constraints = append(checks.constraints, status)
+
+		root, err := parseCheck(constraint.Expression)
+		if err != nil {
+			status.unsupported = err
+			continue
+		}
+		for _, cond := range conjuncts(root) {
+			addBound(table, cond, checks.bounds)
+
+			source, err := checkSource(cond)
+			var expr *expression.Expression
+			if err == nil {
+				expr, err = expression.Parse(source)
+			}
+			if err != nil {
+				status.unsupported = err
+				continue
+			}
+			checks.conditions = append(checks.conditions, &checkCondition{status: status, expr: expr})
+			status.enforced++
+		}
+	}
+	return checks
+}
+
+// enforceChecks regenerates the columns of a row that break its CHECK
+// constraints. Columns set before generation (foreign keys, polymorphic and
+// hierarchy columns) and serial columns are never regenerated.
+func (c *Coordinator) enforceChecks(ctx *generator.Context, table *schema.Table, row map[string]interface{}, preset map[string]bool) error {
+	checks := c.tableChecks(ctx.TableName, table)
+	if checks == nil {
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Conditions that fail to evaluate have values of the wrong type,
+		// which regenerating does not fix
+		var failing, fixable []*checkCondition
+		for _, cond := range checks.conditions {
+			ok, err := cond.holds(row)
+			if !ok {
+				failing = append(failing, cond)
+			}
+			if err == nil && !ok {
+				fixable = append(fixable, cond)
+			}
+		}
+		if len(failing) == 0 {
+			return nil
+		}
+
+		regenerate := c.checkColumns(table, fixable, preset)
+		if attempt == checkBudget || len(regenerate) == 0 {
+			if !c.keysOnly {
+				counted := make(map[*checkStatus]bool)
+				for _, cond := range failing {
+					if !counted[cond.status] {
+						counted[cond.status] = true
+						cond.status.failures++
+					}
+				}
+			}
+			return nil
+		}
+
+		for _, col := range c.columnOrder(table) {
+			if regenerate[col.Name] {
+				if err := c.generateColumn(ctx, table, col, row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// holds reports whether a condition is not false for a row. As in
+// PostgreSQL, a NULL result satisfies the constraint.
+func (cond *checkCondition) holds(row map[string]interface{}) (bool, error) {
+	val, err := cond.expr.Eval(row)
+	if err != nil {
+		if cond.status.evalErr == nil {
+			cond.status.evalErr = err
+		}
+		return false, err
+	}
+	return val != false, nil
+}
+
+// checkColumns returns the columns to regenerate for failing conditions: the
+// columns they read and those these are derived from, then every column
+// derived from a regenerated one
+func (c *Coordinator) checkColumns(table *schema.Table, failing []*checkCondition, preset map[string]bool) map[string]bool {
+	regenerable := func(col *schema.Column) bool {
+		return !preset[col.Name] && c.mapTypeToGenerator(col.Type) != "serial"
+	}
+	// The timestamps of a lifecycle are regenerated with its status column
+	generatedBy := func(name string) string {
+		if owner := table.LifecycleOwner(name); owner != nil {
+			return owner.Name
+		}
+		return name
+	}
+
+	regenerate := make(map[string]bool)
+	seen := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		name = generatedBy(name)
+		col := table.Column(name)
+		if col == nil || seen[name] {
+			return
+		}
+		seen[name] = true
+		if regenerable(col) {
+			regenerate[name] = true
+		}
+		for _, dep := range col.RowDependencies() {
+			visit(dep)
+		}
+	}
+	for _, cond := range failing {
+		for _, name := range cond.expr.Columns() {
+			visit(name)
+		}
+	}
+
+	// Columns are in dependency order, so one pass reaches every derived column
+	for _, col := range c.columnOrder(table) {
+		if regenerate[col.Name] || !regenerable(col) || generatedBy(col.Name) != col.Name {
+			continue
+		}
+		for _, dep := range col.RowDependencies() {
+			if regenerate[generatedBy(dep)] {
+				regenerate[col.Name] = true
+				break
+			}
+		}
+	}
+	return regenerate
+}
+
+// boundValue keeps a generated value within the bounds CHECK constraints
+// place on its column, drawing a new one when it falls outside
+func (c *Coordinator) boundValue(ctx *generator.Context, table *schema.Table, col *schema.Column, val interface{}) interface{} {
+	checks := c.tableChecks(ctx.TableName, table)
+	if checks == nil || col.Expression != "" || c.mapTypeToGenerator(col.Type) == "serial" {
+		return val
+	}
+	if bounds := checks.bounds[col.Name]; bounds != nil && !bounds.admits(val) {
+		// Values the bounds cannot supply are left to enforceChecks
+		if picked, ok := bounds.pick(ctx.Rand, col.Type); ok {
+			return picked
+		}
+	}
+	return val
+}
+
+// Warnings describes the settings, such as CHECK constraints, generation
+// could not honour
+func (c *Coordinator) Warnings() []string {
+	warnings := append([]string(nil), c.warnings...)
+	for _, checks := range c.checkOrder {
+		for _, status := range checks.constraints {
+			switch {
+			case status.unsupported != nil && status.enforced == 0:
+				warnings = append(warnings, fmt.Sprintf("table %s: %s is not supported and was not enforced: %v", checks.table, status.label, status.unsupported))
+			case status.unsupported != nil:
+				warnings = append(warnings, fmt.Sprintf("table %s: %s was only partly enforced: %v", checks.table, status.label, status.unsupported))
+			case status.evalErr != nil:
+				warnings = append(warnings, fmt.Sprintf("table %s: %s is violated by %d row(s): %v", checks.table, status.label, status.failures, status.evalErr))
+			case status.failures > 0:
+				warnings = append(warnings, fmt.Sprintf("table %s: %s is violated by %d row(s)", checks.table, status.label, status.failures))
+			}
+		}
+	}
+	return warnings
+}
+
+// parseCheck parses a CHECK expression with the PostgreSQL parser
+func parseCheck(source string) (*pg_query.Node, error) {
+	result, err := pg_query.Parse("SELECT " + source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	if len(result.Stmts) != 1 {
+		return nil, fmt.Errorf("expected a single expression")
+	}
+	sel := result.Stmts[0].GetStmt().GetSelectStmt()
+	if sel == nil || len(sel.GetTargetList()) != 1 || len(sel.GetFromClause()) > 0 || sel.GetWhereClause() != nil {
+		return nil, fmt.Errorf("expected a single expression")
+	}
+	return sel.GetTargetList()[0].GetResTarget().GetVal(), nil
+}
+
+// conjuncts splits an expression at its top-level ANDs. A conjunction is
+// false exactly when one of its parts is, so each part can be checked alone.
+func conjuncts(node *pg_query.Node) []*pg_query.Node {
+	if b := node.GetBoolExpr(); b != nil && b.GetBoolop() == pg_query.BoolExprType_AND_EXPR {
+		var parts []*pg_query.Node
+		for _, arg := range b.GetArgs() {
+			parts = append(parts, conjuncts(arg)...)
+		}
+		return parts
+	}
+	return []*pg_query.Node{node}
+}
+
+// checkOperators maps SQL operators to those of the expression language
+var checkOperators = map[string]string{
+	"=": "=", "<>": "<>", "!=": "<>", "<": "<", "<=": "<=", ">": ">", ">=": ">=",
+	"+": "+", "-": "-", "*": "*", "/": "/", "%": "%", "||": "||",
+}
+
+// checkFunctions maps SQL functions to those of the expression language
+var checkFunctions = map[string]string{
+	"lower": "lower", "upper": "upper", "length": "length", "char_length": "length",
+	"character_length": "length", "trim": "trim", "btrim": "trim", "abs": "abs",
+	"round": "round", "floor": "floor", "ceil": "ceil", "ceiling": "ceil",
+}
+
+// checkSource translates a CHECK expression to the expression language
+func checkSource(node *pg_query.Node) (string, error) {
+	switch {
+	case node.GetColumnRef() != nil:
+		name, ok := columnName(node)
+		if !ok || strings.Contains(name, `"`) {
+			return "", fmt.Errorf("unsupported column reference")
+		}
+		return `"` + name + `"`, nil
+
+	case node.GetAConst() != nil:
+		return constSource(node.GetAConst()), nil
+
+	case node.GetTypeCast() != nil:
+		cast := node.GetTypeCast()
+		inner, err := checkSource(cast.GetArg())
+		if err != nil {
+			return "", err
+		}
+		switch typeName(cast.GetTypeName()) {
+		case "date":
+			return "date(" + inner + ")", nil
+		case "timestamp", "timestamptz", "bool", "boolean":
+			return inner, nil
+		case "int2", "int4", "int8", "int", "integer", "smallint", "bigint":
+			return "int(" + inner + ")", nil
+		case "numeric", "decimal", "float4", "float8", "real":
+			return "float(" + inner + ")", nil
+		case "text", "varchar", "bpchar", "char":
+			return "text(" + inner + ")", nil
+		default:
+			return "", fmt.Errorf("unsupported cast to %s", typeName(cast.GetTypeName()))
+		}
+
+	case node.GetAExpr() != nil:
+		return exprSource(node.GetAExpr())
+
+	case node.GetBoolExpr() != nil:
+		b := node.GetBoolExpr()
+		args := make([]string, len(b.GetArgs()))
+		for i, arg := range b.GetArgs() {
+			src, err := checkSource(arg)
+			if err != nil {
+				return "", err
+			}
+			args[i] = src
+		}
+		switch b.GetBoolop() {
+		case pg_query.BoolExprType_AND_EXPR:
+			return "(" + strings.Join(args, " and ") + ")", nil
+		case pg_query.BoolExprType_OR_EXPR:
+			return "(" + strings.Join(args, " or ") + ")", nil
+		default:
+			return "(not " + args[0] + ")", nil
+		}
+
+	case node.GetNullTest() != nil:
+		test := node.GetNullTest()
+		arg, err := checkSource(test.GetArg())
+		if err != nil {
+			return "", err
+		}
+		if test.GetNulltesttype() == pg_query.NullTestType_IS_NULL {
+			return "is_null(" + arg + ")", nil
+		}
+		return "(not is_null(" + arg + "))", nil
+
+	case node.GetCoalesceExpr() != nil:
+		return callSource("coalesce", node.GetCoalesceExpr().GetArgs())
+
+	case node.GetFuncCall() != nil:
+		call := node.GetFuncCall()
+		names := call.GetFuncname()
+		name := strings.ToLower(names[len(names)-1].GetString_().GetSval())
+		fn, ok := checkFunctions[name]
+		if !ok {
+			return "", fmt.Errorf("unsupported function %s()", name)
+		}
+		return callSource(fn, call.GetArgs())
+
+	default:
+		kind := fmt.Sprintf("%T", node.GetNode())
+		return "", fmt.Errorf("unsupported %s", strings.TrimPrefix(kind, "*pg_query.Node_"))
+	}
+}
+
+func exprSource(e *pg_query.A_Expr) (string, error) {
+	names := e.GetName()
+	op := names[len(names)-1].GetString_().GetSval()
+	if e.GetLexpr() == nil {
+		if op != "-" && op != "+" {
+			return "", fmt.Errorf("unsupported operator %s", op)
+		}
+		operand, err := checkSource(e.GetRexpr())
+		if err != nil {
+			return "", err
+		}
+		return "(" + op + operand + ")", nil
+	}
+	left, err := checkSource(e.GetLexpr())
+	if err != nil {
+		return "", err
+	}
+
+	switch e.GetKind() {
+	case pg_query.A_Expr_Kind_AEXPR_OP:
+		mapped, ok := checkOperators[op]
+		if !ok {
+			return "", fmt.Errorf("unsupported operator %s", op)
+		}
+		right, err := checkSource(e.GetRexpr())
+		if err != nil {
+			return "", err
+		}
+		return "(" + left + " " + mapped + " " + right + ")", nil
+
+	case pg_query.A_Expr_Kind_AEXPR_IN:
+		items, err := listSources(e.GetRexpr())
+		if err != nil {
+			return "", err
+		}
+		tests := make([]string, len(items))
+		for i, item := range items {
+			tests[i] = left + " = " + item
+		}
+		source := "(" + strings.Join(tests, " or ") + ")"
+		if op == "<>" {
+			source = "(not " + source + ")"
+		}
+		return source, nil
+
+	case pg_query.A_Expr_Kind_AEXPR_BETWEEN, pg_query.A_Expr_Kind_AEXPR_NOT_BETWEEN:
+		items, err := listSources(e.GetRexpr())
+		if err != nil || len(items) != 2 {
+			return "", fmt.Errorf("unsupported BETWEEN")
+		}
+		source := "(" + left + " >= " + items[0] + " and " + left + " <= " + items[1] + ")"
+		if e.GetKind() == pg_query.A_Expr_Kind_AEXPR_NOT_BETWEEN {
+			source = "(not " + source + ")"
+		}
+		return source, nil
+
+	case pg_query.A_Expr_Kind_AEXPR_LIKE, pg_query.A_Expr_Kind_AEXPR_ILIKE:
+		right, err := checkSource(e.GetRexpr())
+		if err != nil {
+			return "", err
+		}
+		source := "like(" + left + ", " + right + ")"
+		if e.GetKind() == pg_query.A_Expr_Kind_AEXPR_ILIKE {
+			source = "like(lower(" + left + "), lower(" + right + "))"
+		}
+		if strings.HasPrefix(op, "!") {
+			source = "(not " + source + ")"
+		}
+		return source, nil
+
+	default:
+		return "", fmt.Errorf("unsupported operator %s", op)
+	}
+}
+
+func listSources(node *pg_query.Node) ([]string, error) {
+	list := node.GetList()
+	if list == nil {
+		return nil, fmt.Errorf("expected a list of values")
+	}
+	sources := make([]string, len(list.GetItems()))
+	for i, item := range list.GetItems() {
+		src, err := checkSource(item)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = src
+	}
+	return sources, nil
+}
+
+func callSource(fn string, args []*pg_query.Node) (string, error) {
+	sources := make([]string, len(args))
+	for i, arg := range args {
+		src, err := checkSource(arg)
+		if err != nil {
+			return "", err
+		}
+		sources[i] = src
+	}
+	return fn + "(" + strings.Join(sources, ", ") + ")", nil
+}
+
+func constSource(c *pg_query.A_Const) string {
+	switch {
+	case c.GetIsnull():
+		return "null"
+	case c.GetIval() != nil:
+		return strconv.FormatInt(int64(c.GetIval().GetIval()), 10)
+	case c.GetFval() != nil:
+		return c.GetFval().GetFval()
+	case c.GetBoolval() != nil:
+		return strconv.FormatBool(c.GetBoolval().GetBoolval())
+	default:
+		return "'" + strings.ReplaceAll(c.GetSval().GetSval(), "'", "''") + "'"
+	}
+}
+
+// columnName returns the column a column reference names
+func columnName(node *pg_query.Node) (string, bool) {
+	ref := node.GetColumnRef()
+	if ref == nil || len(ref.GetFields()) == 0 {
+		return "", false
+	}
+	fields := ref.GetFields()
+	field := fields[len(fields)-1].GetString_()
+	if field == nil {
+		return "", false
+	}
+	return field.GetSval(), true
+}
+
+func typeName(t *pg_query.TypeName) string {
+	names := t.GetNames()
+	if len(names) == 0 {
+		return ""
+	}
+	return names[len(names)-1].GetString_().GetSval()
+}
+
+// valueBounds are the values a column may take: a range, a list of allowed
+// values, or both
+type valueBounds struct {
+	min, max         interface{} // float64 or time.Time, nil when unbounded
+	minOpen, maxOpen bool
+	values           []interface{} // nil when any value in range is allowed
+}
+
+// addBound records the bound a comparison of a single column with constants
+// places on the column: col > 0, 0 < col, col BETWEEN a AND b, col IN (...).
+// Ranges are only recorded on numbers and times, the values pick can draw.
+func addBound(table *schema.Table, node *pg_query.Node, bounds map[string]*valueBounds) {
+	e := node.GetAExpr()
+	if e == nil {
+		return
+	}
+	names := e.GetName()
+	op := names[len(names)-1].GetString_().GetSval()
+
+	column, other, flipped := e.GetLexpr(), e.GetRexpr(), false
+	if _, ok := columnName(column); !ok {
+		column, other, flipped = other, column, true
+	}
+	name, ok := columnName(column)
+	col := table.Column(name)
+	if !ok || col == nil {
+		return
+	}
+	bound := func() *valueBounds {
+		if bounds[name] == nil {
+			bounds[name] = &valueBounds{}
+		}
+		return bounds[name]
+	}
+
+	switch e.GetKind() {
+	case pg_query.A_Expr_Kind_AEXPR_OP:
+		v, ok := boundConstant(col, other)
+		if !ok || (op != "=" && !isRangeBound(v)) {
+			return
+		}
+		if flipped {
+			op = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[op]
+		}
+		switch op {
+		case "=":
+			bound().allow([]interface{}{v})
+		case ">", ">=":
+			bound().lower(v, op == ">")
+		case "<", "<=":
+			bound().upper(v, op == "<")
+		}
+
+	case pg_query.A_Expr_Kind_AEXPR_BETWEEN:
+		items := other.GetList().GetItems()
+		if flipped || len(items) != 2 {
+			return
+		}
+		low, lowOK := boundConstant(col, items[0])
+		high, highOK := boundConstant(col, items[1])
+		if lowOK && highOK && isRangeBound(low) && isRangeBound(high) {
+			bound().lower(low, false)
+			bound().upper(high, false)
+		}
+
+	case pg_query.A_Expr_Kind_AEXPR_IN:
+		if flipped || op != "=" {
+			return
+		}
+		var values []interface{}
+		for _, item := range other.GetList().GetItems() {
+			v, ok := boundConstant(col, item)
+			if !ok {
+				return
+			}
+			values = append(values, v)
+		}
+		bound().allow(values)
+	}
+}
+
+// boundConstant returns the value of a constant compared with a column, as a
+// number for numbers and a time for timestamp columns
+func boundConstant(col *schema.Column, node *pg_query.Node) (interface{}, bool) {
+	negate := false
+	if e := node.GetAExpr(); e != nil && e.GetLexpr() == nil && len(e.GetName()) == 1 && e.GetName()[0].GetString_().GetSval() == "-" {
+		negate, node = true, e.GetRexpr()
+	}
+	if cast := node.GetTypeCast(); cast != nil {
+		node = cast.GetArg()
+	}
+	c := node.GetAConst()
+	if c == nil || c.GetIsnull() {
+		return nil, false
+	}
+
+	var v interface{}
+	switch {
+	case c.GetIval() != nil:
+		v = float64(c.GetIval().GetIval())
+	case c.GetFval() != nil:
+		f, err := strconv.ParseFloat(c.GetFval().GetFval(), 64)
+		if err != nil {
+			return nil, false
+		}
+		v = f
+	case c.GetBoolval() != nil:
+		v = c.GetBoolval().GetBoolval()
+	default:
+		v = c.GetSval().GetSval()
+	}
+
+	if negate {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, false
+		}
+		v = -f
+	}
+	if s, ok := v.(string); ok && generatesTimes(col) {
+		t, err := parseBoundTime(s)
+		if err != nil {
+			return nil, false
+		}
+		v = t
+	}
+	return v, true
+}
+
+func generatesTimes(col *schema.Column) bool {
+	switch col.Type {
+	case "timestamp", "timestamptz", "timestamp with time zone", "timestamp without time zone", "date":
+		return true
+	}
+	return false
+}
+
+func parseBoundTime(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// lower raises the lower bound to v if it is tighter
+func (b *valueBounds) lower(v interface{}, open bool) {
+	if b.min != nil {
+		cmp, err := expression.Compare(v, b.min)
+		if err != nil || cmp < 0 || (cmp == 0 && !open) {
+			return
+		}
+	}
+	b.min, b.minOpen = v, open
+}
+
+// upper lowers the upper bound to v if it is tighter
+func (b *valueBounds) upper(v interface{}, open bool) {
+	if b.max != nil {
+		cmp, err := expression.Compare(v, b.max)
+		if err != nil || cmp > 0 || (cmp == 0 && !open) {
+			return
+		}
+	}
+	b.max, b.maxOpen = v, open
+}
+
+// allow restricts the column to values, keeping only those already allowed
+func (b *valueBounds) allow(values []interface{}) {
+	if b.values == nil {
+		b.values = values
+		return
+	}
+	kept := []interface{}{}
+	for _, v := range b.values {
+		if containsValue(values, v) {
+			kept = append(kept, v)
+		}
+	}
+	b.values = kept
+}
+
+// admits reports whether a value is within the bounds. NULL always is.
+func (b *valueBounds) admits(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if b.values != nil && !containsValue(b.values, v) {
+		return false
+	}
+	if b.min != nil {
+		cmp, err := expression.Compare(v, b.min)
+		if err != nil || cmp < 0 || (cmp == 0 && b.minOpen) {
+			return false
+		}
+	}
+	if b.max != nil {
+		cmp, err := expression.Compare(v, b.max)
+		if err != nil || cmp > 0 || (cmp == 0 && b.maxOpen) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, allowed := range values {
+		if cmp, err := expression.Compare(v, allowed); err == nil && cmp == 0 {
+			return true
+		}
+		if fmt.Sprintf("%v", v) == fmt.Sprintf("%v", allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// pick draws a value within the bounds, typed for the column, reporting
+// whether there is one
+func (b *valueBounds) pick(rng *rand.Rand, columnType string) (interface{}, bool) {
+	if b.values != nil {
+		var admitted []interface{}
+		for _, v := range b.values {
+			if (&valueBounds{min: b.min, max: b.max, minOpen: b.minOpen, maxOpen: b.maxOpen}).admits(v) {
+				admitted = append(admitted, v)
+			}
+		}
+		if len(admitted) == 0 {
+			return nil, false
+		}
+		v := admitted[rng.Intn(len(admitted))]
+		if f, ok := v.(float64); ok {
+			return typedNumber(columnType, f, f == math.Trunc(f)), true
+		}
+		return v, true
+	}
+
+	switch {
+	case isTime(b.min) || isTime(b.max):
+		return b.pickTime(rng), true
+	case b.min != nil || b.max != nil:
+		return b.pickNumber(rng, columnType), true
+	}
+	return nil, false
+}
+
+func isTime(v interface{}) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+// isRangeBound reports whether a constant can bound a range: a number or a time
+func isRangeBound(v interface{}) bool {
+	_, isNumber := v.(float64)
+	return isNumber || isTime(v)
+}
+
+// pickTime draws a time in the bounds, spanning a year past an open end
+func (b *valueBounds) pickTime(rng *rand.Rand) interface{} {
+	const span = 365 * 24 * time.Hour
+	low, hasLow := b.min.(time.Time)
+	high, hasHigh := b.max.(time.Time)
+	switch {
+	case !hasLow:
+		low = high.Add(-span)
+	case !hasHigh:
+		high = low.Add(span)
+	}
+	if b.minOpen {
+		low = low.Add(time.Second)
+	}
+	if b.maxOpen {
+		high = high.Add(-time.Second)
+	}
+	if !high.After(low) {
+		return low
+	}
+	seconds := int64(high.Sub(low) / time.Second)
+	return low.Add(time.Duration(rng.Int63n(seconds+1)) * time.Second)
+}
+
+// pickNumber draws a number in the bounds, on the column's precision (whole
+// numbers for integer columns, the scale of numeric(p,s) columns)
+func (b *valueBounds) pickNumber(rng *rand.Rand, columnType string) interface{} {
+	low, hasLow := b.min.(float64)
+	high, hasHigh := b.max.(float64)
+	switch {
+	case !hasLow:
+		low = high - math.Max(100, math.Abs(high))
+	case !hasHigh:
+		high = low + math.Max(100, math.Abs(low))
+	}
+
+	step := numberStep(columnType)
+	if step == 0 {
+		v := low + rng.Float64()*(high-low)
+		if b.minOpen && v == low {
+			v = (low + high) / 2
+		}
+		return typedNumber(columnType, v, false)
+	}
+
+	first := math.Ceil(low/step - 1e-9)
+	if b.minOpen && math.Abs(first*step-low) < step/2 {
+		first++
+	}
+	last := math.Floor(high/step + 1e-9)
+	if b.maxOpen && math.Abs(last*step-high) < step/2 {
+		last--
+	}
+	if last < first {
+		return typedNumber(columnType, low, step == 1)
+	}
+	k := first + float64(rng.Int63n(int64(last-first)+1))
+	return typedNumber(columnType, k*step, step == 1)
+}
+
+// numberStep returns the precision of a numeric column type: 1 for integers,
+// 10^-s for numeric(p,s), 0 when values are continuous
+func numberStep(columnType string) float64 {
+	switch columnType {
+	case "integer", "int", "bigint", "smallint", "serial", "bigserial", "smallserial":
+		return 1
+	}
+	if m := numericScale.FindStringSubmatch(columnType); m != nil {
+		scale, _ := strconv.Atoi(m[1])
+		return math.Pow(10, -float64(scale))
+	}
+	return 0
+}
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"fmt"
 	"io"
+	"math/rand"
 	"time"
 
 	"github.com/NhaLeTruc/datagen-cli/internal/generator"
@@ -14,6 +15,39 @@ import (
 type Coordinator struct {
 	registry *generator.Registry
 	detector *generator.SemanticDetector
+	splitter pgdump.Splitter
+	mode     OutputMode
+	filter   *TableFilter
+	database databaseOverrides
+	schema   *schema.Schema
+	keys     *KeyStore
+	plans    map[string]*childPlan
+	spill    int    // key rows kept in memory before spilling to disk
+	baseDir  string // directory files named in the schema are relative to
+
+	rowParents         map[*schema.ForeignKey]int                            // parent row of each foreign key of the current row
+	rowDictionaries    map[dictionaryDraw]int                                // dictionary row each draw of the current row reads
+	columnOrders       map[*schema.Table][]*schema.Column                    // column generation order of each table
+	expressions        map[*schema.Column]*generator.ExpressionGenerator     // parsed expression columns
+	rules              map[*schema.Column]*generator.RulesGenerator          // parsed business rules
+	conditionals       map[*schema.Column]*generator.ConditionalGenerator    // parsed conditional weights
+	distributions      map[*schema.Column]*generator.DistributionGenerator   // parsed column distributions
+	texts              map[*schema.Column]*generator.StructuredTextGenerator // text generators (with trained Markov chains)
+	lifecycles         map[*schema.Column]*generator.LifecycleGenerator      // parsed lifecycles
+	checks             map[*schema.Table]*tableChecks                        // compiled CHECK constraints
+	copulas            map[*schema.Correlation]*generator.CopulaGenerator    // correlated column groups
+	tuples             map[*schema.Tuple]generator.TupleGenerator            // multi-column generators
+	dictionaries       map[string]*generator.Dictionary                      // loaded reference data files, by path
+	dictionarySamplers map[dictionaryDraw]*generator.DictionarySampler       // row draws of the current table
+	checkOrder         []*tableChecks                                        // in the order tables were generated
+	warnings           []string                                              // settings generation could not honour
+
+	junctions   map[string]*junctionPlan
+	aggregates  map[string][]*aggregateColumn // by child table
+	hierarchies map[string]*hierarchyPlan
+	generated   map[string]bool
+	fkRand      *rand.Rand // samples parent rows, separate from column values
+	keysOnly    bool       // leave foreign keys NULL while pre-generating parent keys
 }
 
 // NewCoordinator creates a new pipeline coordinator
@@ -21,9 +55,57 @@ func NewCoordinator() *Coordinator {
 	return &Coordinator{
 		registry: generator.DefaultRegistry(),
 		detector: generator.NewSemanticDetector(),
+		mode:     ModeFull,
+		spill:    DefaultSpillRows,
 	}
 }
 
+// SetSpillThreshold sets how many parent rows of each key set are kept in
+// memory before the rest is written to temporary files (0 keeps all in memory)
+func (c *Coordinator) SetSpillThreshold(rows int) {
+	c.spill = rows
+}
+
+// SetBaseDir sets the directory that files named in the schema (such as text
+// corpora and dictionaries) are relative to, usually the schema file's directory
+func (c *Coordinator) SetBaseDir(dir string) {
+	c.baseDir = dir
+}
+
+// SetSplitter configures an output that may roll over to a new file between
+// tables or rows (used for split dumps)
+func (c *Coordinator) SetSplitter(splitter pgdump.Splitter) {
+	c.splitter = splitter
+}
+
+// SetOutputMode selects whether the schema, the data or both are written
+func (c *Coordinator) SetOutputMode(mode OutputMode) {
+	c.mode = mode
+}
+
+// SetTableFilter restricts output to the tables matched by the filter.
+// Tables referenced by selected tables are still generated (but not written)
+// so that foreign key values point at the same parent rows as a full run.
+func (c *Coordinator) SetTableFilter(filter *TableFilter) {
+	c.filter = filter
+}
+
+// databaseOverrides holds command-line overrides of the schema's database settings
+type databaseOverrides struct {
+	createDatabase *bool
+	namespace      string
+}
+
+// SetCreateDatabase overrides whether the dump creates and connects to the database
+func (c *Coordinator) SetCreateDatabase(create bool) {
+	c.database.createDatabase = &create
+}
+
+// SetTargetSchema overrides the default PostgreSQL schema (namespace) of tables
+func (c *Coordinator) SetTargetSchema(namespace string) {
+	c.database.namespace = namespace
+}
+
 // Execute runs the complete pipeline: parse → validate → generate → write
 // Uses SQL format by default
 func (c *Coordinator) Execute(schemaJSON io.Reader, output io.Writer, seed int64) error {
@@ -32,69 +114,332 @@ func (c *Coordinator) Execute(schemaJSON io.Reader, output io.Writer, seed int64
 
 // ExecuteWithFormat runs the complete pipeline with specified output format
 func (c *Coordinator) ExecuteWithFormat(schemaJSON io.Reader, output io.Writer, seed int64, format string) error {
+	// Create writer based on format
+	writer, err := pgdump.NewWriter(output, format)
+	if err != nil {
+		return fmt.Errorf("failed to create writer: %w", err)
+	}
+
+	return c.ExecuteWithWriter(schemaJSON, writer, seed)
+}
+
+// ExecuteWithWriter runs the complete pipeline using a pre-configured writer.
+// This is used for formats that do not write to a single stream (e.g. Parquet).
+func (c *Coordinator) ExecuteWithWriter(schemaJSON io.Reader, writer pgdump.Writer, seed int64) error {
 	// Parse schema
 	s, err := schema.Parse(schemaJSON)
 	if err != nil {
 		return fmt.Errorf("failed to parse schema: %w", err)
 	}
 
+	// Apply database overrides before validation
+	if c.database.createDatabase != nil {
+		s.Database.CreateDatabase = c.database.createDatabase
+	}
+	if c.database.namespace != "" {
+		s.Database.Schema = c.database.namespace
+	}
+
 	// Validate schema
 	errors := schema.Validate(s)
 	if len(errors) > 0 {
 		return fmt.Errorf("schema validation failed: %v", errors[0])
 	}
 
-	// Create writer based on format
-	writer, err := pgdump.NewWriter(output, format)
+	// Order tables so referenced tables are generated before their children
+	order := schema.TableOrder(s)
+	selected, err := c.selectTables(s, order)
 	if err != nil {
-		return fmt.Errorf("failed to create writer: %w", err)
+		return err
 	}
 
-	// Write schema structure
-	if err := writer.WriteSchema(s); err != nil {
+	// Tables closing a cycle with UPDATE statements need a writer that can emit them
+	if err := checkCycleBreaks(s, selected, writer, c.mode); err != nil {
+		return err
+	}
+	if err := checkAggregates(s, selected, writer, c.mode); err != nil {
+		return err
+	}
+
+	// Write schema structure for the selected tables
+	filtered := *s
+	filtered.Tables = make(map[string]*schema.Table, len(selected))
+	for tableName := range selected {
+		filtered.Tables[tableName] = s.Tables[tableName]
+	}
+
+	if dataWriter, ok := pgdump.IsDataOnlyWriter(writer); ok && c.mode == ModeDataOnly {
+		if err := dataWriter.WriteDataHeader(&filtered); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+	} else if err := writer.WriteSchema(&filtered); err != nil {
 		return fmt.Errorf("failed to write schema: %w", err)
 	}
 
-	// Generate and write data for each table
-	for tableName, table := range s.Tables {
-		if err := c.generateTableDataWithWriter(writer, tableName, table, seed); err != nil {
-			return fmt.Errorf("failed to generate data for table %s: %w", tableName, err)
+	if c.mode == ModeSchemaOnly {
+		return nil
+	}
+
+	// Generate selected tables and every table they reference
+	required := requiredTables(s, selected)
+	c.schema = s
+	c.keys = NewKeyStore()
+	c.keys.SetSpill(c.spill, "")
+	defer c.keys.Close()
+	c.rowParents = make(map[*schema.ForeignKey]int)
+	c.checks, c.checkOrder, c.warnings = nil, nil, nil
+	c.plans = make(map[string]*childPlan)
+	c.junctions = make(map[string]*junctionPlan)
+	c.hierarchies = make(map[string]*hierarchyPlan)
+	c.generated = make(map[string]bool)
+	for tableName := range required {
+		table := s.Tables[tableName]
+		for _, fk := range table.ForeignKeys {
+			if fk.ReferencedTable != tableName || fk.Hierarchy != nil {
+				c.keys.Track(fk.ReferencedTable, referencedColumns(s, fk))
+			}
+			if fk.CycleBreak == schema.CycleBreakUpdate {
+				c.keys.Track(tableName, table.PrimaryKeyColumns())
+			}
+			if fk.Where != nil {
+				c.keys.TrackSubset(fk.ReferencedTable, whereKey(fk), fk.Qualifies)
+			}
+		}
+		// Keep the parent columns that lookups, temporal constraints, rules,
+		// conditional weights and lifecycles read
+		for _, col := range table.Columns {
+			if lookup, _ := col.Lookup(); lookup != nil {
+				c.keys.Track(lookup.Table, []string{lookup.Column})
+			}
+		}
+		c.trackTemporal(table)
+		c.trackPolymorphic(table)
+		c.trackRules(table)
+	}
+	c.planAggregates(s, required)
+
+	// Deferred foreign keys are only checked once all data has been loaded
+	transaction, inTransaction := pgdump.IsTransactionWriter(writer)
+	inTransaction = inTransaction && hasDeferredKeys(s, selected)
+	if inTransaction {
+		if err := transaction.BeginTransaction(); err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+	}
+
+	for _, tableName := range order {
+		table := s.Tables[tableName]
+		if required[tableName] {
+			if err := c.prepareDeferredParents(tableName, table, seed); err != nil {
+				return err
+			}
+			c.planChildren(tableName, table, seed)
+		}
+
+		if selected[tableName] {
+			if err := c.generateTableDataWithWriter(writer, tableName, table, seed); err != nil {
+				return fmt.Errorf("failed to generate data for table %s: %w", tableName, err)
+			}
+		} else if required[tableName] {
+			if err := c.generateTableKeys(tableName, table, seed); err != nil {
+				return fmt.Errorf("failed to generate keys for table %s: %w", tableName, err)
+			}
+		}
+	}
+
+	// Close cycles whose foreign keys were inserted as NULL
+	if err := c.writeCycleUpdates(writer, order, selected, seed); err != nil {
+		return err
+	}
+
+	// Set aggregate columns from the child rows
+	if err := c.writeAggregateUpdates(writer, order, selected); err != nil {
+		return err
+	}
+
+	if inTransaction {
+		if err := transaction.CommitTransaction(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// planChildren assigns the rows of a table with a cardinality foreign key to
+// parent rows, deriving the row count from the plan when none is set, and lays
+// out self-referencing hierarchies and junction pairs
+func (c *Coordinator) planChildren(tableName string, table *schema.Table, seed int64) {
+	c.planHierarchy(tableName, table, seed)
+	c.planJunction(tableName, table, seed)
+
+	fk := table.CardinalityKey()
+	if fk == nil {
+		return
+	}
+
+	parentCount := c.keys.Count(fk.ReferencedTable, referencedColumns(c.schema, fk))
+	rng := rand.New(rand.NewSource(seed))
+	parents := PlanChildren(rng, fk.Cardinality, parentCount, table.RowCount)
+	if table.RowCount > 0 && len(parents) != table.RowCount {
+		c.warnings = append(c.warnings, fmt.Sprintf("table %s: row_count %d cannot be met by %d parent rows with %d to %d children each, generating %d rows",
+			tableName, table.RowCount, parentCount, fk.Cardinality.Min, fk.Cardinality.Max, len(parents)))
+		table.RowCount = len(parents)
+	}
+	if table.RowCount == 0 {
+		table.RowCount = len(parents)
+	}
+	c.plans[tableName] = &childPlan{fk: fk, parents: parents}
+}
+
+// planHierarchy lays out the rows of a self-referencing hierarchy
+func (c *Coordinator) planHierarchy(tableName string, table *schema.Table, seed int64) {
+	if fk := table.HierarchyKey(); fk != nil {
+		parents, depths := PlanHierarchy(rand.New(rand.NewSource(seed)), fk.Hierarchy, table.RowCount)
+		c.hierarchies[tableName] = &hierarchyPlan{fk: fk, parents: parents, depths: depths}
+	}
+}
+
+// selectTables returns the tables matched by the table filter
+func (c *Coordinator) selectTables(s *schema.Schema, order []string) (map[string]bool, error) {
+	if c.filter != nil {
+		if err := c.filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, tableName := range order {
+		if c.filter.Match(tableName) {
+			selected[tableName] = true
+		}
+	}
+
+	if len(selected) == 0 && c.filter != nil && len(c.filter.Include) > 0 {
+		return nil, fmt.Errorf("no tables matched %v", c.filter.Include)
+	}
+	return selected, nil
+}
+
+// requiredTables returns the selected tables plus every table they reference,
+// directly or transitively, and the child tables their aggregate columns read
+func requiredTables(s *schema.Schema, selected map[string]bool) map[string]bool {
+	required := make(map[string]bool)
+	var visit func(tableName string)
+	visit = func(tableName string) {
+		if required[tableName] {
+			return
+		}
+		table, exists := s.Tables[tableName]
+		if !exists {
+			return
+		}
+		required[tableName] = true
+		for _, fk := range table.ForeignKeys {
+			visit(fk.ReferencedTable)
+		}
+		for _, source := range aggregateSources(table) {
+			visit(source)
+		}
+		for _, poly := range table.Polymorphic {
+			for _, target := range poly.Targets {
+				visit(target.Table)
+			}
+		}
+	}
+
+	for tableName := range selected {
+		visit(tableName)
+	}
+	return required
+}
+
+// referencedColumns returns the parent columns of a foreign key, defaulting
+// to the parent's primary key
+func referencedColumns(s *schema.Schema, fk *schema.ForeignKey) []string {
+	if len(fk.ReferencedColumns) > 0 {
+		return fk.ReferencedColumns
+	}
+	if parent, ok := s.Tables[fk.ReferencedTable]; ok {
+		if pk := parent.PrimaryKeyColumns(); len(pk) > 0 {
+			return pk
+		}
+	}
+	return fk.Columns
+}
+
+// generateTableKeys generates the rows of a table that is not written so that
+// its key values are available to child tables
+func (c *Coordinator) generateTableKeys(tableName string, table *schema.Table, seed int64) error {
+	if !c.keys.Tracks(tableName) && len(c.aggregates[tableName]) == 0 {
+		return nil
+	}
+
+	ctx := generator.NewContextWithSeed(seed)
+	ctx.TableName = tableName
+	c.beginTable(tableName, seed)
+
+	for rowIdx := 0; rowIdx < table.RowCount; rowIdx++ {
+		ctx.RowIndex = rowIdx
+		if _, err := c.generateRow(ctx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// beginTable resets the per-table generation state. Parent rows are sampled
+// from their own random source so that a table generates the same column
+// values whether or not its foreign keys are assigned.
+func (c *Coordinator) beginTable(tableName string, seed int64) {
+	c.keys.Reset(tableName)
+	if plan := c.hierarchies[tableName]; plan != nil {
+		plan.paths = nil
+	}
+	c.resetAggregates(tableName)
+	clear(c.dictionarySamplers)
+	c.fkRand = rand.New(rand.NewSource(seed))
+	c.generated[tableName] = true
+}
+
 // generateTableDataWithWriter generates data for a single table using any Writer
 func (c *Coordinator) generateTableDataWithWriter(writer pgdump.Writer, tableName string, table *schema.Table, seed int64) error {
 	ctx := generator.NewContextWithSeed(seed)
 	ctx.TableName = tableName
+	c.beginTable(tableName, seed)
 
 	columnNames := make([]string, len(table.Columns))
 	for i, col := range table.Columns {
 		columnNames[i] = col.Name
 	}
 
+	// Start a new output file for this table if splitting per table
+	if c.splitter != nil {
+		if err := c.splitter.BeginTable(tableName); err != nil {
+			return fmt.Errorf("failed to split output: %w", err)
+		}
+	}
+
 	// Check if writer supports row-by-row INSERTs (SQL format)
 	if rowWriter, ok := pgdump.IsRowWriter(writer); ok {
 		// Generate rows and write INSERT statements
 		for rowIdx := 0; rowIdx < table.RowCount; rowIdx++ {
 			ctx.RowIndex = rowIdx
-			row := make(map[string]interface{})
-
-			// Generate value for each column
-			for _, col := range table.Columns {
-				ctx.ColumnName = col.Name
-				val, err := c.generateColumnValue(ctx, col)
-				if err != nil {
-					return fmt.Errorf("failed to generate value for column %s: %w", col.Name, err)
-				}
-				row[col.Name] = val
+			row, err := c.generateRow(ctx, table)
+			if err != nil {
+				return err
 			}
 
 			if err := rowWriter.WriteInsert(tableName, columnNames, row); err != nil {
 				return fmt.Errorf("failed to write row: %w", err)
 			}
+
+			// Roll over to the next file between statements
+			if c.splitter != nil && c.splitter.NeedsSplit() {
+				if err := c.splitter.Split(); err != nil {
+					return fmt.Errorf("failed to split output: %w", err)
+				}
+			}
 		}
 		return nil
 	}
@@ -109,21 +454,27 @@ func (c *Coordinator) generateTableDataWithWriter(writer pgdump.Writer, tableNam
 		// Generate rows and write COPY data
 		for rowIdx := 0; rowIdx < table.RowCount; rowIdx++ {
 			ctx.RowIndex = rowIdx
-			row := make(map[string]interface{})
-
-			// Generate value for each column
-			for _, col := range table.Columns {
-				ctx.ColumnName = col.Name
-				val, err := c.generateColumnValue(ctx, col)
-				if err != nil {
-					return fmt.Errorf("failed to generate value for column %s: %w", col.Name, err)
-				}
-				row[col.Name] = val
+			row, err := c.generateRow(ctx, table)
+			if err != nil {
+				return err
 			}
 
 			if err := copyWriter.WriteCopyRow(columnNames, row); err != nil {
 				return fmt.Errorf("failed to write COPY row: %w", err)
 			}
+
+			// Roll over to the next file, closing and reopening the COPY block
+			if c.splitter != nil && c.splitter.NeedsSplit() && rowIdx < table.RowCount-1 {
+				if err := copyWriter.WriteCopyFooter(); err != nil {
+					return fmt.Errorf("failed to write COPY footer: %w", err)
+				}
+				if err := c.splitter.Split(); err != nil {
+					return fmt.Errorf("failed to split output: %w", err)
+				}
+				if err := copyWriter.WriteCopyHeader(tableName, columnNames); err != nil {
+					return fmt.Errorf("failed to write COPY header: %w", err)
+				}
+			}
 		}
 
 		// Write COPY footer
@@ -133,9 +484,306 @@ func (c *Coordinator) generateTableDataWithWriter(writer pgdump.Writer, tableNam
 		return nil
 	}
 
+	// Check if writer stores each table separately (Parquet format)
+	if tableWriter, ok := pgdump.IsTableWriter(writer); ok {
+		if err := tableWriter.BeginTable(tableName, table); err != nil {
+			return fmt.Errorf("failed to begin table: %w", err)
+		}
+
+		for rowIdx := 0; rowIdx < table.RowCount; rowIdx++ {
+			ctx.RowIndex = rowIdx
+			row, err := c.generateRow(ctx, table)
+			if err == nil {
+				if err = tableWriter.WriteRow(row); err != nil {
+					err = fmt.Errorf("failed to write row: %w", err)
+				}
+			}
+			if err != nil {
+				// Leave no partial table file behind
+				tableWriter.AbortTable()
+				return err
+			}
+		}
+
+		if err := tableWriter.EndTable(); err != nil {
+			return fmt.Errorf("failed to end table: %w", err)
+		}
+		return nil
+	}
+
 	return fmt.Errorf("writer does not support row-by-row output")
 }
 
+// generateRow generates values for every column of the current row
+func (c *Coordinator) generateRow(ctx *generator.Context, table *schema.Table) (map[string]interface{}, error) {
+	row := make(map[string]interface{})
+	clear(c.rowParents)
+	clear(c.rowDictionaries)
+
+	// Point foreign keys at generated parent rows
+	if err := c.assignForeignKeys(ctx, table, row); err != nil {
+		return nil, err
+	}
+	if err := c.assignPolymorphic(table, row); err != nil {
+		return nil, err
+	}
+
+	// Hierarchy path and depth columns are filled once the row's key is known
+	if plan := c.hierarchies[ctx.TableName]; plan != nil {
+		for _, col := range []string{plan.fk.Hierarchy.PathColumn, plan.fk.Hierarchy.DepthColumn} {
+			if col != "" {
+				row[col] = nil
+			}
+		}
+	}
+
+	// Correlated columns and tuples are drawn as a group
+	if err := c.assignCorrelations(ctx, table, row); err != nil {
+		return nil, err
+	}
+	if err := c.assignTuples(ctx, table, row); err != nil {
+		return nil, err
+	}
+
+	// Generate value for each remaining column, after the columns it depends on
+	var preset map[string]bool
+	if len(table.CheckConstraints) > 0 {
+		preset = make(map[string]bool, len(row))
+		for name := range row {
+			preset[name] = true
+		}
+	}
+	ctx.RowData = row
+	ctx.ParentValue = c.parentValue(table)
+	for _, col := range c.columnOrder(table) {
+		if _, assigned := row[col.Name]; assigned {
+			continue
+		}
+		if err := c.generateColumn(ctx, table, col, row); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.enforceChecks(ctx, table, row, preset); err != nil {
+		return nil, err
+	}
+
+	c.completeHierarchy(ctx, row)
+	c.accumulateAggregates(ctx.TableName, row)
+
+	// Make key values available to child tables
+	if c.keys != nil {
+		c.keys.Record(ctx.TableName, row)
+		if err := c.keys.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return row, nil
+}
+
+// generateColumn generates the value of one column of the current row
+func (c *Coordinator) generateColumn(ctx *generator.Context, table *schema.Table, col *schema.Column, row map[string]interface{}) error {
+	ctx.ColumnName = col.Name
+	ctx.TimeBounds = c.timeBounds(table, col, row)
+	val, err := c.generateColumnValue(ctx, col)
+	ctx.TimeBounds = nil
+	if err != nil {
+		return fmt.Errorf("failed to generate value for column %s: %w", col.Name, err)
+	}
+	row[col.Name] = c.boundValue(ctx, table, col, val)
+	return nil
+}
+
+// columnOrder returns the columns of a table in generation order: a column
+// follows the columns its expression or temporal constraint depends on, and
+// lifecycle timestamps follow their status column
+func (c *Coordinator) columnOrder(table *schema.Table) []*schema.Column {
+	if ordered, ok := c.columnOrders[table]; ok {
+		return ordered
+	}
+
+	ordered := make([]*schema.Column, 0, len(table.Columns))
+	placed := make(map[*schema.Column]bool, len(table.Columns))
+	var place func(col *schema.Column)
+	place = func(col *schema.Column) {
+		if placed[col] {
+			return
+		}
+		placed[col] = true // also stops cycles, which validation rejects
+		if owner := table.LifecycleOwner(col.Name); owner != nil {
+			place(owner) // sets the column with its status
+		}
+		for _, dep := range col.RowDependencies() {
+			if ref := table.Column(dep); ref != nil {
+				place(ref)
+			}
+		}
+		ordered = append(ordered, col)
+	}
+	for _, col := range table.Columns {
+		place(col)
+	}
+
+	if c.columnOrders == nil {
+		c.columnOrders = make(map[*schema.Table][]*schema.Column)
+	}
+	c.columnOrders[table] = ordered
+	return ordered
+}
+
+// assignForeignKeys sets the foreign key columns of a row to the key values of
+// a random parent row. All columns of a composite key come from the same row.
+func (c *Coordinator) assignForeignKeys(ctx *generator.Context, table *schema.Table, row map[string]interface{}) error {
+	if c.keys == nil {
+		return nil
+	}
+
+	for _, fk := range table.ForeignKeys {
+		// Self-references point at an earlier row of the hierarchy, or are
+		// generated like regular columns without one
+		if fk.ReferencedTable == ctx.TableName {
+			plan := c.hierarchies[ctx.TableName]
+			if plan == nil || plan.fk != fk || ctx.RowIndex >= len(plan.parents) {
+				continue
+			}
+			parent := plan.parents[ctx.RowIndex]
+			tuple, found := c.keys.Get(fk.ReferencedTable, referencedColumns(c.schema, fk), parent)
+			if found {
+				c.rowParents[fk] = parent
+			}
+			for i, col := range fk.Columns {
+				row[col] = nil
+				if i < len(tuple) {
+					row[col] = tuple[i]
+				}
+			}
+			continue
+		}
+
+		// Keys-only passes and keys set by UPDATE statements later start as NULL
+		if c.keysOnly || fk.CycleBreak == schema.CycleBreakUpdate {
+			for _, col := range fk.Columns {
+				row[col] = nil
+			}
+			continue
+		}
+
+		// Optional keys are NULL for a share of the rows
+		if fk.NullRate > 0 && c.fkRand.Float64() < fk.NullRate {
+			for _, col := range fk.Columns {
+				row[col] = nil
+			}
+			continue
+		}
+
+		var tuple []interface{}
+		parent, ok := c.plannedParent(ctx, fk)
+		if !ok {
+			parent, ok = c.pickParent(c.fkRand, fk)
+		}
+		if ok {
+			tuple, ok = c.keys.Get(fk.ReferencedTable, referencedColumns(c.schema, fk), parent)
+		}
+		if !ok {
+			if !foreignKeyNullable(table, fk) {
+				if fk.Where != nil {
+					return fmt.Errorf("foreign key %v references %s, which has no rows matching %v", fk.Columns, fk.ReferencedTable, fk.Where)
+				}
+				return fmt.Errorf("foreign key %v references %s, which has no rows", fk.Columns, fk.ReferencedTable)
+			}
+			for _, col := range fk.Columns {
+				row[col] = nil
+			}
+			continue
+		}
+
+		for i, col := range fk.Columns {
+			if i < len(tuple) {
+				row[col] = tuple[i]
+			}
+		}
+		c.rowParents[fk] = parent
+	}
+	return nil
+}
+
+// lookupValue reads a column of the parent row the current row references.
+// Rows without a parent get NULL.
+func (c *Coordinator) lookupValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	lookup, err := col.Lookup()
+	if err != nil {
+		return nil, err
+	}
+	if c.schema == nil || c.schema.Tables[ctx.TableName] == nil {
+		return nil, fmt.Errorf("lookup generator needs the pipeline's parent rows")
+	}
+	table := c.schema.Tables[ctx.TableName]
+
+	parent, ok := c.rowParents[table.ForeignKeyFor(lookup.Via)]
+	if !ok {
+		return nil, nil
+	}
+	tuple, ok := c.keys.Get(lookup.Table, []string{lookup.Column}, parent)
+	if !ok {
+		return nil, c.keys.Err()
+	}
+	return lookup.Apply(tuple[0]), nil
+}
+
+// expressionValue evaluates an expression column over the row generated so
+// far, converting numbers to the column's type
+func (c *Coordinator) expressionValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	gen, ok := c.expressions[col]
+	if !ok {
+		var err error
+		if gen, err = generator.NewExpressionGenerator(col.Expression); err != nil {
+			return nil, fmt.Errorf("invalid expression: %w", err)
+		}
+		if c.expressions == nil {
+			c.expressions = make(map[*schema.Column]*generator.ExpressionGenerator)
+		}
+		c.expressions[col] = gen
+	}
+
+	val, err := gen.Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := val.(float64); ok {
+		return typedNumber(col.Type, f, false), nil
+	}
+	return val, nil
+}
+
+// plannedParent returns the parent row a cardinality or junction plan chose
+// for the current row
+func (c *Coordinator) plannedParent(ctx *generator.Context, fk *schema.ForeignKey) (int, bool) {
+	if plan := c.plans[ctx.TableName]; plan != nil && plan.fk == fk && ctx.RowIndex < len(plan.parents) {
+		return plan.parents[ctx.RowIndex], true
+	}
+	if plan := c.junctions[ctx.TableName]; plan != nil && ctx.RowIndex < len(plan.pairs) {
+		switch fk {
+		case plan.left:
+			return plan.pairs[ctx.RowIndex][0], true
+		case plan.right:
+			return plan.pairs[ctx.RowIndex][1], true
+		}
+	}
+	return 0, false
+}
+
+// foreignKeyNullable reports whether every column of a foreign key is nullable
+func foreignKeyNullable(table *schema.Table, fk *schema.ForeignKey) bool {
+	for _, name := range fk.Columns {
+		for _, col := range table.Columns {
+			if col.Name == name && !col.Nullable {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // generateTableData generates data for a single table (deprecated - use generateTableDataWithWriter)
 func (c *Coordinator) generateTableData(writer *pgdump.SQLWriter, tableName string, table *schema.Table, seed int64) error {
 	ctx := generator.NewContextWithSeed(seed)
@@ -172,7 +820,24 @@ func (c *Coordinator) generateTableData(writer *pgdump.SQLWriter, tableName stri
 
 // generateColumnValue generates a value for a column
 func (c *Coordinator) generateColumnValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
-	// First, check if there's a custom generator_config
+	// Derived columns are computed from the rest of the row
+	if col.Expression != "" {
+		return c.expressionValue(ctx, col)
+	}
+
+	// Lifecycles set the status and the timestamps of the states reached
+	if col.Lifecycle != nil {
+		return c.lifecycleValue(ctx, col)
+	}
+
+	// Business rules set the value when one of them applies
+	if len(col.Rules) > 0 {
+		if val, applied, err := c.ruleValue(ctx, col); applied || err != nil {
+			return val, err
+		}
+	}
+
+	// Then, check if there's a custom generator_config
 	if col.GeneratorConfig != nil && len(col.GeneratorConfig) > 0 {
 		return c.generateWithConfig(ctx, col)
 	}
@@ -217,6 +882,25 @@ func (c *Coordinator) generateWithConfig(ctx *generator.Context, col *schema.Col
 	var gen generator.Generator
 
 	switch genType {
+	case schema.LookupGenerator:
+		return c.lookupValue(ctx, col)
+
+	case schema.ConditionalGenerator:
+		return c.conditionalValue(ctx, col)
+
+	case schema.DistributionGenerator:
+		return c.distributionValue(ctx, col)
+
+	case schema.TextGenerator:
+		return c.textValue(ctx, col)
+
+	case schema.DictionaryGenerator:
+		return c.dictionaryValue(ctx, col)
+
+	case schema.AggregateGenerator:
+		// Set by an UPDATE statement once the child rows are generated
+		return aggregatePlaceholder(col), nil
+
 	case "weighted_enum":
 		weights := make(map[string]float64)
 
@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// assignCorrelations draws the correlated column groups of a row together,
+// converting each value to its column's type
+func (c *Coordinator) assignCorrelations(ctx *generator.Context, table *schema.Table, row map[string]interface{}) error {
+	for _, corr := range table.Correlations {
+		gen, ok := c.copulas[corr]
+		if !ok {
+			var err error
+			if gen, err = generator.NewCopulaGenerator(corr); err != nil {
+				return err
+			}
+			if c.copulas == nil {
+				c.copulas = make(map[*schema.Correlation]*generator.CopulaGenerator)
+			}
+			c.copulas[corr] = gen
+		}
+
+		values := gen.Sample(ctx)
+		for _, name := range corr.Columns {
+			if col := table.Column(name); col != nil {
+				row[name] = typedNumber(col.Type, values[name], false)
+			}
+		}
+	}
+	return nil
+}
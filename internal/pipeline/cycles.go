@@ -0,0 +1,122 @@
+package pipeline
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// checkCycleBreaks verifies that the writer can close the cycles of the
+// selected tables whose foreign keys are set by UPDATE statements
+func checkCycleBreaks(s *schema.Schema, selected map[string]bool, writer pgdump.Writer, mode OutputMode) error {
+	if mode == ModeSchemaOnly {
+		return nil
+	}
+	if _, ok := pgdump.IsUpdateWriter(writer); ok {
+		return nil
+	}
+
+	for tableName := range selected {
+		for _, fk := range s.Tables[tableName].ForeignKeys {
+			if fk.CycleBreak == schema.CycleBreakUpdate {
+				return fmt.Errorf("table %s: cycle_break \"update\" requires an output format that supports UPDATE statements", tableName)
+			}
+		}
+	}
+	return nil
+}
+
+// hasDeferredKeys reports whether any selected table has a foreign key
+// checked at commit time
+func hasDeferredKeys(s *schema.Schema, selected map[string]bool) bool {
+	for tableName := range selected {
+		for _, fk := range s.Tables[tableName].ForeignKeys {
+			if fk.CycleBreak == schema.CycleBreakDeferred {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// prepareDeferredParents generates the keys of tables referenced through
+// deferred foreign keys that come later in the load order. Their foreign keys
+// are left NULL in this pass; since parent rows are sampled from a separate
+// random source, the keys match the rows written later.
+func (c *Coordinator) prepareDeferredParents(tableName string, table *schema.Table, seed int64) error {
+	for _, fk := range table.ForeignKeys {
+		parent, exists := c.schema.Tables[fk.ReferencedTable]
+		if fk.CycleBreak != schema.CycleBreakDeferred || !exists || c.generated[fk.ReferencedTable] {
+			continue
+		}
+
+		c.planHierarchy(fk.ReferencedTable, parent, seed)
+		c.keysOnly = true
+		err := c.generateTableKeys(fk.ReferencedTable, parent, seed)
+		c.keysOnly = false
+		if err != nil {
+			return fmt.Errorf("failed to generate keys for table %s: %w", fk.ReferencedTable, err)
+		}
+	}
+	return nil
+}
+
+// writeCycleUpdates points the foreign keys inserted as NULL to break a cycle
+// at parent rows, now that every table has been generated
+func (c *Coordinator) writeCycleUpdates(writer pgdump.Writer, order []string, selected map[string]bool, seed int64) error {
+	updater, ok := pgdump.IsUpdateWriter(writer)
+	if !ok {
+		return nil
+	}
+
+	for _, tableName := range order {
+		if !selected[tableName] {
+			continue
+		}
+		table := c.schema.Tables[tableName]
+		keyColumns := table.PrimaryKeyColumns()
+
+		for _, fk := range table.ForeignKeys {
+			if fk.CycleBreak != schema.CycleBreakUpdate {
+				continue
+			}
+			refColumns := referencedColumns(c.schema, fk)
+			rng := rand.New(rand.NewSource(seed))
+
+			for rowIdx := 0; rowIdx < c.keys.Count(tableName, keyColumns); rowIdx++ {
+				if fk.NullRate > 0 && rng.Float64() < fk.NullRate {
+					continue // the keys stay NULL
+				}
+				index, ok := c.pickParent(rng, fk)
+				if !ok {
+					break // no parent rows, the keys stay NULL
+				}
+				parent, _ := c.keys.Get(fk.ReferencedTable, refColumns, index)
+				key, _ := c.keys.Get(tableName, keyColumns, rowIdx)
+
+				row := make(map[string]interface{}, len(keyColumns)+len(fk.Columns))
+				for i, col := range keyColumns {
+					row[col] = key[i]
+				}
+				for i, col := range fk.Columns {
+					if i < len(parent) {
+						row[col] = parent[i]
+					}
+				}
+
+				if err := updater.WriteUpdate(tableName, fk.Columns, keyColumns, row); err != nil {
+					return fmt.Errorf("failed to write update: %w", err)
+				}
+
+				if c.splitter != nil && c.splitter.NeedsSplit() {
+					if err := c.splitter.Split(); err != nil {
+						return fmt.Errorf("failed to split output: %w", err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
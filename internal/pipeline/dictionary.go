@@ -0,0 +1,71 @@
+package pipeline
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// dictionaryDraw identifies the row draw of a dictionary file that columns
+// of the current table share
+type dictionaryDraw struct {
+	file string
+	draw string
+}
+
+// dictionaryValue reads the value of a column from its dictionary row. The
+// first column of a draw in the current row draws the row, the others read it.
+func (c *Coordinator) dictionaryValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	config, err := col.Dictionary()
+	if err != nil {
+		return nil, err
+	}
+
+	path := c.schemaPath(config.File)
+	dict, ok := c.dictionaries[path]
+	if !ok {
+		if dict, err = generator.LoadDictionary(path, config); err != nil {
+			return nil, err
+		}
+		if c.dictionaries == nil {
+			c.dictionaries = make(map[string]*generator.Dictionary)
+		}
+		c.dictionaries[path] = dict
+	}
+	column, err := dict.Column(config.Column, col.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := dictionaryDraw{file: config.File, draw: config.Draw}
+	row, drawn := c.rowDictionaries[key]
+	if !drawn {
+		sampler, ok := c.dictionarySamplers[key]
+		if !ok {
+			if sampler, err = generator.NewDictionarySampler(dict, config.Weight, config.Replacement); err != nil {
+				return nil, err
+			}
+			if c.dictionarySamplers == nil {
+				c.dictionarySamplers = make(map[dictionaryDraw]*generator.DictionarySampler)
+			}
+			c.dictionarySamplers[key] = sampler
+		}
+		if row, err = sampler.Draw(ctx); err != nil {
+			return nil, err
+		}
+		if c.rowDictionaries == nil {
+			c.rowDictionaries = make(map[dictionaryDraw]int)
+		}
+		c.rowDictionaries[key] = row
+	}
+
+	val := dict.Value(row, column)
+	if s, ok := val.(string); ok && col.IsNumeric() {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return typedNumber(col.Type, f, false), nil
+		}
+	}
+	return val, nil
+}
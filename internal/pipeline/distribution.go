@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// distributionValue draws the value of a column from its distribution,
+// converting numbers to the column's type
+func (c *Coordinator) distributionValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	gen, ok := c.distributions[col]
+	if !ok {
+		config, err := col.Distribution()
+		if err != nil {
+			return nil, err
+		}
+		gen = generator.NewDistributionGenerator(config)
+		if c.distributions == nil {
+			c.distributions = make(map[*schema.Column]*generator.DistributionGenerator)
+		}
+		c.distributions[col] = gen
+	}
+
+	val, err := gen.Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := val.(float64); ok {
+		return typedNumber(col.Type, f, false), nil
+	}
+	return val, nil
+}
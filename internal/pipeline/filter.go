@@ -0,0 +1,58 @@
+package pipeline
+
+import (
+	"fmt"
+	"path"
+)
+
+// OutputMode selects which parts of the dump are written
+type OutputMode string
+
+const (
+	// ModeFull writes the schema (DDL) and the data
+	ModeFull OutputMode = "full"
+
+	// ModeSchemaOnly writes the schema without any data (pg_dump --schema-only)
+	ModeSchemaOnly OutputMode = "schema-only"
+
+	// ModeDataOnly writes the data without any DDL (pg_dump --data-only)
+	ModeDataOnly OutputMode = "data-only"
+)
+
+// TableFilter selects tables by glob pattern, like pg_dump -t and -T.
+// An empty Include list selects every table; Exclude always wins.
+type TableFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// Validate checks that every pattern is a valid glob
+func (f *TableFilter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid table pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether a table is selected by the filter
+func (f *TableFilter) Match(tableName string) bool {
+	if f == nil {
+		return true
+	}
+	for _, pattern := range f.Exclude {
+		if matched, _ := path.Match(pattern, tableName); matched {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if matched, _ := path.Match(pattern, tableName); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// defaultFanOut is the number of children per node when none is configured
+var defaultFanOut = schema.Cardinality{Min: 1, Max: 5}
+
+// hierarchyPlan assigns every row of a self-referencing table to a parent row
+type hierarchyPlan struct {
+	fk      *schema.ForeignKey
+	parents []int    // parent row index, -1 for roots
+	depths  []int    // level of each row, roots are 1
+	paths   []string // materialized paths of the rows generated so far
+}
+
+// PlanHierarchy lays out rowCount rows as a forest and returns the parent
+// index (-1 for roots) and level of each row. Nodes are expanded breadth-first
+// so every parent precedes its children, which keeps the tree acyclic.
+func PlanHierarchy(rng *rand.Rand, h *schema.Hierarchy, rowCount int) (parents, depths []int) {
+	if rowCount <= 0 {
+		return nil, nil
+	}
+
+	roots := h.Roots
+	if roots <= 0 {
+		roots = 1
+	}
+	if roots > rowCount {
+		roots = rowCount
+	}
+	fanOut := h.FanOut
+	if fanOut == nil {
+		fanOut = &defaultFanOut
+	}
+	canExpand := func(node int) bool {
+		return h.MaxDepth == 0 || depths[node] < h.MaxDepth
+	}
+
+	parents = make([]int, 0, rowCount)
+	depths = make([]int, 0, rowCount)
+	for i := 0; i < roots; i++ {
+		parents = append(parents, -1)
+		depths = append(depths, 1)
+	}
+
+	// Expand nodes in breadth-first order with the configured fan-out; the
+	// empty ratio leaves a share of the nodes as leaves
+	draw := cardinalityDrawer(rng, fanOut)
+	for node := 0; node < len(parents) && len(parents) < rowCount; node++ {
+		if !canExpand(node) {
+			continue
+		}
+		if fanOut.EmptyRatio > 0 && rng.Float64() < fanOut.EmptyRatio {
+			continue
+		}
+		for n := draw(); n > 0 && len(parents) < rowCount; n-- {
+			parents = append(parents, node)
+			depths = append(depths, depths[node]+1)
+		}
+	}
+
+	// Rows left over once the fan-out or depth is exhausted are spread over the
+	// shallowest expandable nodes; without any, they become extra roots
+	for node := 0; len(parents) < rowCount; node = (node + 1) % len(parents) {
+		if canExpand(node) {
+			parents = append(parents, node)
+			depths = append(depths, depths[node]+1)
+		} else if h.MaxDepth == 1 {
+			parents = append(parents, -1)
+			depths = append(depths, 1)
+		}
+	}
+
+	return parents, depths
+}
+
+// completeHierarchy fills the path and depth columns of a generated row and
+// remembers its path for the row's children
+func (c *Coordinator) completeHierarchy(ctx *generator.Context, row map[string]interface{}) {
+	plan := c.hierarchies[ctx.TableName]
+	if plan == nil || ctx.RowIndex >= len(plan.parents) {
+		return
+	}
+	h := plan.fk.Hierarchy
+
+	// The path is built from the row's own key, the value children point at
+	refColumns := referencedColumns(c.schema, plan.fk)
+	label := make([]string, len(refColumns))
+	for i, col := range refColumns {
+		label[i] = fmt.Sprintf("%v", row[col])
+	}
+
+	separator := h.PathSeparator
+	if separator == "" {
+		separator = "/"
+	}
+	path := strings.Join(label, "_")
+	if parent := plan.parents[ctx.RowIndex]; parent >= 0 && parent < len(plan.paths) {
+		path = plan.paths[parent] + separator + path
+	}
+	plan.paths = append(plan.paths, path)
+
+	if h.PathColumn != "" {
+		row[h.PathColumn] = path
+	}
+	if h.DepthColumn != "" {
+		row[h.DepthColumn] = int64(plan.depths[ctx.RowIndex])
+	}
+}
@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// junctionPlan assigns every row of a many-to-many table to a distinct pair
+// of parent rows
+type junctionPlan struct {
+	left, right *schema.ForeignKey
+	pairs       [][2]int // left and right parent index of each row
+}
+
+// PlanJunction links leftCount parent rows to rightCount parent rows and
+// returns distinct (left, right) index pairs, grouped by left row. The number
+// of pairs is rowCount (or the sum of the left degrees when rowCount is 0),
+// reduced to the number of pairs the degrees and parent counts allow.
+func PlanJunction(rng *rand.Rand, j *schema.Junction, leftCount, rightCount, rowCount int) [][2]int {
+	if leftCount == 0 || rightCount == 0 {
+		return nil
+	}
+
+	leftDegree, leftMax := junctionDegree(j.Left.Degree, rightCount)
+	rightDegree, rightMax := junctionDegree(j.Right.Degree, leftCount)
+	leftCounts := drawCounts(rng, leftDegree, leftCount)
+	rightCounts := drawCounts(rng, rightDegree, rightCount)
+
+	total := rowCount
+	if total <= 0 {
+		for _, n := range leftCounts {
+			total += n
+		}
+	}
+	if achievable := min(leftCount*leftMax, rightCount*rightMax); total > achievable {
+		total = achievable
+	}
+	if total == 0 {
+		return nil
+	}
+	leftCounts = fitCounts(leftCounts, total, leftMax)
+	rightCounts = fitCounts(rightCounts, total, rightMax)
+
+	// Link the busiest left rows first, each to the right rows with the most
+	// links left to give (ties in random order), which meets both sides'
+	// degrees whenever they are compatible
+	order := rng.Perm(leftCount)
+	sort.SliceStable(order, func(a, b int) bool {
+		return leftCounts[order[a]] > leftCounts[order[b]]
+	})
+
+	pairs := make([][2]int, 0, total)
+	for _, left := range order {
+		candidates := rng.Perm(rightCount)
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return rightCounts[candidates[a]] > rightCounts[candidates[b]]
+		})
+		for _, right := range candidates[:leftCounts[left]] {
+			pairs = append(pairs, [2]int{left, right})
+			rightCounts[right]--
+		}
+	}
+
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a][0] != pairs[b][0] {
+			return pairs[a][0] < pairs[b][0]
+		}
+		return pairs[a][1] < pairs[b][1]
+	})
+	return pairs
+}
+
+// junctionDegree returns the degree distribution of one side and the most
+// links a row of that side can have, given the row count of the other side
+func junctionDegree(degree *schema.Cardinality, otherCount int) (*schema.Cardinality, int) {
+	if degree == nil {
+		return &schema.Cardinality{Min: 0, Max: otherCount}, otherCount
+	}
+	return degree, min(degree.Max, otherCount)
+}
+
+// fitCounts scales counts to sum to total without any count exceeding limit.
+// The caller guarantees total <= len(counts)*limit.
+func fitCounts(counts []int, total, limit int) []int {
+	return scaleCounts(counts, total, &schema.Cardinality{Min: 0, Max: limit})
+}
+
+// planJunction pairs the parent rows of a junction table and sizes the table
+// to the number of pairs
+func (c *Coordinator) planJunction(tableName string, table *schema.Table, seed int64) {
+	left, right := table.JunctionKeys()
+	if left == nil || right == nil {
+		return
+	}
+
+	leftCount := c.keys.Count(left.ReferencedTable, referencedColumns(c.schema, left))
+	rightCount := c.keys.Count(right.ReferencedTable, referencedColumns(c.schema, right))
+	pairs := PlanJunction(rand.New(rand.NewSource(seed)), table.Junction, leftCount, rightCount, table.RowCount)
+
+	table.RowCount = len(pairs)
+	c.junctions[tableName] = &junctionPlan{left: left, right: right, pairs: pairs}
+}
@@ -0,0 +1,235 @@
+package pipeline
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// DefaultSpillRows is the number of rows of a key set kept in memory before
+// the rest is written to a temporary file
+const DefaultSpillRows = 1000000
+
+// KeyStore records the referenced key values of generated rows so that child
+// tables can point their foreign keys at parent rows that exist. It also keeps
+// the parent columns that child rows look up.
+type KeyStore struct {
+	sets   map[string]*keySet
+	tables map[string][]*keySet
+
+	subsets  map[string][]*rowSubset // by table
+	recorded map[string]int          // rows recorded per table
+
+	spillRows int    // rows kept in memory per set, 0 for no limit
+	spillDir  string // directory of spill files (default: os.TempDir)
+	err       error
+}
+
+// rowSubset holds the indexes of the recorded rows of a table that match a filter
+type rowSubset struct {
+	name  string
+	match func(row map[string]interface{}) bool
+	rows  []int
+}
+
+// keySet holds the values of one referenced column list, one tuple per row.
+// Rows beyond the store's memory limit are kept in a spill file.
+type keySet struct {
+	columns []string
+	rows    [][]interface{}
+	spill   *spillFile
+}
+
+// NewKeyStore creates an empty key store
+func NewKeyStore() *KeyStore {
+	return &KeyStore{
+		sets:     make(map[string]*keySet),
+		tables:   make(map[string][]*keySet),
+		subsets:  make(map[string][]*rowSubset),
+		recorded: make(map[string]int),
+	}
+}
+
+// SetSpill keeps at most rows tuples of each column list in memory and writes
+// the rest to temporary files in dir. Zero rows disables spilling.
+func (ks *KeyStore) SetSpill(rows int, dir string) {
+	ks.spillRows = rows
+	ks.spillDir = dir
+}
+
+// Track registers a column list of a table whose values should be recorded
+func (ks *KeyStore) Track(tableName string, columns []string) {
+	key := keyStoreKey(tableName, columns)
+	if _, exists := ks.sets[key]; !exists {
+		set := &keySet{columns: columns}
+		ks.sets[key] = set
+		ks.tables[tableName] = append(ks.tables[tableName], set)
+	}
+}
+
+// TrackSubset registers a named subset of a table's rows: those for which
+// match returns true. Only their row indexes are kept.
+func (ks *KeyStore) TrackSubset(tableName, name string, match func(row map[string]interface{}) bool) {
+	if ks.subset(tableName, name) != nil {
+		return
+	}
+	ks.subsets[tableName] = append(ks.subsets[tableName], &rowSubset{name: name, match: match})
+}
+
+// Tracks reports whether any column list or subset of a table is tracked
+func (ks *KeyStore) Tracks(tableName string) bool {
+	return len(ks.tables[tableName]) > 0 || len(ks.subsets[tableName]) > 0
+}
+
+// Record stores the tracked key values of a generated row
+func (ks *KeyStore) Record(tableName string, row map[string]interface{}) {
+	index := ks.recorded[tableName]
+	ks.recorded[tableName]++
+	for _, subset := range ks.subsets[tableName] {
+		if subset.match(row) {
+			subset.rows = append(subset.rows, index)
+		}
+	}
+
+	for _, set := range ks.tables[tableName] {
+		tuple := make([]interface{}, len(set.columns))
+		for i, col := range set.columns {
+			tuple[i] = row[col]
+		}
+
+		if ks.spillRows <= 0 || len(set.rows) < ks.spillRows {
+			set.rows = append(set.rows, tuple)
+			continue
+		}
+		if set.spill == nil {
+			set.spill, ks.err = newSpillFile(ks.spillDir)
+			if ks.err != nil {
+				return
+			}
+		}
+		if err := set.spill.append(tuple); err != nil {
+			ks.err = err
+			return
+		}
+	}
+}
+
+// Reset discards the recorded rows of a table before it is generated again
+func (ks *KeyStore) Reset(tableName string) {
+	ks.recorded[tableName] = 0
+	for _, subset := range ks.subsets[tableName] {
+		subset.rows = nil
+	}
+	for _, set := range ks.tables[tableName] {
+		set.rows = nil
+		if set.spill != nil {
+			if err := set.spill.reset(); err != nil {
+				ks.err = err
+			}
+		}
+	}
+}
+
+// Count returns the number of recorded rows for a column list
+func (ks *KeyStore) Count(tableName string, columns []string) int {
+	if set, ok := ks.sets[keyStoreKey(tableName, columns)]; ok {
+		return set.count()
+	}
+	return 0
+}
+
+// Get returns the key values of the recorded row at index
+func (ks *KeyStore) Get(tableName string, columns []string, index int) ([]interface{}, bool) {
+	set, ok := ks.sets[keyStoreKey(tableName, columns)]
+	if !ok || index < 0 || index >= set.count() {
+		return nil, false
+	}
+	if index < len(set.rows) {
+		return set.rows[index], true
+	}
+
+	tuple, err := set.spill.get(index - len(set.rows))
+	if err != nil {
+		ks.err = err
+		return nil, false
+	}
+	return tuple, true
+}
+
+// SubsetCount returns the number of recorded rows in a subset
+func (ks *KeyStore) SubsetCount(tableName, name string) int {
+	if subset := ks.subset(tableName, name); subset != nil {
+		return len(subset.rows)
+	}
+	return 0
+}
+
+// SubsetRow returns the row index of the subset's row at index, for use with Get
+func (ks *KeyStore) SubsetRow(tableName, name string, index int) (int, bool) {
+	subset := ks.subset(tableName, name)
+	if subset == nil || index < 0 || index >= len(subset.rows) {
+		return 0, false
+	}
+	return subset.rows[index], true
+}
+
+func (ks *KeyStore) subset(tableName, name string) *rowSubset {
+	for _, subset := range ks.subsets[tableName] {
+		if subset.name == name {
+			return subset
+		}
+	}
+	return nil
+}
+
+// Pick returns the index of a random recorded row.
+// It returns false when the table has no recorded rows.
+func (ks *KeyStore) Pick(rng *rand.Rand, tableName string, columns []string) (int, bool) {
+	n := ks.Count(tableName, columns)
+	if n == 0 {
+		return 0, false
+	}
+	return rng.Intn(n), true
+}
+
+// Sample returns the key values of a random recorded row.
+// It returns false when the table has no recorded rows.
+func (ks *KeyStore) Sample(rng *rand.Rand, tableName string, columns []string) ([]interface{}, bool) {
+	index, ok := ks.Pick(rng, tableName, columns)
+	if !ok {
+		return nil, false
+	}
+	return ks.Get(tableName, columns, index)
+}
+
+// Err returns the first error encountered while spilling rows to disk
+func (ks *KeyStore) Err() error {
+	return ks.err
+}
+
+// Close removes the spill files
+func (ks *KeyStore) Close() error {
+	var firstErr error
+	for _, set := range ks.sets {
+		if set.spill == nil {
+			continue
+		}
+		if err := set.spill.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		set.spill = nil
+	}
+	return firstErr
+}
+
+// count returns the number of rows in memory and on disk
+func (set *keySet) count() int {
+	if set.spill == nil {
+		return len(set.rows)
+	}
+	return len(set.rows) + set.spill.count()
+}
+
+// keyStoreKey identifies a column list of a table (e.g. "orders(id)")
+func keyStoreKey(tableName string, columns []string) string {
+	return tableName + "(" + strings.Join(columns, ",") + ")"
+}
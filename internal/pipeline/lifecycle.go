@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// lifecycleValue walks the lifecycle of a status column, setting the
+// timestamp columns of its states in the row, and returns the final state
+func (c *Coordinator) lifecycleValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	gen, ok := c.lifecycles[col]
+	if !ok {
+		var err error
+		if gen, err = generator.NewLifecycleGenerator(col.Lifecycle); err != nil {
+			return nil, err
+		}
+		if c.lifecycles == nil {
+			c.lifecycles = make(map[*schema.Column]*generator.LifecycleGenerator)
+		}
+		c.lifecycles[col] = gen
+	}
+
+	state, timestamps, err := gen.Walk(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for name, at := range timestamps {
+		ctx.RowData[name] = at
+	}
+	return state, nil
+}
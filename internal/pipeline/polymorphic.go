@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// trackPolymorphic keeps the target keys that the polymorphic references of a table read
+func (c *Coordinator) trackPolymorphic(table *schema.Table) {
+	for _, poly := range table.Polymorphic {
+		for _, target := range poly.Targets {
+			c.keys.Track(target.Table, []string{target.ReferencedColumn(c.schema)})
+		}
+	}
+}
+
+// assignPolymorphic points the polymorphic references of a row at a random
+// row of one of their target tables, chosen by weight
+func (c *Coordinator) assignPolymorphic(table *schema.Table, row map[string]interface{}) error {
+	if c.keys == nil {
+		return nil
+	}
+
+	for _, poly := range table.Polymorphic {
+		row[poly.TypeColumn], row[poly.IDColumn] = nil, nil
+		if c.keysOnly {
+			continue // like foreign keys, set on the full pass
+		}
+
+		target := c.pickTarget(poly)
+		if target == nil {
+			if col := table.Column(poly.IDColumn); col == nil || !col.Nullable {
+				return fmt.Errorf("polymorphic reference %s has no target rows", poly.IDColumn)
+			}
+			continue
+		}
+
+		columns := []string{target.ReferencedColumn(c.schema)}
+		parent, _ := c.keys.Pick(c.fkRand, target.Table, columns)
+		tuple, ok := c.keys.Get(target.Table, columns, parent)
+		if !ok {
+			return c.keys.Err()
+		}
+		row[poly.TypeColumn] = target.TypeValue()
+		row[poly.IDColumn] = tuple[0]
+	}
+	return nil
+}
+
+// pickTarget chooses a target table with rows by weight, or nil when no target has rows
+func (c *Coordinator) pickTarget(poly *schema.Polymorphic) *schema.PolymorphicTarget {
+	weights := make([]float64, len(poly.Targets))
+	total := 0.0
+	for i, target := range poly.Targets {
+		if c.keys.Count(target.Table, []string{target.ReferencedColumn(c.schema)}) > 0 {
+			weights[i] = target.TargetWeight()
+			total += weights[i]
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	r := c.fkRand.Float64() * total
+	for i, target := range poly.Targets {
+		if weights[i] == 0 {
+			continue
+		}
+		if r < weights[i] {
+			return target
+		}
+		r -= weights[i]
+	}
+	// Floating point rounding: fall back to the last target with rows
+	for i := len(poly.Targets) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return poly.Targets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// trackRules keeps the parent columns that the business rules, conditional
+// weights and lifecycles of a table read
+func (c *Coordinator) trackRules(table *schema.Table) {
+	for _, col := range table.Columns {
+		if cond, _ := col.Conditional(); cond != nil {
+			for _, field := range cond.Given {
+				if refTable, refColumn := schema.ConditionReference(field); refTable != "" {
+					c.keys.Track(refTable, []string{refColumn})
+				}
+			}
+		}
+		if col.Lifecycle != nil {
+			if refTable, refColumn := schema.ConditionReference(col.Lifecycle.Start); refTable != "" {
+				c.keys.Track(refTable, []string{refColumn})
+			}
+		}
+		for _, rule := range col.Rules {
+			cond, err := schema.ParseCondition(rule.Condition)
+			if err != nil {
+				continue
+			}
+			for _, field := range cond.Columns() {
+				if refTable, refColumn := schema.ConditionReference(field); refTable != "" {
+					c.keys.Track(refTable, []string{refColumn})
+				}
+			}
+		}
+	}
+}
+
+// parentValue returns a lookup of the parent rows the current row of a table
+// references, for generators that read "table.column"
+func (c *Coordinator) parentValue(table *schema.Table) func(refTable, column string) (interface{}, bool) {
+	return func(refTable, column string) (interface{}, bool) {
+		return c.parentColumn(table, refTable, column)
+	}
+}
+
+// parentColumn returns a column of the parent row in refTable that the
+// current row of table references through a foreign key
+func (c *Coordinator) parentColumn(table *schema.Table, refTable, column string) (interface{}, bool) {
+	parent, ok := c.rowParents[table.ForeignKeyTo(refTable)]
+	if !ok || c.keys == nil {
+		return nil, false
+	}
+	tuple, found := c.keys.Get(refTable, []string{column}, parent)
+	if !found {
+		return nil, false
+	}
+	return tuple[0], true
+}
+
+// ruleValue applies the business rules of a column, reporting false when
+// none applies and the column's generator should be used
+func (c *Coordinator) ruleValue(ctx *generator.Context, col *schema.Column) (interface{}, bool, error) {
+	gen, ok := c.rules[col]
+	if !ok {
+		var err error
+		if gen, err = generator.NewRulesGenerator(col.Rules, nil); err != nil {
+			return nil, false, err
+		}
+		if c.rules == nil {
+			c.rules = make(map[*schema.Column]*generator.RulesGenerator)
+		}
+		c.rules[col] = gen
+	}
+
+	val, applied, err := gen.Apply(ctx)
+	if err != nil || !applied {
+		return nil, applied, err
+	}
+	if f, ok := val.(float64); ok {
+		return typedNumber(col.Type, f, false), true, nil
+	}
+	return val, true, nil
+}
+
+// conditionalValue picks the value of a column with conditional weights
+func (c *Coordinator) conditionalValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	gen, ok := c.conditionals[col]
+	if !ok {
+		cond, err := col.Conditional()
+		if err != nil {
+			return nil, err
+		}
+		gen = generator.NewConditionalGenerator(cond)
+		if c.conditionals == nil {
+			c.conditionals = make(map[*schema.Column]*generator.ConditionalGenerator)
+		}
+		c.conditionals[col] = gen
+	}
+	return gen.Generate(ctx)
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+const (
+	defaultSelectionZipfAlpha   = 1.5
+	defaultSelectionParetoAlpha = 1.161 // log(5)/log(4): the top 20% get 80% of references
+)
+
+// pickParent returns the index of the parent row a foreign key references,
+// following the foreign key's selection distribution among the parent rows
+// that match its where filter.
+// It returns false when no recorded parent row qualifies.
+func (c *Coordinator) pickParent(rng *rand.Rand, fk *schema.ForeignKey) (int, bool) {
+	n := c.keys.Count(fk.ReferencedTable, referencedColumns(c.schema, fk))
+	if fk.Where != nil {
+		n = c.keys.SubsetCount(fk.ReferencedTable, whereKey(fk))
+	}
+	if n == 0 {
+		return 0, false
+	}
+
+	index := selectParent(rng, fk.Selection, n)
+	if fk.Where != nil {
+		return c.keys.SubsetRow(fk.ReferencedTable, whereKey(fk), index)
+	}
+	return index, true
+}
+
+// whereKey names the subset of parent rows matching a foreign key's where filter
+func whereKey(fk *schema.ForeignKey) string {
+	return fmt.Sprintf("where %v", fk.Where)
+}
+
+// selectParent draws a parent row index in [0, n) from a selection distribution
+func selectParent(rng *rand.Rand, sel *schema.Selection, n int) int {
+	if sel == nil {
+		return rng.Intn(n)
+	}
+	switch sel.Distribution {
+	case "zipf":
+		alpha := defaultSelectionZipfAlpha
+		if sel.Alpha != nil {
+			alpha = *sel.Alpha
+		}
+		return generator.ZipfValue(rng, alpha, 0, n-1)
+	case "pareto":
+		// Row ranks have density proportional to x^(-1/alpha) on (0, 1), so the
+		// first share p of the rows receives p^(1-1/alpha) of the references
+		alpha := defaultSelectionParetoAlpha
+		if sel.Alpha != nil {
+			alpha = *sel.Alpha
+		}
+		index := int(float64(n) * math.Pow(rng.Float64(), alpha/(alpha-1)))
+		return min(index, n-1)
+	case "hotspot":
+		hot := min(max(int(math.Round(sel.Top*float64(n))), 1), n)
+		if hot == n || rng.Float64() < sel.Share {
+			return rng.Intn(hot)
+		}
+		return hot + rng.Intn(n-hot)
+	default:
+		return rng.Intn(n)
+	}
+}
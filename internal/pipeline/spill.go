@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"time"
+)
+
+func init() {
+	// Generated values stored as interface{} beyond gob's built-in types
+	gob.Register(time.Time{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// spillFile stores key tuples in a temporary file, each encoded on its own so
+// any tuple can be read back by offset
+type spillFile struct {
+	file    *os.File
+	offsets []int64
+	size    int64
+}
+
+// newSpillFile creates an empty spill file in dir (default: os.TempDir)
+func newSpillFile(dir string) (*spillFile, error) {
+	file, err := os.CreateTemp(dir, "datagen-keys-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key spill file: %w", err)
+	}
+	return &spillFile{file: file}, nil
+}
+
+// append writes a tuple at the end of the file
+func (sf *spillFile) append(tuple []interface{}) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(tuple); err != nil {
+		return fmt.Errorf("failed to encode spilled keys: %w", err)
+	}
+	if _, err := sf.file.WriteAt(buf.Bytes(), sf.size); err != nil {
+		return fmt.Errorf("failed to write key spill file: %w", err)
+	}
+	sf.offsets = append(sf.offsets, sf.size)
+	sf.size += int64(buf.Len())
+	return nil
+}
+
+// get reads the tuple at index
+func (sf *spillFile) get(index int) ([]interface{}, error) {
+	end := sf.size
+	if index+1 < len(sf.offsets) {
+		end = sf.offsets[index+1]
+	}
+	data := make([]byte, end-sf.offsets[index])
+	if _, err := sf.file.ReadAt(data, sf.offsets[index]); err != nil {
+		return nil, fmt.Errorf("failed to read key spill file: %w", err)
+	}
+
+	var tuple []interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&tuple); err != nil {
+		return nil, fmt.Errorf("failed to decode spilled keys: %w", err)
+	}
+	return tuple, nil
+}
+
+// count returns the number of tuples in the file
+func (sf *spillFile) count() int {
+	return len(sf.offsets)
+}
+
+// reset discards every tuple
+func (sf *spillFile) reset() error {
+	sf.offsets = nil
+	sf.size = 0
+	return sf.file.Truncate(0)
+}
+
+// close closes and removes the file
+func (sf *spillFile) close() error {
+	sf.file.Close()
+	return os.Remove(sf.file.Name())
+}
@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// trackTemporal keeps the parent timestamps that temporal constraints of a table read
+func (c *Coordinator) trackTemporal(table *schema.Table) {
+	for _, col := range table.Columns {
+		if col.Temporal == nil {
+			continue
+		}
+		if refTable, refColumn := col.Temporal.Reference(); refTable != "" {
+			c.keys.Track(refTable, []string{refColumn})
+		}
+	}
+}
+
+// timeBounds returns the interval a column's value must fall in, or nil when
+// it has no temporal constraint or the referenced timestamp is NULL
+func (c *Coordinator) timeBounds(table *schema.Table, col *schema.Column, row map[string]interface{}) *generator.TimeBounds {
+	if col.Temporal == nil {
+		return nil
+	}
+
+	refTable, refColumn := col.Temporal.Reference()
+	ref := row[refColumn]
+	if refTable != "" {
+		ref, _ = c.parentColumn(table, refTable, refColumn)
+	}
+
+	after, ok := ref.(time.Time)
+	if !ok {
+		return nil
+	}
+	bounds := &generator.TimeBounds{After: after}
+	if window, _ := col.Temporal.Window(); window > 0 {
+		bounds.Before = after.Add(window)
+	}
+	return bounds
+}
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"path/filepath"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// textValue writes the text of a column, training its Markov chain on the
+// corpus the first time
+func (c *Coordinator) textValue(ctx *generator.Context, col *schema.Column) (interface{}, error) {
+	gen, ok := c.texts[col]
+	if !ok {
+		config, err := col.Text()
+		if err != nil {
+			return nil, err
+		}
+
+		var chain *generator.MarkovChain
+		if config.Mode == "markov" {
+			if chain, err = generator.LoadMarkovChain(c.schemaPath(config.Corpus), config.Order); err != nil {
+				return nil, err
+			}
+		}
+		if gen, err = generator.NewStructuredTextGenerator(config, chain); err != nil {
+			return nil, err
+		}
+		if c.texts == nil {
+			c.texts = make(map[*schema.Column]*generator.StructuredTextGenerator)
+		}
+		c.texts[col] = gen
+	}
+	return gen.Generate(ctx)
+}
+
+// schemaPath resolves a file named in the schema against the base directory
+func (c *Coordinator) schemaPath(name string) string {
+	if filepath.IsAbs(name) || c.baseDir == "" {
+		return name
+	}
+	return filepath.Join(c.baseDir, name)
+}
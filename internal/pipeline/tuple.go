@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// assignTuples fills the columns of each multi-column generator of a row
+// from one generated tuple
+func (c *Coordinator) assignTuples(ctx *generator.Context, table *schema.Table, row map[string]interface{}) error {
+	for _, tuple := range table.Tuples {
+		gen, ok := c.tuples[tuple]
+		if !ok {
+			var err error
+			if gen, err = generator.NewTupleGenerator(tuple); err != nil {
+				return err
+			}
+			if c.tuples == nil {
+				c.tuples = make(map[*schema.Tuple]generator.TupleGenerator)
+			}
+			c.tuples[tuple] = gen
+		}
+
+		values, err := gen.GenerateTuple(ctx)
+		if err != nil {
+			return err
+		}
+		for role, name := range tuple.Columns {
+			val := values[role]
+			if f, ok := val.(float64); ok {
+				if col := table.Column(name); col != nil {
+					val = typedNumber(col.Type, f, false)
+				}
+			}
+			row[name] = val
+		}
+	}
+	return nil
+}
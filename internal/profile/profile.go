@@ -0,0 +1,250 @@
+package profile
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+)
+
+// Default limits of a profile
+const (
+	DefaultTopK = 20
+	DefaultBins = 20
+)
+
+// Options controls how columns are summarised
+type Options struct {
+	TopK int // most frequent values kept with their share; numbers with at most TopK distinct values keep them all
+	Bins int // histogram bins of other numeric columns
+}
+
+// Profile holds the distribution learned from each column of sample rows
+type Profile struct {
+	Source  string           `json:"source,omitempty"`
+	Rows    int              `json:"rows"`
+	Columns []*ColumnProfile `json:"columns"`
+}
+
+// ColumnProfile is the distribution learned from one column, ready to be
+// used as {"type": "distribution", "distribution": ...} generator config.
+// Columns without any value have no distribution.
+type ColumnProfile struct {
+	Name         string                     `json:"name"`
+	Kind         string                     `json:"kind"` // integer, numeric, boolean, text, or empty when all values are NULL
+	Distinct     int                        `json:"distinct"`
+	Distribution *schema.DistributionConfig `json:"distribution,omitempty"`
+}
+
+// ReadCSV reads sample rows from CSV with a header line. Empty fields are
+// NULL (nil); other values are strings.
+func ReadCSV(r io.Reader) ([]string, [][]interface{}, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("CSV input is empty, expected a header line")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	var rows [][]interface{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return header, rows, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		row := make([]interface{}, len(record))
+		for i, field := range record {
+			if field != "" {
+				row[i] = field
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// Build profiles sample rows (strings, or nil for NULL) column by column
+func Build(columns []string, rows [][]interface{}, opts Options) *Profile {
+	if opts.TopK <= 0 {
+		opts.TopK = DefaultTopK
+	}
+	if opts.Bins <= 0 {
+		opts.Bins = DefaultBins
+	}
+
+	p := &Profile{Rows: len(rows)}
+	for i, name := range columns {
+		var values []string
+		for _, row := range rows {
+			if s, ok := row[i].(string); ok {
+				values = append(values, s)
+			}
+		}
+		p.Columns = append(p.Columns, profileColumn(name, values, len(rows), opts))
+	}
+	return p
+}
+
+// profileColumn learns the distribution of the non-NULL values of a column
+func profileColumn(name string, values []string, rows int, opts Options) *ColumnProfile {
+	counts := make(map[string]int)
+	for _, v := range values {
+		counts[v]++
+	}
+	col := &ColumnProfile{Name: name, Distinct: len(counts)}
+	if len(values) == 0 {
+		return col
+	}
+
+	col.Kind = kindOf(counts)
+	switch col.Kind {
+	case "integer", "numeric":
+		numbers := make([]float64, len(values))
+		for i, v := range values {
+			numbers[i], _ = strconv.ParseFloat(v, 64)
+		}
+		col.Distribution = numericDistribution(numbers, opts)
+	case "boolean":
+		weights := make(map[string]int)
+		for v, n := range counts {
+			weights[strconv.FormatBool(strings.EqualFold(v, "true") || strings.EqualFold(v, "t"))] += n
+		}
+		col.Distribution = textDistribution(weights, len(values), opts)
+	default:
+		col.Distribution = textDistribution(counts, len(values), opts)
+	}
+	col.Distribution.NullRate = share(rows-len(values), rows)
+	return col
+}
+
+// kindOf returns the narrowest kind all values of a column fit
+func kindOf(counts map[string]int) string {
+	integer, numeric, boolean := true, true, true
+	for v := range counts {
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			integer = false
+		}
+		if f, err := strconv.ParseFloat(v, 64); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			numeric = false
+		}
+		switch strings.ToLower(v) {
+		case "true", "false", "t", "f":
+		default:
+			boolean = false
+		}
+	}
+	switch {
+	case integer:
+		return "integer"
+	case numeric:
+		return "numeric"
+	case boolean:
+		return "boolean"
+	default:
+		return "text"
+	}
+}
+
+// numericDistribution keeps every value of numbers with few distinct values
+// (as bins of a single value), or builds an equal-width histogram
+func numericDistribution(numbers []float64, opts Options) *schema.DistributionConfig {
+	sort.Float64s(numbers)
+	config := &schema.DistributionConfig{Type: "empirical"}
+
+	var distinct []float64
+	counts := make(map[float64]int)
+	for _, n := range numbers {
+		if counts[n] == 0 {
+			distinct = append(distinct, n)
+		}
+		counts[n]++
+	}
+	if len(distinct) <= opts.TopK {
+		for _, n := range distinct {
+			config.Bins = append(config.Bins, &schema.HistogramBin{Min: n, Max: n, Weight: share(counts[n], len(numbers))})
+		}
+		return config
+	}
+
+	low, high := numbers[0], numbers[len(numbers)-1]
+	width := (high - low) / float64(opts.Bins)
+	binCounts := make([]int, opts.Bins)
+	for _, n := range numbers {
+		i := int((n - low) / width)
+		if i >= opts.Bins {
+			i = opts.Bins - 1
+		}
+		binCounts[i]++
+	}
+	for i, count := range binCounts {
+		if count == 0 {
+			continue
+		}
+		config.Bins = append(config.Bins, &schema.HistogramBin{
+			Min:    roundEdge(low + width*float64(i)),
+			Max:    roundEdge(low + width*float64(i+1)),
+			Weight: share(count, len(numbers)),
+		})
+	}
+	config.Bins[0].Min, config.Bins[len(config.Bins)-1].Max = low, high
+	return config
+}
+
+// textDistribution keeps the shares of the most frequent values; when there
+// are more distinct values, the rest are described by their lengths
+func textDistribution(counts map[string]int, total int, opts Options) *schema.DistributionConfig {
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	config := &schema.DistributionConfig{Type: "empirical", Weights: make(map[string]interface{})}
+	for i, v := range values {
+		if i == opts.TopK {
+			break
+		}
+		config.Weights[v] = share(counts[v], total)
+	}
+	if len(values) <= opts.TopK {
+		return config
+	}
+
+	var lengths []float64
+	for _, v := range values[opts.TopK:] {
+		for n := 0; n < counts[v]; n++ {
+			lengths = append(lengths, float64(utf8.RuneCountInString(v)))
+		}
+	}
+	config.Length = numericDistribution(lengths, opts)
+	return config
+}
+
+// share returns count/total, rounded down to 6 decimals so that shares never
+// add up to more than 1
+func share(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Floor(float64(count)/float64(total)*1e6) / 1e6
+}
+
+// roundEdge rounds a histogram bin edge to 6 decimals
+func roundEdge(x float64) float64 {
+	return math.Round(x*1e6) / 1e6
+}
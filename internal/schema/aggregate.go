@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggregateGenerator is the generator type that summarises child rows
+const AggregateGenerator = "aggregate"
+
+// Aggregate functions
+var aggregateFunctions = map[string]bool{
+	"sum": true, "count": true, "min": true, "max": true, "avg": true,
+}
+
+// Aggregate computes a column from the child rows that reference the row.
+// It is configured as a generator:
+//
+//	{"type": "aggregate", "function": "sum", "from": "order_items", "via": "order_id",
+//	 "value": "quantity * unit_price"}
+//	{"type": "aggregate", "function": "count", "from": "orders", "via": "customer_id"}
+type Aggregate struct {
+	Function string   // sum, count, min, max or avg
+	Table    string   // child table
+	Via      string   // foreign key column of the child table referencing this table
+	Factors  []string // child columns or numbers multiplied into the aggregated value
+}
+
+// Aggregate returns the aggregate configured on a column, or nil when the
+// column uses another generator
+func (col *Column) Aggregate() (*Aggregate, error) {
+	genType := col.GeneratorType
+	if genType == "" {
+		genType, _ = col.GeneratorConfig["type"].(string)
+	}
+	if genType != AggregateGenerator {
+		return nil, nil
+	}
+
+	agg := &Aggregate{}
+	agg.Function, _ = col.GeneratorConfig["function"].(string)
+	agg.Table, _ = col.GeneratorConfig["from"].(string)
+	agg.Via, _ = col.GeneratorConfig["via"].(string)
+	if !aggregateFunctions[agg.Function] {
+		return nil, fmt.Errorf("unknown aggregate function %q (use one of: sum, count, min, max, avg)", agg.Function)
+	}
+	if agg.Table == "" || agg.Via == "" {
+		return nil, fmt.Errorf("aggregate needs 'from' (child table) and 'via' (its foreign key column)")
+	}
+
+	if value, _ := col.GeneratorConfig["value"].(string); value != "" {
+		for _, factor := range strings.Split(value, "*") {
+			agg.Factors = append(agg.Factors, strings.TrimSpace(factor))
+		}
+	}
+	if len(agg.Factors) == 0 && agg.Function != "count" {
+		return nil, fmt.Errorf("aggregate %s needs a 'value' (e.g. \"quantity * unit_price\")", agg.Function)
+	}
+	return agg, nil
+}
+
+// Columns returns the child columns the aggregated value is computed from
+func (a *Aggregate) Columns() []string {
+	var cols []string
+	for _, factor := range a.Factors {
+		if _, err := strconv.ParseFloat(factor, 64); err != nil {
+			cols = append(cols, factor)
+		}
+	}
+	return cols
+}
@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConditionalGenerator is the generator type that picks a value with weights
+// that depend on other columns
+const ConditionalGenerator = "conditional"
+
+// Conditional is a conditional probability table: the weights of the
+// column's values depend on the values of the given columns of the row, or of
+// the parent row as "table.column". It is configured as a generator:
+//
+//	{"type": "conditional", "given": ["country"], "weights": {
+//	  "JP": {"JPY": 95, "USD": 5},
+//	  "US": {"USD": 100},
+//	  "*":  {"EUR": 70, "USD": 30}
+//	}}
+//
+// With several given columns, keys join their values with "|" ("JP|mobile").
+// A "*" matches any value, and "*" alone matches any row.
+type Conditional struct {
+	Given   []string
+	Entries []*ConditionalEntry // most specific first
+}
+
+// ConditionalEntry holds the weights used when the given columns match Key
+type ConditionalEntry struct {
+	Key     []string // one value per given column, "*" for any
+	Weights map[string]float64
+}
+
+// Conditional returns the conditional probability table configured on a
+// column, or nil when the column uses another generator
+func (col *Column) Conditional() (*Conditional, error) {
+	genType := col.GeneratorType
+	if genType == "" {
+		genType, _ = col.GeneratorConfig["type"].(string)
+	}
+	if genType != ConditionalGenerator {
+		return nil, nil
+	}
+
+	cond := &Conditional{}
+	given, _ := col.GeneratorConfig["given"].([]interface{})
+	for _, g := range given {
+		field, ok := g.(string)
+		if !ok || field == "" {
+			return nil, fmt.Errorf("conditional 'given' must list column names")
+		}
+		cond.Given = append(cond.Given, field)
+	}
+	if len(cond.Given) == 0 {
+		return nil, fmt.Errorf("conditional 'given' must list the columns the weights depend on")
+	}
+
+	table, _ := col.GeneratorConfig["weights"].(map[string]interface{})
+	if len(table) == 0 {
+		return nil, fmt.Errorf("conditional 'weights' must map values of %s to weights", strings.Join(cond.Given, "|"))
+	}
+	for _, key := range sortedKeys(table) {
+		entry := &ConditionalEntry{Key: strings.Split(key, "|")}
+		if key == "*" {
+			entry.Key = make([]string, len(cond.Given))
+			for i := range entry.Key {
+				entry.Key[i] = "*"
+			}
+		}
+		if len(entry.Key) != len(cond.Given) {
+			return nil, fmt.Errorf("conditional key %q needs %d value(s) joined with '|'", key, len(cond.Given))
+		}
+
+		weights, _ := table[key].(map[string]interface{})
+		if len(weights) == 0 {
+			return nil, fmt.Errorf("conditional key %q must map values to weights", key)
+		}
+		entry.Weights = make(map[string]float64, len(weights))
+		total := 0.0
+		for value, w := range weights {
+			weight, ok := w.(float64)
+			if !ok || weight < 0 {
+				return nil, fmt.Errorf("conditional key %q: weight of %q must be a non-negative number", key, value)
+			}
+			entry.Weights[value] = weight
+			total += weight
+		}
+		if total == 0 {
+			return nil, fmt.Errorf("conditional key %q: weights must not all be zero", key)
+		}
+		cond.Entries = append(cond.Entries, entry)
+	}
+
+	// Exact keys take precedence over keys with wildcards
+	sort.SliceStable(cond.Entries, func(i, j int) bool {
+		return cond.Entries[i].wildcards() < cond.Entries[j].wildcards()
+	})
+	return cond, nil
+}
+
+func (e *ConditionalEntry) wildcards() int {
+	n := 0
+	for _, part := range e.Key {
+		if part == "*" {
+			n++
+		}
+	}
+	return n
+}
+
+// Match returns the most specific entry matching the values of the given
+// columns, or nil. NULL values only match "*".
+func (c *Conditional) Match(values []interface{}) *ConditionalEntry {
+	for _, entry := range c.Entries {
+		matches := true
+		for i, part := range entry.Key {
+			if part != "*" && (values[i] == nil || fmt.Sprintf("%v", values[i]) != part) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Correlation draws a group of numeric columns together: each column follows
+// its marginal distribution, and the group follows the correlation matrix
+// (a Gaussian copula). Matrix rows and columns are in the order of Columns.
+//
+//	"correlations": [{
+//	  "columns": ["height", "weight"],
+//	  "marginals": {
+//	    "height": {"type": "normal", "mean": 170, "std_dev": 9},
+//	    "weight": {"type": "log_normal", "mean": 4.3, "std_dev": 0.15}
+//	  },
+//	  "matrix": [[1, 0.7], [0.7, 1]]
+//	}]
+type Correlation struct {
+	Columns   []string                       `json:"columns"`
+	Marginals map[string]*DistributionConfig `json:"marginals"`
+	Matrix    [][]float64                    `json:"matrix"`
+}
+
+// Cholesky returns the lower triangular factor L of the correlation matrix
+// (L·Lᵀ = matrix), checking that the matrix is a valid correlation matrix
+func (c *Correlation) Cholesky() ([][]float64, error) {
+	n := len(c.Columns)
+	if len(c.Matrix) != n {
+		return nil, fmt.Errorf("matrix has %d rows, expected %d (one per column)", len(c.Matrix), n)
+	}
+	// Every row is checked before the symmetry check reads across rows
+	for i, row := range c.Matrix {
+		if len(row) != n {
+			return nil, fmt.Errorf("matrix row %d has %d values, expected %d", i+1, len(row), n)
+		}
+	}
+	for i, row := range c.Matrix {
+		if row[i] != 1 {
+			return nil, fmt.Errorf("matrix diagonal must be 1, got %g in row %d", row[i], i+1)
+		}
+		for j, v := range row {
+			if v < -1 || v > 1 {
+				return nil, fmt.Errorf("correlation %g between '%s' and '%s' is outside [-1, 1]", v, c.Columns[i], c.Columns[j])
+			}
+			if v != c.Matrix[j][i] {
+				return nil, fmt.Errorf("matrix is not symmetric: %g and %g between '%s' and '%s'", v, c.Matrix[j][i], c.Columns[i], c.Columns[j])
+			}
+		}
+	}
+
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := c.Matrix[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 1e-12 {
+					return nil, fmt.Errorf("matrix is not positive definite, the correlations contradict each other")
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, nil
+}
+
+// ValidateMarginal checks the marginal distribution of a correlated column:
+// "normal" (mean, std_dev), "log_normal" (mean and std_dev of the value's
+// logarithm), "uniform" (min, max) or "empirical" (observed values or
+// histogram bins). Normal
+// and log-normal values are clamped to min and max when set.
+func ValidateMarginal(config *DistributionConfig) error {
+	switch config.Type {
+	case "normal", "log_normal":
+		if config.Mean == nil || config.StdDev == nil {
+			return fmt.Errorf("%s distribution needs 'mean' and 'std_dev'", config.Type)
+		}
+		if *config.StdDev <= 0 {
+			return fmt.Errorf("%s distribution needs a positive 'std_dev'", config.Type)
+		}
+	case "uniform":
+		min, minOK := config.Min.(float64)
+		max, maxOK := config.Max.(float64)
+		if !minOK || !maxOK {
+			return fmt.Errorf("uniform distribution needs numeric 'min' and 'max'")
+		}
+		if min >= max {
+			return fmt.Errorf("uniform distribution needs 'min' below 'max'")
+		}
+		return nil
+	case "empirical":
+		if len(config.Values) == 0 && len(config.Bins) == 0 {
+			return fmt.Errorf("empirical distribution needs 'values' or 'bins'")
+		}
+		return config.ValidateEmpirical()
+	default:
+		return fmt.Errorf("unknown marginal type '%s' (use normal, log_normal, uniform or empirical)", config.Type)
+	}
+
+	for _, bound := range []interface{}{config.Min, config.Max} {
+		if _, ok := bound.(float64); bound != nil && !ok {
+			return fmt.Errorf("'min' and 'max' must be numbers")
+		}
+	}
+	return nil
+}
+
+// isNumericType reports whether a column type holds numbers
+func isNumericType(typeName string) bool {
+	switch strings.TrimSpace(strings.Split(strings.ToLower(typeName), "(")[0]) {
+	case "smallint", "integer", "int", "bigint", "real", "double precision", "numeric", "decimal":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DictionaryGenerator is the generator type that draws values from a
+// reference data file
+const DictionaryGenerator = "dictionary"
+
+// Dictionary draws the value of a column from a CSV or text file of
+// reference data (SKU catalogues, ICD-10 codes, airport codes):
+//
+//	{"type": "dictionary", "file": "data/codes.txt"}
+//	{"type": "dictionary", "file": "data/airports.csv", "column": "iata", "weight": "passengers"}
+//	{"type": "dictionary", "file": "data/skus.csv", "column": "sku", "replacement": false}
+//
+// CSV (and TSV) files have a header line; other files hold one value per
+// line. Columns of a table naming the same file and draw read the same
+// dictionary row, so a code, name and price stay together; another draw
+// name picks another row (an origin and a destination airport).
+type Dictionary struct {
+	File        string // relative to the schema file
+	Column      string // file column to read, by default the one named like the column, else the first
+	Weight      string // optional file column of row weights
+	Replacement bool   // rows may repeat; without replacement each row is used at most once per table
+	Draw        string // name of the row draw shared by columns
+}
+
+// Dictionary returns the dictionary configured on a column, or nil when the
+// column uses another generator
+func (col *Column) Dictionary() (*Dictionary, error) {
+	genType := col.GeneratorType
+	if genType == "" {
+		genType, _ = col.GeneratorConfig["type"].(string)
+	}
+	if genType != DictionaryGenerator {
+		return nil, nil
+	}
+
+	dict := &Dictionary{Replacement: true}
+	dict.File, _ = col.GeneratorConfig["file"].(string)
+	if strings.TrimSpace(dict.File) == "" {
+		return nil, fmt.Errorf("dictionary needs a 'file' of reference data")
+	}
+
+	for key, field := range map[string]*string{"column": &dict.Column, "weight": &dict.Weight, "draw": &dict.Draw} {
+		raw, ok := col.GeneratorConfig[key]
+		if !ok {
+			continue
+		}
+		s, isString := raw.(string)
+		if !isString || s == "" {
+			return nil, fmt.Errorf("dictionary '%s' must be a non-empty string", key)
+		}
+		*field = s
+	}
+	if (dict.Column != "" || dict.Weight != "") && !dict.Tabular() {
+		return nil, fmt.Errorf("dictionary 'column' and 'weight' need a CSV or TSV file with a header line")
+	}
+
+	if raw, ok := col.GeneratorConfig["replacement"]; ok {
+		b, isBool := raw.(bool)
+		if !isBool {
+			return nil, fmt.Errorf("dictionary 'replacement' must be true or false")
+		}
+		dict.Replacement = b
+	}
+	return dict, nil
+}
+
+// Tabular reports whether the dictionary file has columns and a header line
+// (.csv and .tsv files) rather than one value per line
+func (d *Dictionary) Tabular() bool {
+	name := strings.ToLower(d.File)
+	return strings.HasSuffix(name, ".csv") || strings.HasSuffix(name, ".tsv")
+}
+
+// SameDraw reports whether two dictionaries read the same row of each draw
+func (d *Dictionary) SameDraw(other *Dictionary) bool {
+	return d.File == other.File && d.Draw == other.Draw
+}
+
+// IsNumeric reports whether the column holds numbers
+func (col *Column) IsNumeric() bool {
+	return isNumericType(col.Type)
+}
@@ -1,8 +1,20 @@
 package schema
 
+import (
+	"fmt"
+	"math"
+)
+
+// DistributionGenerator is the generator type that draws a column from a
+// distribution, such as the empirical distributions written by datagen profile:
+//
+//	{"type": "distribution", "distribution": {"type": "empirical", "null_rate": 0.1,
+//	 "bins": [{"min": 0, "max": 50, "weight": 0.7}, {"min": 50, "max": 200, "weight": 0.3}]}}
+const DistributionGenerator = "distribution"
+
 // DistributionConfig represents weighted distribution for column values
 type DistributionConfig struct {
-	// Type of distribution: "weighted", "normal", "poisson", "zipf"
+	// Type of distribution: "weighted", "normal", "poisson", "zipf", "empirical"
 	Type string `json:"type"`
 
 	// For weighted distribution: map of value -> weight
@@ -16,20 +28,196 @@ type DistributionConfig struct {
 	// For zipf distribution (power-law, e.g., popularity)
 	Alpha *float64 `json:"alpha,omitempty"` // Exponent parameter (typically 1.0-2.0)
 
+	// For empirical distribution: observed values, sampled by quantile
+	Values []float64 `json:"values,omitempty"`
+
+	// For empirical distribution: histogram bins, drawn by weight and uniformly
+	// within a bin (a bin whose min equals its max yields that exact value)
+	Bins []*HistogramBin `json:"bins,omitempty"`
+
+	// For empirical distribution of text: when the weights (shares of the
+	// values) add up to less than 1, the rest are random strings whose
+	// lengths follow this distribution
+	Length *DistributionConfig `json:"length,omitempty"`
+
+	// Share of NULL values, from 0 (default) to 1
+	NullRate float64 `json:"null_rate,omitempty"`
+
 	// Common parameters
 	Min interface{} `json:"min,omitempty"`
 	Max interface{} `json:"max,omitempty"`
 }
 
+// HistogramBin is one bin of an empirical distribution
+type HistogramBin struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Weight float64 `json:"weight"`
+}
+
+// Distribution returns the distribution a column is drawn from, or nil when
+// the column uses another generator
+func (col *Column) Distribution() (*DistributionConfig, error) {
+	genType := col.GeneratorType
+	if genType == "" {
+		genType, _ = col.GeneratorConfig["type"].(string)
+	}
+	if genType != DistributionGenerator {
+		return nil, nil
+	}
+	return ActionDistribution(col.GeneratorConfig)
+}
+
+// ParseDistribution reads and checks a distribution written as a JSON object
+func ParseDistribution(raw map[string]interface{}) (*DistributionConfig, error) {
+	config := &DistributionConfig{Min: raw["min"], Max: raw["max"]}
+	config.Type, _ = raw["type"].(string)
+	config.Weights, _ = raw["weights"].(map[string]interface{})
+	for key, field := range map[string]**float64{"mean": &config.Mean, "std_dev": &config.StdDev, "alpha": &config.Alpha} {
+		if v, ok := raw[key].(float64); ok {
+			*field = &v
+		}
+	}
+
+	switch config.Type {
+	case "weighted":
+		if len(config.Weights) == 0 {
+			return nil, fmt.Errorf("weighted distribution needs 'weights'")
+		}
+		if err := validateWeights(config.Weights); err != nil {
+			return nil, err
+		}
+	case "normal":
+		if config.Mean == nil || config.StdDev == nil {
+			return nil, fmt.Errorf("normal distribution needs 'mean' and 'std_dev'")
+		}
+	case "poisson":
+		if config.Mean == nil || *config.Mean <= 0 {
+			return nil, fmt.Errorf("poisson distribution needs a positive 'mean'")
+		}
+	case "zipf":
+		if config.Alpha == nil || *config.Alpha <= 1 {
+			return nil, fmt.Errorf("zipf distribution needs an 'alpha' greater than 1")
+		}
+	case "empirical":
+		if err := parseEmpirical(raw, config); err != nil {
+			return nil, err
+		}
+		if err := config.ValidateEmpirical(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown distribution type '%s' (use weighted, normal, poisson, zipf or empirical)", config.Type)
+	}
+
+	if rate, ok := raw["null_rate"]; ok {
+		f, isNumber := rate.(float64)
+		if !isNumber || f < 0 || f > 1 {
+			return nil, fmt.Errorf("'null_rate' must be a number between 0 and 1")
+		}
+		config.NullRate = f
+	}
+	return config, nil
+}
+
+// parseEmpirical reads the values, bins and length of an empirical distribution
+func parseEmpirical(raw map[string]interface{}, config *DistributionConfig) error {
+	if values, ok := raw["values"]; ok {
+		list, _ := values.([]interface{})
+		for _, v := range list {
+			f, ok := v.(float64)
+			if !ok {
+				return fmt.Errorf("empirical 'values' must be numbers, got %v", v)
+			}
+			config.Values = append(config.Values, f)
+		}
+	}
+
+	if bins, ok := raw["bins"]; ok {
+		list, _ := bins.([]interface{})
+		for _, b := range list {
+			obj, _ := b.(map[string]interface{})
+			min, minOK := obj["min"].(float64)
+			max, maxOK := obj["max"].(float64)
+			weight, weightOK := obj["weight"].(float64)
+			if !minOK || !maxOK || !weightOK {
+				return fmt.Errorf("empirical 'bins' must be objects with numeric 'min', 'max' and 'weight'")
+			}
+			config.Bins = append(config.Bins, &HistogramBin{Min: min, Max: max, Weight: weight})
+		}
+	}
+
+	if length, ok := raw["length"]; ok {
+		obj, isObject := length.(map[string]interface{})
+		if !isObject {
+			return fmt.Errorf("'length' expects an object")
+		}
+		parsed, err := ParseDistribution(obj)
+		if err != nil {
+			return fmt.Errorf("invalid length: %w", err)
+		}
+		config.Length = parsed
+	}
+	return nil
+}
+
+// ValidateEmpirical checks an empirical distribution: numbers drawn from
+// observed values or histogram bins, or text drawn from weights (shares of
+// the values) and, for the rest, random strings of a given length
+func (d *DistributionConfig) ValidateEmpirical() error {
+	numeric := len(d.Values) > 0 || len(d.Bins) > 0
+	text := len(d.Weights) > 0 || d.Length != nil
+	switch {
+	case numeric && text:
+		return fmt.Errorf("empirical distribution takes 'values' or 'bins' for numbers, or 'weights' and 'length' for text, not both")
+	case !numeric && !text:
+		return fmt.Errorf("empirical distribution needs 'values', 'bins' or 'weights'")
+	case len(d.Values) > 0 && len(d.Bins) > 0:
+		return fmt.Errorf("empirical distribution takes 'values' or 'bins', not both")
+	}
+
+	total := 0.0
+	for _, bin := range d.Bins {
+		if bin.Min > bin.Max || bin.Weight < 0 {
+			return fmt.Errorf("empirical bin [%g, %g] needs 'min' up to 'max' and a weight of at least 0", bin.Min, bin.Max)
+		}
+		total += bin.Weight
+	}
+	if len(d.Bins) > 0 && total <= 0 {
+		return fmt.Errorf("empirical bins need a positive total weight")
+	}
+
+	total = 0
+	for value, weight := range d.Weights {
+		w, ok := weight.(float64)
+		if !ok || w < 0 {
+			return fmt.Errorf("empirical weight of %q must be a number of at least 0", value)
+		}
+		total += w
+	}
+	if d.Length != nil {
+		if total > 1+1e-9 {
+			return fmt.Errorf("empirical weights are shares of the values and add up to %g, more than 1", math.Round(total*1e6)/1e6)
+		}
+		if d.Length.Type != "empirical" || len(d.Length.Weights) > 0 || d.Length.Length != nil {
+			return fmt.Errorf("'length' must be an empirical distribution of numbers")
+		}
+	} else if len(d.Weights) > 0 && total <= 0 {
+		return fmt.Errorf("empirical weights need a positive total")
+	}
+	return nil
+}
+
 // BusinessRule represents conditional logic for data generation
 type BusinessRule struct {
-	// Condition to check (evaluated against other columns in same row)
+	// Condition to check against other columns of the same row, or "table.column"
+	// of a parent row (see Condition)
 	Condition map[string]interface{} `json:"if"`
 
 	// Action to take if condition is true
 	Then map[string]interface{} `json:"then"`
 
-	// Optional else clause
+	// Optional else clause, applied when the condition fails; no later rule is evaluated
 	Else map[string]interface{} `json:"else,omitempty"`
 }
 
@@ -42,3 +230,20 @@ type PatternConfig struct {
 	// Available variables (computed or custom)
 	Variables map[string]interface{} `json:"variables,omitempty"`
 }
+
+// validateWeights checks that weights map each value to a non-negative
+// number, and not all to zero
+func validateWeights(weights map[string]interface{}) error {
+	total := 0.0
+	for _, value := range sortedKeys(weights) {
+		w, ok := weights[value].(float64)
+		if !ok || w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("weight of %q must be a non-negative number, got %v", value, weights[value])
+		}
+		total += w
+	}
+	if total <= 0 {
+		return fmt.Errorf("weights must not all be zero")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package schema
+
+import "github.com/NhaLeTruc/datagen-cli/internal/expression"
+
+// RowDependencies returns the columns of the same row that must be generated
+// before a column: those its expression, rule conditions and conditional
+// weights read, the column its temporal constraint follows and the start of
+// its lifecycle
+func (col *Column) RowDependencies() []string {
+	var deps []string
+	if col.Expression != "" {
+		if expr, err := expression.Parse(col.Expression); err == nil {
+			deps = append(deps, expr.Columns()...)
+		}
+	}
+	for _, rule := range col.Rules {
+		if cond, err := ParseCondition(rule.Condition); err == nil {
+			for _, field := range cond.Columns() {
+				if refTable, _ := ConditionReference(field); refTable == "" {
+					deps = append(deps, field)
+				}
+			}
+		}
+	}
+	if cond, err := col.Conditional(); err == nil && cond != nil {
+		for _, field := range cond.Given {
+			if refTable, _ := ConditionReference(field); refTable == "" {
+				deps = append(deps, field)
+			}
+		}
+	}
+	if col.Lifecycle != nil {
+		if refTable, refColumn := ConditionReference(col.Lifecycle.Start); refTable == "" && refColumn != "" {
+			deps = append(deps, refColumn)
+		}
+	}
+	if col.Temporal != nil {
+		if refTable, refColumn := col.Temporal.Reference(); refTable == "" {
+			deps = append(deps, refColumn)
+		}
+	}
+	return deps
+}
@@ -0,0 +1,80 @@
+package schema
+
+import "sort"
+
+// TableDependencies returns the tables that must be loaded before a table:
+// those it references through foreign keys or polymorphic references,
+// excluding self-references and keys that break a cycle, sorted by name
+func TableDependencies(tableName string, table *Table) []string {
+	seen := make(map[string]bool)
+	var deps []string
+	for _, fk := range table.ForeignKeys {
+		if fk.ReferencedTable == tableName || fk.CycleBreak != "" || seen[fk.ReferencedTable] {
+			continue
+		}
+		seen[fk.ReferencedTable] = true
+		deps = append(deps, fk.ReferencedTable)
+	}
+	for _, poly := range table.Polymorphic {
+		for _, target := range poly.Targets {
+			if target.Table == tableName || seen[target.Table] {
+				continue
+			}
+			seen[target.Table] = true
+			deps = append(deps, target.Table)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// TableOrder returns table names in dependency order: every table comes after
+// the tables it references. Ties are broken by name so the order is stable.
+// Tables caught in a foreign key cycle are appended in name order. The schema
+// is left unchanged.
+func TableOrder(s *Schema) []string {
+	names := make([]string, 0, len(s.Tables))
+	deps := make(map[string][]string, len(s.Tables))
+	for name, table := range s.Tables {
+		names = append(names, name)
+		deps[name] = TableDependencies(name, table)
+	}
+	sort.Strings(names)
+
+	placed := make(map[string]bool, len(names))
+	order := make([]string, 0, len(names))
+	for len(order) < len(names) {
+		progress := false
+		for _, name := range names {
+			if placed[name] || !dependenciesPlaced(s, deps[name], placed) {
+				continue
+			}
+			placed[name] = true
+			order = append(order, name)
+			progress = true
+			break // restart so the next table is again the first ready by name
+		}
+		if !progress {
+			break
+		}
+	}
+
+	// Cycles cannot be ordered; keep them in name order after the rest
+	for _, name := range names {
+		if !placed[name] {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// dependenciesPlaced reports whether every table in deps has been ordered
+// (references to unknown tables are ignored)
+func dependenciesPlaced(s *Schema, deps []string, placed map[string]bool) bool {
+	for _, dep := range deps {
+		if _, exists := s.Tables[dep]; exists && !placed[dep] {
+			return false
+		}
+	}
+	return true
+}
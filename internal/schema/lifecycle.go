@@ -0,0 +1,145 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+)
+
+// Lifecycle fills a status column by walking a state machine, together with
+// the timestamp columns recording when the row entered each state. Columns of
+// states the row never reached are NULL.
+//
+//	"lifecycle": {
+//	  "start": "created_at", "initial": "pending",
+//	  "states": {
+//	    "pending":   {"transitions": {"paid": 0.8, "cancelled": 0.15},
+//	                  "dwell": {"type": "log_normal", "mean": 1, "std_dev": 0.8}},
+//	    "paid":      {"timestamp": "paid_at", "transitions": {"shipped": 0.95},
+//	                  "dwell": {"type": "uniform", "min": 2, "max": 48}},
+//	    "shipped":   {"timestamp": "shipped_at", "transitions": {"delivered": 1},
+//	                  "dwell": {"type": "normal", "mean": 72, "std_dev": 24, "min": 12}},
+//	    "delivered": {"timestamp": "delivered_at"},
+//	    "cancelled": {"timestamp": "cancelled_at"}
+//	  }
+//	}
+type Lifecycle struct {
+	Start   string                     `json:"start"`   // column of the row, or "table.column" of the parent row, the initial state is entered at
+	Initial string                     `json:"initial"` // state every row starts in
+	States  map[string]*LifecycleState `json:"states"`
+}
+
+// LifecycleState is one state of a lifecycle. The transition probabilities
+// give the chance of moving on to each next state; the rest is the chance the
+// row is still in this state. States without transitions are final.
+type LifecycleState struct {
+	Timestamp   string              `json:"timestamp,omitempty"`   // column set to the time the state is entered
+	Transitions map[string]float64  `json:"transitions,omitempty"` // next states by probability
+	Dwell       *DistributionConfig `json:"dwell,omitempty"`       // hours spent in the state before moving on (see ValidateMarginal)
+}
+
+// NextStates returns the states a state can move on to, sorted by name
+func (s *LifecycleState) NextStates() []string {
+	return sortedKeys(s.Transitions)
+}
+
+// LifecycleOwner returns the column whose lifecycle fills a column: the
+// column itself for a status column, or the status column for a timestamp
+// column of one of its states
+func (t *Table) LifecycleOwner(column string) *Column {
+	for _, col := range t.Columns {
+		if col.Lifecycle == nil {
+			continue
+		}
+		if col.Name == column {
+			return col
+		}
+		for _, state := range col.Lifecycle.States {
+			if state.Timestamp == column {
+				return col
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the states of a lifecycle, returning one error per problem
+func (l *Lifecycle) Validate() []error {
+	var errs []error
+	if l.Start == "" {
+		errs = append(errs, fmt.Errorf("lifecycle 'start' must name the timestamp the initial state is entered at"))
+	}
+	if l.States[l.Initial] == nil {
+		errs = append(errs, fmt.Errorf("lifecycle initial state '%s' is not one of its states", l.Initial))
+	}
+
+	for _, name := range sortedKeys(l.States) {
+		state := l.States[name]
+		if state == nil {
+			errs = append(errs, fmt.Errorf("lifecycle state '%s' is empty", name))
+			continue
+		}
+
+		total := 0.0
+		for _, next := range state.NextStates() {
+			p := state.Transitions[next]
+			if l.States[next] == nil {
+				errs = append(errs, fmt.Errorf("lifecycle state '%s' moves on to unknown state '%s'", name, next))
+			}
+			if p <= 0 || p > 1 {
+				errs = append(errs, fmt.Errorf("lifecycle state '%s': probability of '%s' must be in (0, 1], got %g", name, next, p))
+			}
+			total += p
+		}
+		if total > 1+1e-9 {
+			errs = append(errs, fmt.Errorf("lifecycle state '%s': transition probabilities add up to %g, more than 1", name, math.Round(total*1e6)/1e6))
+		}
+
+		switch {
+		case len(state.Transitions) > 0 && state.Dwell == nil:
+			errs = append(errs, fmt.Errorf("lifecycle state '%s' needs a 'dwell' distribution (hours) before moving on", name))
+		case state.Dwell != nil:
+			if err := ValidateMarginal(state.Dwell); err != nil {
+				errs = append(errs, fmt.Errorf("lifecycle state '%s': invalid dwell: %v", name, err))
+			}
+		}
+	}
+
+	if cycle := l.cycle(); cycle != "" {
+		errs = append(errs, fmt.Errorf("lifecycle state '%s' can be reached again, so its timestamp would be overwritten", cycle))
+	}
+	return errs
+}
+
+// cycle returns a state that transitions lead back to, if any
+func (l *Lifecycle) cycle() string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int)
+	var visit func(name string) string
+	visit = func(name string) string {
+		switch marks[name] {
+		case visiting:
+			return name
+		case done:
+			return ""
+		}
+		marks[name] = visiting
+		if state := l.States[name]; state != nil {
+			for _, next := range state.NextStates() {
+				if found := visit(next); found != "" {
+					return found
+				}
+			}
+		}
+		marks[name] = done
+		return ""
+	}
+	for _, name := range sortedKeys(l.States) {
+		if found := visit(name); found != "" {
+			return found
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LookupGenerator is the generator type that reads a value from the parent row
+const LookupGenerator = "lookup"
+
+// Lookup copies, or maps, a column of the parent row a foreign key points at.
+// It is configured as a generator:
+//
+//	{"type": "lookup", "from": "products.price", "via": "product_id"}
+//	{"type": "lookup", "from": "customers.country", "via": "customer_id",
+//	 "map": {"US": "USD", "DE": "EUR"}, "default": "EUR"}
+type Lookup struct {
+	Table   string                 // parent table
+	Column  string                 // parent column to read
+	Via     string                 // foreign key column of this table
+	Map     map[string]interface{} // optional value mapping (keys are the parent values as text)
+	Default interface{}            // value for parent values missing from Map
+}
+
+// Lookup returns the lookup configured on a column, or nil when the column
+// uses another generator
+func (col *Column) Lookup() (*Lookup, error) {
+	genType := col.GeneratorType
+	if genType == "" {
+		genType, _ = col.GeneratorConfig["type"].(string)
+	}
+	if genType != LookupGenerator {
+		return nil, nil
+	}
+
+	from, _ := col.GeneratorConfig["from"].(string)
+	dot := strings.LastIndex(from, ".")
+	if dot <= 0 || dot == len(from)-1 {
+		return nil, fmt.Errorf("lookup 'from' must be 'table.column', got %q", from)
+	}
+	via, _ := col.GeneratorConfig["via"].(string)
+	if via == "" {
+		return nil, fmt.Errorf("lookup 'via' must name a foreign key column")
+	}
+
+	lookup := &Lookup{
+		Table:   from[:dot],
+		Column:  from[dot+1:],
+		Via:     via,
+		Default: col.GeneratorConfig["default"],
+	}
+	if m, ok := col.GeneratorConfig["map"].(map[string]interface{}); ok {
+		lookup.Map = m
+	}
+	return lookup, nil
+}
+
+// Apply derives the column value from the parent value
+func (l *Lookup) Apply(parentValue interface{}) interface{} {
+	if l.Map == nil || parentValue == nil {
+		return parentValue
+	}
+	if mapped, ok := l.Map[fmt.Sprintf("%v", parentValue)]; ok {
+		return mapped
+	}
+	return l.Default
+}
+
+// ForeignKeyFor returns the foreign key that includes a column, if any
+func (t *Table) ForeignKeyFor(column string) *ForeignKey {
+	for _, fk := range t.ForeignKeys {
+		for _, col := range fk.Columns {
+			if col == column {
+				return fk
+			}
+		}
+	}
+	return nil
+}
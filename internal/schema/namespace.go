@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultNamespace is the PostgreSQL schema tables live in unless configured otherwise
+const DefaultNamespace = "public"
+
+// ShouldCreateDatabase reports whether dumps start with CREATE DATABASE and \connect
+func (db DatabaseConfig) ShouldCreateDatabase() bool {
+	return db.CreateDatabase == nil || *db.CreateDatabase
+}
+
+// QualifiedTable returns the namespace and bare name of a table. The namespace
+// comes from the table's "schema" field, a "namespace.table" key, or the
+// database default, in that order. An empty namespace means public. The name
+// of a "namespace.table" key is never qualified twice.
+func (s *Schema) QualifiedTable(tableName string) (namespace, name string) {
+	namespace, name = s.Database.Schema, tableName
+	if i := strings.Index(tableName, "."); i > 0 {
+		namespace, name = tableName[:i], tableName[i+1:]
+	}
+	if table, ok := s.Tables[tableName]; ok && table.Schema != "" {
+		namespace = table.Schema
+	}
+	return namespace, name
+}
+
+// Namespaces returns the non-public namespaces used by the schema's tables, sorted
+func (s *Schema) Namespaces() []string {
+	seen := make(map[string]bool)
+	var namespaces []string
+	for tableName := range s.Tables {
+		ns, _ := s.QualifiedTable(tableName)
+		if ns == "" || ns == DefaultNamespace || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	if ns := s.Database.Schema; ns != "" && ns != DefaultNamespace && !seen[ns] {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
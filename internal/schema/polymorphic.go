@@ -0,0 +1,53 @@
+package schema
+
+// Polymorphic is a Rails-style reference to rows of several tables: the type
+// column holds the chosen table and the id column a key of one of its rows.
+// No foreign key constraint is created, but the targets are generated first.
+//
+//	"polymorphic": [{
+//	  "type_column": "commentable_type", "id_column": "commentable_id",
+//	  "targets": [{"table": "posts", "weight": 3}, {"table": "photos", "value": "Photo"}]
+//	}]
+type Polymorphic struct {
+	TypeColumn string               `json:"type_column"`
+	IDColumn   string               `json:"id_column"`
+	Targets    []*PolymorphicTarget `json:"targets"`
+}
+
+// PolymorphicTarget is a table a polymorphic reference can point at
+type PolymorphicTarget struct {
+	Table  string   `json:"table"`
+	Column string   `json:"column,omitempty"` // referenced column (default: the table's primary key)
+	Weight *float64 `json:"weight,omitempty"` // relative share of references (default: 1)
+	Value  string   `json:"value,omitempty"`  // type column value (default: the table name)
+}
+
+// TargetWeight returns the relative share of references of the target
+func (t *PolymorphicTarget) TargetWeight() float64 {
+	if t.Weight == nil {
+		return 1
+	}
+	return *t.Weight
+}
+
+// TypeValue returns the value stored in the type column for the target
+func (t *PolymorphicTarget) TypeValue() string {
+	if t.Value != "" {
+		return t.Value
+	}
+	return t.Table
+}
+
+// ReferencedColumn returns the target column the id column holds: Column, or
+// the target table's single primary key column. It is empty when neither exists.
+func (t *PolymorphicTarget) ReferencedColumn(s *Schema) string {
+	if t.Column != "" {
+		return t.Column
+	}
+	if target, exists := s.Tables[t.Table]; exists {
+		if pk := target.PrimaryKeyColumns(); len(pk) == 1 {
+			return pk[0]
+		}
+	}
+	return ""
+}
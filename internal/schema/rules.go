@@ -0,0 +1,315 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/expression"
+)
+
+// Condition is a parsed business rule condition. A condition maps columns to
+// the value they must equal, or to operators:
+//
+//	{"status": "shipped"}
+//	{"amount": {"gt": 100}, "country": {"in": ["US", "CA"]}}
+//	{"or": [{"email": {"is_null": true}}, {"customers.tier": {"not": "gold"}}]}
+//
+// Columns of the parent row a foreign key references are written "table.column".
+// All entries of a map must hold; "and", "or" and "not" combine conditions.
+type Condition struct {
+	op       string // and, or, not, or a comparison operator
+	field    string
+	operand  interface{}
+	pattern  *regexp.Regexp
+	children []*Condition
+}
+
+// Comparison operators of conditions
+var conditionOperators = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"between": true, "in": true, "not": true, "regex": true, "is_null": true,
+}
+
+// ParseCondition parses the "if" of a business rule. An empty condition always holds.
+func ParseCondition(raw map[string]interface{}) (*Condition, error) {
+	var children []*Condition
+	for _, key := range sortedKeys(raw) {
+		value := raw[key]
+		var child *Condition
+		var err error
+		switch key {
+		case "and", "or":
+			child, err = parseConditionList(key, value)
+		case "not":
+			nested, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("'not' expects a condition, got %v", value)
+			}
+			child, err = ParseCondition(nested)
+			child = &Condition{op: "not", children: []*Condition{child}}
+		default:
+			child, err = parseFieldTest(key, value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &Condition{op: "and", children: children}, nil
+}
+
+func parseConditionList(op string, value interface{}) (*Condition, error) {
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("'%s' expects a list of conditions", op)
+	}
+	cond := &Condition{op: op}
+	for _, item := range items {
+		nested, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'%s' expects a list of conditions, got %v", op, item)
+		}
+		child, err := ParseCondition(nested)
+		if err != nil {
+			return nil, err
+		}
+		cond.children = append(cond.children, child)
+	}
+	return cond, nil
+}
+
+// parseFieldTest parses the test of one column: a value to equal, or operators
+func parseFieldTest(field string, value interface{}) (*Condition, error) {
+	if value == nil {
+		return &Condition{op: "is_null", field: field, operand: true}, nil
+	}
+	operators, ok := value.(map[string]interface{})
+	if !ok {
+		return &Condition{op: "eq", field: field, operand: value}, nil
+	}
+	if len(operators) == 0 {
+		return nil, fmt.Errorf("column '%s' has no operators", field)
+	}
+
+	var children []*Condition
+	for _, op := range sortedKeys(operators) {
+		if !conditionOperators[op] {
+			return nil, fmt.Errorf("unknown operator '%s' for column '%s'", op, field)
+		}
+		operand := operators[op]
+		cond := &Condition{op: op, field: field, operand: operand}
+
+		switch op {
+		case "not":
+			// Either a value to differ from or operators to negate
+			if _, nested := operand.(map[string]interface{}); !nested {
+				cond.op = "ne"
+				break
+			}
+			child, err := parseFieldTest(field, operand)
+			if err != nil {
+				return nil, err
+			}
+			cond = &Condition{op: "not", children: []*Condition{child}}
+		case "between":
+			bounds, ok := operand.([]interface{})
+			if !ok || len(bounds) != 2 {
+				return nil, fmt.Errorf("'between' for column '%s' expects [low, high]", field)
+			}
+		case "in":
+			if _, ok := operand.([]interface{}); !ok {
+				return nil, fmt.Errorf("'in' for column '%s' expects a list of values", field)
+			}
+		case "regex":
+			pattern, ok := operand.(string)
+			if !ok {
+				return nil, fmt.Errorf("'regex' for column '%s' expects a pattern", field)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex for column '%s': %v", field, err)
+			}
+			cond.pattern = re
+		case "is_null":
+			if _, ok := operand.(bool); !ok {
+				return nil, fmt.Errorf("'is_null' for column '%s' expects true or false", field)
+			}
+		}
+		children = append(children, cond)
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &Condition{op: "and", children: children}, nil
+}
+
+// Columns returns the columns the condition reads, sorted
+func (c *Condition) Columns() []string {
+	seen := make(map[string]bool)
+	var walk func(c *Condition)
+	walk = func(c *Condition) {
+		if c.field != "" {
+			seen[c.field] = true
+		}
+		for _, child := range c.children {
+			walk(child)
+		}
+	}
+	walk(c)
+	return sortedKeys(seen)
+}
+
+// Matches evaluates the condition, reading columns through value. A column
+// without a value is NULL: it only satisfies is_null and its negations.
+func (c *Condition) Matches(value func(column string) (interface{}, bool)) bool {
+	switch c.op {
+	case "and":
+		for _, child := range c.children {
+			if !child.Matches(value) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, child := range c.children {
+			if child.Matches(value) {
+				return true
+			}
+		}
+		return false
+	case "not":
+		return !c.children[0].Matches(value)
+	}
+
+	actual, _ := value(c.field)
+	if c.op == "is_null" {
+		return (actual == nil) == c.operand.(bool)
+	}
+	if actual == nil {
+		return false
+	}
+
+	switch c.op {
+	case "eq":
+		return valuesEqual(actual, c.operand)
+	case "ne":
+		return !valuesEqual(actual, c.operand)
+	case "in":
+		for _, option := range c.operand.([]interface{}) {
+			if valuesEqual(actual, option) {
+				return true
+			}
+		}
+		return false
+	case "between":
+		bounds := c.operand.([]interface{})
+		low, lowErr := expression.Compare(actual, bounds[0])
+		high, highErr := expression.Compare(actual, bounds[1])
+		return lowErr == nil && highErr == nil && low >= 0 && high <= 0
+	case "regex":
+		return c.pattern.MatchString(expression.Text(actual))
+	}
+
+	cmp, err := expression.Compare(actual, c.operand)
+	if err != nil {
+		return false
+	}
+	switch c.op {
+	case "gt":
+		return cmp > 0
+	case "gte":
+		return cmp >= 0
+	case "lt":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// valuesEqual compares values by their order when they have one (so 5 equals
+// 5.0 and "5"), and as text otherwise
+func valuesEqual(actual, expected interface{}) bool {
+	if cmp, err := expression.Compare(actual, expected); err == nil {
+		return cmp == 0
+	}
+	return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", expected)
+}
+
+// ConditionReference splits a condition column into the parent table (empty for the
+// same row) and column
+func ConditionReference(field string) (string, string) {
+	dot := strings.LastIndex(field, ".")
+	if dot < 0 {
+		return "", field
+	}
+	return field[:dot], field[dot+1:]
+}
+
+// ValidateAction checks the "then" or "else" of a business rule, which sets
+// the value, draws it from a range, a distribution or weights, or uses a
+// named generator:
+//
+//	{"value": "priority"}
+//	{"min": 10, "max": 50}
+//	{"distribution": {"type": "normal", "mean": 100, "std_dev": 15}}
+//	{"weights": {"express": 1, "standard": 4}}
+//	{"generator": "email"}
+func ValidateAction(action map[string]interface{}) error {
+	switch {
+	case action == nil:
+		return fmt.Errorf("action is empty")
+	case hasKey(action, "value"):
+		return nil
+	case hasKey(action, "min") || hasKey(action, "max"):
+		if !hasKey(action, "min") || !hasKey(action, "max") {
+			return fmt.Errorf("'min' and 'max' must be set together")
+		}
+		return nil
+	case hasKey(action, "distribution"):
+		_, err := ActionDistribution(action)
+		return err
+	case hasKey(action, "weights"):
+		weights, ok := action["weights"].(map[string]interface{})
+		if !ok || len(weights) == 0 {
+			return fmt.Errorf("'weights' expects a map of values to weights")
+		}
+		return validateWeights(weights)
+	case hasKey(action, "generator"):
+		if _, ok := action["generator"].(string); !ok {
+			return fmt.Errorf("'generator' expects a generator name")
+		}
+		return nil
+	default:
+		return fmt.Errorf("action needs one of 'value', 'min'/'max', 'distribution', 'weights' or 'generator'")
+	}
+}
+
+// ActionDistribution reads the distribution of an action
+func ActionDistribution(action map[string]interface{}) (*DistributionConfig, error) {
+	raw, ok := action["distribution"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'distribution' expects an object")
+	}
+	return ParseDistribution(raw)
+}
+
+func hasKey(m map[string]interface{}, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Temporal keeps a timestamp column after another timestamp, either of the
+// same row or of the parent row a foreign key points at:
+//
+//	"temporal": {"after": "created_at"}
+//	"temporal": {"after": "customers.created_at", "within": "30d"}
+type Temporal struct {
+	After  string `json:"after"`            // column of the same row, or "table.column" of the referenced parent
+	Within string `json:"within,omitempty"` // maximum delay after it, e.g. "30d", "12h", "90m"
+}
+
+// Reference splits After into the parent table (empty for the same row) and column
+func (t *Temporal) Reference() (string, string) {
+	dot := strings.LastIndex(t.After, ".")
+	if dot < 0 {
+		return "", t.After
+	}
+	return t.After[:dot], t.After[dot+1:]
+}
+
+// Window parses Within, returning 0 when it is not set.
+// Besides Go durations ("12h", "90m") it accepts days ("30d").
+func (t *Temporal) Window() (time.Duration, error) {
+	if t.Within == "" {
+		return 0, nil
+	}
+
+	var window time.Duration
+	if days, ok := strings.CutSuffix(t.Within, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid 'within' %q", t.Within)
+		}
+		window = time.Duration(n * float64(24*time.Hour))
+	} else {
+		d, err := time.ParseDuration(t.Within)
+		if err != nil {
+			return 0, fmt.Errorf("invalid 'within' %q", t.Within)
+		}
+		window = d
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("'within' must be positive, got %q", t.Within)
+	}
+	return window, nil
+}
+
+// ForeignKeyTo returns the first foreign key referencing a table, if any
+func (t *Table) ForeignKeyTo(tableName string) *ForeignKey {
+	for _, fk := range t.ForeignKeys {
+		if fk.ReferencedTable == tableName {
+			return fk
+		}
+	}
+	return nil
+}
+
+// Column returns the column with the given name, if any
+func (t *Table) Column(name string) *Column {
+	for _, col := range t.Columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TextGenerator is the generator type that writes text with a realistic
+// structure: words, sentences and paragraphs, a Markov chain trained on a
+// corpus, or domain text
+const TextGenerator = "text"
+
+// TextModes lists the modes of the text generator
+var TextModes = []string{"words", "sentences", "paragraphs", "markov", "product_title", "review", "support_ticket"}
+
+// TextConfig configures the text generator:
+//
+//	{"type": "text", "mode": "words", "count": {"min": 3, "max": 8}}
+//	{"type": "text", "mode": "sentences", "count": 2, "words": {"min": 6, "max": 14}}
+//	{"type": "text", "mode": "paragraphs", "count": {"min": 1, "max": 3}, "sentences": {"min": 3, "max": 6}}
+//	{"type": "text", "mode": "markov", "corpus": "corpus/reviews.txt", "order": 2, "count": {"min": 1, "max": 3}}
+//	{"type": "text", "mode": "review", "sentiment": {"positive": 0.6, "neutral": 0.25, "negative": 0.15}}
+//
+// Count is the number of words, sentences or paragraphs (sentences for
+// markov and review). Text longer than the column allows is cut at a word.
+type TextConfig struct {
+	Mode      string
+	Count     *CountRange        // units of the mode
+	Words     *CountRange        // words per sentence
+	Sentences *CountRange        // sentences per paragraph
+	Corpus    string             // markov: corpus file, relative to the schema file
+	Order     int                // markov: words that predict the next word
+	Sentiment map[string]float64 // review: weights of positive, neutral and negative
+	MaxLength int                // characters, 0 for no limit
+}
+
+// CountRange is an inclusive range of counts, written as a number or as
+// {"min": 1, "max": 3}
+type CountRange struct {
+	Min int
+	Max int
+}
+
+// Text returns the text generator configured on a column, or nil when the
+// column uses another generator. The length limit defaults to the column's.
+func (col *Column) Text() (*TextConfig, error) {
+	genType := col.GeneratorType
+	if genType == "" {
+		genType, _ = col.GeneratorConfig["type"].(string)
+	}
+	if genType != TextGenerator {
+		return nil, nil
+	}
+
+	config := &TextConfig{Mode: "sentences", Order: 2, MaxLength: col.MaxLength()}
+	if mode, ok := col.GeneratorConfig["mode"]; ok {
+		config.Mode, _ = mode.(string)
+		if !slices.Contains(TextModes, config.Mode) {
+			return nil, fmt.Errorf("unknown text mode '%v' (use %s)", mode, strings.Join(TextModes, ", "))
+		}
+	}
+
+	counts := []struct {
+		key   string
+		field **CountRange
+	}{{"count", &config.Count}, {"words", &config.Words}, {"sentences", &config.Sentences}}
+	for _, count := range counts {
+		raw, ok := col.GeneratorConfig[count.key]
+		if !ok {
+			continue
+		}
+		r, err := parseCountRange(raw)
+		if err != nil {
+			return nil, fmt.Errorf("text '%s': %v", count.key, err)
+		}
+		*count.field = r
+	}
+
+	if config.Mode == "markov" {
+		config.Corpus, _ = col.GeneratorConfig["corpus"].(string)
+		if config.Corpus == "" {
+			return nil, fmt.Errorf("markov text needs a 'corpus' file")
+		}
+		if order, ok := col.GeneratorConfig["order"]; ok {
+			f, isNumber := order.(float64)
+			if !isNumber || f != float64(int(f)) || f < 1 || f > 4 {
+				return nil, fmt.Errorf("markov 'order' must be a whole number from 1 to 4")
+			}
+			config.Order = int(f)
+		}
+	}
+
+	if raw, ok := col.GeneratorConfig["sentiment"]; ok {
+		weights, isMap := raw.(map[string]interface{})
+		if config.Mode != "review" || !isMap {
+			return nil, fmt.Errorf("'sentiment' applies to reviews and maps positive, neutral and negative to weights")
+		}
+		config.Sentiment = make(map[string]float64)
+		total := 0.0
+		for _, name := range sortedKeys(weights) {
+			w, isNumber := weights[name].(float64)
+			if !slices.Contains([]string{"positive", "neutral", "negative"}, name) || !isNumber || w < 0 {
+				return nil, fmt.Errorf("'sentiment' maps positive, neutral and negative to weights, got %s: %v", name, weights[name])
+			}
+			config.Sentiment[name] = w
+			total += w
+		}
+		if total <= 0 {
+			return nil, fmt.Errorf("'sentiment' needs a positive weight")
+		}
+	}
+
+	if raw, ok := col.GeneratorConfig["max_length"]; ok {
+		f, isNumber := raw.(float64)
+		if !isNumber || f < 1 || f != float64(int(f)) {
+			return nil, fmt.Errorf("text 'max_length' must be a positive whole number")
+		}
+		if config.MaxLength == 0 || int(f) < config.MaxLength {
+			config.MaxLength = int(f)
+		}
+	}
+	return config, nil
+}
+
+// parseCountRange reads a count written as a number or as {"min", "max"}
+func parseCountRange(raw interface{}) (*CountRange, error) {
+	var min, max float64
+	switch v := raw.(type) {
+	case float64:
+		min, max = v, v
+	case map[string]interface{}:
+		var minOK, maxOK bool
+		min, minOK = v["min"].(float64)
+		max, maxOK = v["max"].(float64)
+		if !minOK || !maxOK {
+			return nil, fmt.Errorf("expects a number or {\"min\": ..., \"max\": ...}")
+		}
+	default:
+		return nil, fmt.Errorf("expects a number or {\"min\": ..., \"max\": ...}")
+	}
+	if min != float64(int(min)) || max != float64(int(max)) || min < 1 || min > max {
+		return nil, fmt.Errorf("counts must be whole numbers from 1, with 'min' up to 'max'")
+	}
+	return &CountRange{Min: int(min), Max: int(max)}, nil
+}
+
+// characterLength matches the length of varchar(n) and char(n) types
+var characterLength = regexp.MustCompile(`^(?:varchar|character varying|char|character)\s*\(\s*(\d+)\s*\)$`)
+
+// MaxLength returns the maximum number of characters of a varchar(n) or
+// char(n) column, or 0 for other types
+func (col *Column) MaxLength() int {
+	m := characterLength.FindStringSubmatch(strings.ToLower(strings.TrimSpace(col.Type)))
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
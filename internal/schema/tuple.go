@@ -0,0 +1,34 @@
+package schema
+
+// TupleRoles lists the roles each multi-column generator fills
+var TupleRoles = map[string][]string{
+	"address":     {"street", "city", "state", "postal_code", "country", "country_code", "latitude", "longitude"},
+	"geo":         {"latitude", "longitude", "city", "state", "country", "country_code"},
+	"person":      {"first_name", "last_name", "full_name", "gender", "email", "username"},
+	"credit_card": {"brand", "number", "expiry", "expiry_date", "cvv"},
+}
+
+// Tuple fills several columns of a row from one generator, so that related
+// values agree (a street, city and postal code of the same country; an email
+// made of the person's name). Columns maps the generator's roles to columns.
+//
+//	"tuples": [
+//	  {"generator": "address", "countries": ["FR", "DE"],
+//	   "columns": {"street": "line1", "city": "city", "postal_code": "zip", "country": "country"}},
+//	  {"generator": "person", "columns": {"first_name": "first_name", "email": "email"}}
+//	]
+type Tuple struct {
+	Generator string            `json:"generator"`
+	Columns   map[string]string `json:"columns"`             // role -> column
+	Countries []string          `json:"countries,omitempty"` // address and geo: ISO country codes to draw from
+}
+
+// HasRole reports whether a tuple generator fills a role
+func HasRole(generator, role string) bool {
+	for _, r := range TupleRoles[generator] {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
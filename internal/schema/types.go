@@ -12,9 +12,11 @@ type Schema struct {
 
 // DatabaseConfig represents database-level configuration
 type DatabaseConfig struct {
-	Name     string `json:"name"`
-	Encoding string `json:"encoding"` // default: "UTF8"
-	Locale   string `json:"locale"`   // default: "en_US.utf8"
+	Name           string `json:"name"`
+	Encoding       string `json:"encoding"`                  // default: "UTF8"
+	Locale         string `json:"locale"`                    // default: "en_US.utf8"
+	CreateDatabase *bool  `json:"create_database,omitempty"` // default: true (CREATE DATABASE + \connect)
+	Schema         string `json:"schema,omitempty"`          // default namespace for tables (default: public)
 }
 
 // Table represents a single database table
@@ -26,11 +28,59 @@ type Table struct {
 	CheckConstraints  []*CheckConstraint  `json:"check_constraints,omitempty"`
 	Indexes           []*Index            `json:"indexes,omitempty"`
 	RowCount          int                 `json:"row_count"`
+	Schema            string              `json:"schema,omitempty"` // namespace, overrides database.schema
+
+	// Junction generates distinct pairs of parent rows for a many-to-many table
+	Junction *Junction `json:"junction,omitempty"`
+
+	// Polymorphic references point a type/id column pair at rows of several tables
+	Polymorphic []*Polymorphic `json:"polymorphic,omitempty"`
+
+	// Correlations draw groups of numeric columns jointly
+	Correlations []*Correlation `json:"correlations,omitempty"`
+
+	// Tuples fill groups of related columns from multi-column generators
+	Tuples []*Tuple `json:"tuples,omitempty"`
 
 	// Computed fields (not in JSON)
 	Dependencies []string `json:"-"`
 }
 
+// HierarchyKey returns the self-referencing foreign key that declares a hierarchy, if any
+func (t *Table) HierarchyKey() *ForeignKey {
+	for _, fk := range t.ForeignKeys {
+		if fk.Hierarchy != nil {
+			return fk
+		}
+	}
+	return nil
+}
+
+// PrimaryKeyColumns returns the table's primary key, falling back to the
+// columns flagged with primary_key
+func (t *Table) PrimaryKeyColumns() []string {
+	if len(t.PrimaryKey) > 0 {
+		return t.PrimaryKey
+	}
+	var pk []string
+	for _, col := range t.Columns {
+		if col.PrimaryKey {
+			pk = append(pk, col.Name)
+		}
+	}
+	return pk
+}
+
+// CardinalityKey returns the foreign key that declares a cardinality, if any
+func (t *Table) CardinalityKey() *ForeignKey {
+	for _, fk := range t.ForeignKeys {
+		if fk.Cardinality != nil {
+			return fk
+		}
+	}
+	return nil
+}
+
 // Column represents a table column
 type Column struct {
 	Name            string                 `json:"name"`
@@ -42,6 +92,19 @@ type Column struct {
 	GeneratorType   string                 `json:"generator,omitempty"`
 	GeneratorConfig map[string]interface{} `json:"generator_config,omitempty"`
 	Comment         string                 `json:"comment,omitempty"`
+
+	// Temporal orders a timestamp column after another timestamp
+	Temporal *Temporal `json:"temporal,omitempty"`
+
+	// Expression derives the value from other columns of the row, e.g. "price * quantity"
+	Expression string `json:"expression,omitempty"`
+
+	// Rules set the value when conditions on the row hold, falling back to the generator
+	Rules []*BusinessRule `json:"rules,omitempty"`
+
+	// Lifecycle walks a status column through a state machine, setting the
+	// timestamp column of each state reached
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty"`
 }
 
 // ForeignKey represents a foreign key constraint
@@ -51,6 +114,109 @@ type ForeignKey struct {
 	ReferencedColumns []string `json:"referenced_columns"`
 	OnDelete          string   `json:"on_delete,omitempty"` // CASCADE, SET NULL, RESTRICT, NO ACTION
 	OnUpdate          string   `json:"on_update,omitempty"`
+
+	// Cardinality shapes how many child rows reference each parent row
+	Cardinality *Cardinality `json:"cardinality,omitempty"`
+
+	// Hierarchy generates a tree through a self-referencing foreign key
+	Hierarchy *Hierarchy `json:"hierarchy,omitempty"`
+
+	// Selection skews which parent rows the foreign key references
+	Selection *Selection `json:"selection,omitempty"`
+
+	// NullRate is the share of rows (0-1) whose foreign key is NULL
+	NullRate float64 `json:"null_rate,omitempty"`
+
+	// Where limits the referenced parent rows to those whose columns have the
+	// given values (or one of a list of values), e.g. {"status": "active"}
+	Where map[string]interface{} `json:"where,omitempty"`
+
+	// CycleBreak lets this foreign key close a cycle between tables:
+	// "update" inserts NULL and sets the value with UPDATE statements after all data,
+	// "deferred" creates the constraint DEFERRABLE INITIALLY DEFERRED and loads in one transaction
+	CycleBreak string `json:"cycle_break,omitempty"`
+}
+
+// Cycle break strategies for foreign keys that close a cycle
+const (
+	CycleBreakUpdate   = "update"
+	CycleBreakDeferred = "deferred"
+)
+
+// Hierarchy shapes the tree built by a self-referencing foreign key.
+// Rows are generated breadth-first, so parents always precede their children.
+type Hierarchy struct {
+	Roots         int          `json:"roots,omitempty"`          // number of root rows (default: 1)
+	MaxDepth      int          `json:"max_depth,omitempty"`      // number of levels, roots included (0 = unlimited)
+	FanOut        *Cardinality `json:"fan_out,omitempty"`        // children per node (default: 1-5 uniform)
+	PathColumn    string       `json:"path_column,omitempty"`    // column filled with the materialized path of keys
+	PathSeparator string       `json:"path_separator,omitempty"` // default: "/" (use "." for ltree)
+	DepthColumn   string       `json:"depth_column,omitempty"`   // column filled with the level (roots are 1)
+}
+
+// Cardinality controls the number of child rows per parent row.
+// When the child table's row_count is omitted it is derived from the
+// per-parent counts; otherwise the counts are scaled to match it.
+type Cardinality struct {
+	Min          int      `json:"min"`
+	Max          int      `json:"max"`
+	Distribution string   `json:"distribution,omitempty"` // uniform (default), zipf, normal
+	Alpha        *float64 `json:"alpha,omitempty"`        // zipf exponent, must be > 1 (default: 1.5)
+	EmptyRatio   float64  `json:"empty_ratio,omitempty"`  // share of parents with no children (0-1)
+}
+
+// Selection controls how often each parent row is referenced.
+// Skewed distributions favour the first parent rows.
+type Selection struct {
+	Distribution string   `json:"distribution,omitempty"` // uniform (default), zipf, pareto, hotspot
+	Alpha        *float64 `json:"alpha,omitempty"`        // zipf exponent (default: 1.5) or pareto index (default: 1.16, i.e. 80/20); must be > 1
+	Top          float64  `json:"top,omitempty"`          // hotspot: share of parent rows that are hot (0-1)
+	Share        float64  `json:"share,omitempty"`        // hotspot: share of references they receive (0-1)
+}
+
+// Junction pairs the rows of the two tables a many-to-many table references.
+// Every (left, right) pair is generated at most once; a row_count larger than
+// the number of achievable pairs is reduced to it, and an omitted row_count is
+// derived from the left side's degrees.
+type Junction struct {
+	Left  JunctionSide `json:"left"`
+	Right JunctionSide `json:"right"`
+}
+
+// JunctionSide identifies one foreign key of a junction table and how many
+// rows of the other side each of its parent rows is linked to
+type JunctionSide struct {
+	Columns []string     `json:"columns"`          // columns of the foreign key
+	Degree  *Cardinality `json:"degree,omitempty"` // links per parent row (default: 0 to all rows of the other side)
+}
+
+// JunctionKeys returns the foreign keys of the junction's left and right sides
+func (t *Table) JunctionKeys() (left, right *ForeignKey) {
+	if t.Junction == nil {
+		return nil, nil
+	}
+	for _, fk := range t.ForeignKeys {
+		if sameColumns(fk.Columns, t.Junction.Left.Columns) {
+			left = fk
+		}
+		if sameColumns(fk.Columns, t.Junction.Right.Columns) {
+			right = fk
+		}
+	}
+	return left, right
+}
+
+// sameColumns reports whether two column lists are equal
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // UniqueConstraint represents a unique constraint
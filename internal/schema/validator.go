@@ -2,13 +2,22 @@ package schema
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/expression"
 )
 
 // Validate checks the schema for common errors and returns a list of validation errors
 func Validate(s *Schema) []error {
 	var errs []error
 
+	// Validate default namespace
+	if strings.Contains(s.Database.Schema, ".") {
+		errs = append(errs, fmt.Errorf("database: schema %q cannot contain '.'\n  → Suggestion: Use a plain PostgreSQL schema name (e.g., 'sales')", s.Database.Schema))
+	}
+
 	// Validate each table
 	for tableName, table := range s.Tables {
 		errs = append(errs, validateTable(tableName, table, s)...)
@@ -23,11 +32,18 @@ func Validate(s *Schema) []error {
 func validateTable(name string, t *Table, s *Schema) []error {
 	var errs []error
 
-	// Validate row count
-	if t.RowCount <= 0 {
+	// Validate row count (derived from the cardinality of a foreign key when omitted)
+	if t.RowCount < 0 || (t.RowCount == 0 && t.CardinalityKey() == nil && t.Junction == nil) {
 		errs = append(errs, fmt.Errorf("table %s: row_count must be greater than 0, got %d\n  → Suggestion: Set 'row_count' to a positive integer (e.g., 100)", name, t.RowCount))
 	}
 
+	// Validate namespace
+	if strings.Contains(t.Schema, ".") {
+		errs = append(errs, fmt.Errorf("table %s: schema %q cannot contain '.'\n  → Suggestion: Use a plain PostgreSQL schema name (e.g., 'sales')", name, t.Schema))
+	} else if i := strings.Index(name, "."); i > 0 && t.Schema != "" && name[:i] != t.Schema {
+		errs = append(errs, fmt.Errorf("table %s: schema %q conflicts with the namespace %q of the table key\n  → Suggestion: Set the namespace either in the table key or in 'schema', not both", name, t.Schema, name[:i]))
+	}
+
 	// Validate columns
 	if len(t.Columns) == 0 {
 		errs = append(errs, fmt.Errorf("table %s: must have at least one column\n  → Suggestion: Add column definitions in the 'columns' array", name))
@@ -41,8 +57,71 @@ func validateTable(name string, t *Table, s *Schema) []error {
 	}
 
 	// Validate foreign keys
+	cardinalityKeys := 0
 	for _, fk := range t.ForeignKeys {
 		errs = append(errs, validateForeignKey(name, fk, s, columnNames)...)
+		if fk.Cardinality != nil {
+			cardinalityKeys++
+			errs = append(errs, validateCardinality(name, t, fk, s)...)
+		}
+		if fk.Hierarchy != nil {
+			errs = append(errs, validateHierarchy(name, t, fk, columnNames)...)
+		}
+		if fk.CycleBreak != "" {
+			errs = append(errs, validateCycleBreak(name, t, fk)...)
+		}
+		if fk.Selection != nil {
+			errs = append(errs, validateSelection(name, t, fk)...)
+		}
+		if fk.NullRate != 0 || fk.Where != nil {
+			errs = append(errs, validateParentSubset(name, t, fk, s)...)
+		}
+	}
+	if cardinalityKeys > 1 {
+		errs = append(errs, fmt.Errorf("table %s: only one foreign key can declare a cardinality, got %d\n  → Suggestion: Keep 'cardinality' on the foreign key that drives the number of rows", name, cardinalityKeys))
+	}
+	if t.Junction != nil {
+		errs = append(errs, validateJunction(name, t, cardinalityKeys)...)
+	}
+	for _, poly := range t.Polymorphic {
+		errs = append(errs, validatePolymorphic(name, t, poly, s, columnNames)...)
+	}
+	if len(t.Correlations) > 0 {
+		errs = append(errs, validateCorrelations(name, t)...)
+	}
+	if len(t.Tuples) > 0 {
+		errs = append(errs, validateTuples(name, t)...)
+	}
+	errs = append(errs, validateDictionaries(name, t)...)
+
+	// Validate lookups from parent rows, aggregates over child rows, distributions,
+	// text, temporal constraints, expressions, business rules, conditional weights
+	// and lifecycles
+	for _, col := range t.Columns {
+		errs = append(errs, validateLookup(name, t, col, s)...)
+		errs = append(errs, validateAggregate(name, t, col, s)...)
+		if _, err := col.Distribution(); err != nil {
+			errs = append(errs, fmt.Errorf("table %s, column %s: %v\n  → Suggestion: Use e.g. {\"type\": \"distribution\", \"distribution\": {\"type\": \"normal\", \"mean\": 100, \"std_dev\": 15}}", name, col.Name, err))
+		}
+		if _, err := col.Text(); err != nil {
+			errs = append(errs, fmt.Errorf("table %s, column %s: %v\n  → Suggestion: Use e.g. {\"type\": \"text\", \"mode\": \"paragraphs\", \"count\": {\"min\": 1, \"max\": 3}}", name, col.Name, err))
+		}
+		if col.Temporal != nil {
+			errs = append(errs, validateTemporal(name, t, col, s)...)
+		}
+		if col.Expression != "" {
+			errs = append(errs, validateExpression(name, col, columnNames)...)
+		}
+		if len(col.Rules) > 0 {
+			errs = append(errs, validateRules(name, t, col, s)...)
+		}
+		errs = append(errs, validateConditional(name, t, col, s)...)
+		if col.Lifecycle != nil {
+			errs = append(errs, validateLifecycle(name, t, col, s)...)
+		}
+		if rowDependencyCycle(t, col) {
+			errs = append(errs, fmt.Errorf("table %s, column %s: expressions, rules, conditional weights, lifecycles and temporal constraints form a cycle\n  → Suggestion: Make sure no column depends on itself through other columns", name, col.Name))
+		}
 	}
 
 	// Validate unique constraints
@@ -150,18 +229,644 @@ func validateConstraint(tableName, constraintType string, columns []string, colu
 	return errs
 }
 
+func validateCardinality(tableName string, t *Table, fk *ForeignKey, s *Schema) []error {
+	var errs []error
+	c := fk.Cardinality
+
+	if fk.ReferencedTable == tableName {
+		errs = append(errs, fmt.Errorf("table %s: cardinality is not supported on self-referencing foreign keys", tableName))
+	}
+	errs = append(errs, validateCounts(fmt.Sprintf("table %s: foreign key %v: cardinality", tableName, fk.Columns), c)...)
+
+	// A fixed row count must be reachable with every parent's children
+	// between min and max (or none, with an empty ratio)
+	parent := s.Tables[fk.ReferencedTable]
+	if t.RowCount > 0 && parent != nil && parent.RowCount > 0 && fk.ReferencedTable != tableName && c.Min >= 0 && c.Max >= c.Min {
+		low, high := parent.RowCount*c.Min, parent.RowCount*c.Max
+		if c.EmptyRatio > 0 {
+			low = 0
+		}
+		if t.RowCount < low || t.RowCount > high {
+			errs = append(errs, fmt.Errorf("table %s: row_count %d cannot be met by %d %s rows with %d to %d children each, which allows %d to %d rows\n  → Suggestion: Omit 'row_count' to derive it from the cardinality, or widen 'min' and 'max'", tableName, t.RowCount, parent.RowCount, fk.ReferencedTable, c.Min, c.Max, low, high))
+		}
+	}
+
+	return errs
+}
+
+// validateCounts checks the bounds and distribution of a number of children
+// or links per parent: a cardinality, hierarchy fan_out or junction degree.
+// The prefix names it in errors.
+func validateCounts(prefix string, c *Cardinality) []error {
+	var errs []error
+	if c.Min < 0 || c.Max < c.Min {
+		errs = append(errs, fmt.Errorf("%s needs 0 <= min <= max, got min=%d max=%d\n  → Suggestion: Set e.g. \"min\": 1, \"max\": 5", prefix, c.Min, c.Max))
+	}
+	if c.EmptyRatio < 0 || c.EmptyRatio > 1 {
+		errs = append(errs, fmt.Errorf("%s empty_ratio must be between 0 and 1, got %g", prefix, c.EmptyRatio))
+	}
+	switch c.Distribution {
+	case "", "uniform", "normal":
+	case "zipf":
+		if c.Alpha != nil && *c.Alpha <= 1 {
+			errs = append(errs, fmt.Errorf("%s zipf alpha must be greater than 1, got %g", prefix, *c.Alpha))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s: unknown distribution '%s'\n  → Suggestion: Use one of: uniform, zipf, normal", prefix, c.Distribution))
+	}
+	return errs
+}
+
+func validateSelection(tableName string, t *Table, fk *ForeignKey) []error {
+	var errs []error
+	sel := fk.Selection
+
+	if fk.ReferencedTable == tableName {
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: selection is not supported on self-referencing foreign keys", tableName, fk.Columns))
+	}
+	if fk.Cardinality != nil {
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: selection cannot be combined with cardinality\n  → Suggestion: Use the cardinality's 'distribution' to skew children per parent", tableName, fk.Columns))
+	}
+	if left, right := t.JunctionKeys(); fk == left || fk == right {
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: selection is not supported on junction sides\n  → Suggestion: Use the junction's 'degree' instead", tableName, fk.Columns))
+	}
+
+	switch sel.Distribution {
+	case "", "uniform":
+	case "zipf", "pareto":
+		if sel.Alpha != nil && *sel.Alpha <= 1 {
+			errs = append(errs, fmt.Errorf("table %s: foreign key %v: %s alpha must be greater than 1, got %g", tableName, fk.Columns, sel.Distribution, *sel.Alpha))
+		}
+	case "hotspot":
+		if sel.Top <= 0 || sel.Top >= 1 {
+			errs = append(errs, fmt.Errorf("table %s: foreign key %v: hotspot top must be between 0 and 1 (exclusive), got %g\n  → Suggestion: Set e.g. \"top\": 0.01, \"share\": 0.5 for 1%% of rows getting 50%% of references", tableName, fk.Columns, sel.Top))
+		}
+		if sel.Share < 0 || sel.Share > 1 {
+			errs = append(errs, fmt.Errorf("table %s: foreign key %v: hotspot share must be between 0 and 1, got %g", tableName, fk.Columns, sel.Share))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: unknown selection distribution '%s'\n  → Suggestion: Use one of: uniform, zipf, pareto, hotspot", tableName, fk.Columns, sel.Distribution))
+	}
+
+	return errs
+}
+
+func validateParentSubset(tableName string, t *Table, fk *ForeignKey, s *Schema) []error {
+	var errs []error
+
+	if fk.ReferencedTable == tableName || fk.Cardinality != nil {
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: null_rate and where are not supported with cardinality or on self-referencing foreign keys", tableName, fk.Columns))
+	}
+	if left, right := t.JunctionKeys(); fk == left || fk == right {
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: null_rate and where are not supported on junction sides", tableName, fk.Columns))
+	}
+
+	if fk.NullRate < 0 || fk.NullRate > 1 {
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: null_rate must be between 0 and 1, got %g", tableName, fk.Columns, fk.NullRate))
+	} else if fk.NullRate > 0 {
+		for _, name := range fk.Columns {
+			if col := t.Column(name); col != nil && !col.Nullable {
+				errs = append(errs, fmt.Errorf("table %s: foreign key %v: null_rate needs nullable columns, '%s' is NOT NULL\n  → Suggestion: Set \"nullable\": true on '%s'", tableName, fk.Columns, name, name))
+			}
+		}
+	}
+
+	if parent, exists := s.Tables[fk.ReferencedTable]; exists {
+		columns := make([]string, 0, len(fk.Where))
+		for column := range fk.Where {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if parent.Column(column) == nil {
+				errs = append(errs, fmt.Errorf("table %s: foreign key %v: where column '%s' does not exist in table '%s'", tableName, fk.Columns, column, fk.ReferencedTable))
+			}
+		}
+	}
+	return errs
+}
+
+func validateHierarchy(tableName string, t *Table, fk *ForeignKey, columnNames map[string]bool) []error {
+	var errs []error
+	h := fk.Hierarchy
+
+	if fk.ReferencedTable != tableName {
+		errs = append(errs, fmt.Errorf("table %s: hierarchy requires a self-referencing foreign key, got a reference to '%s'", tableName, fk.ReferencedTable))
+	}
+	for _, col := range t.Columns {
+		for _, fkCol := range fk.Columns {
+			if col.Name == fkCol && !col.Nullable {
+				errs = append(errs, fmt.Errorf("table %s: hierarchy column '%s' must be nullable\n  → Suggestion: Set \"nullable\": true so root rows can have no parent", tableName, fkCol))
+			}
+		}
+	}
+	if h.Roots < 0 || h.MaxDepth < 0 {
+		errs = append(errs, fmt.Errorf("table %s: hierarchy roots and max_depth cannot be negative", tableName))
+	}
+	if h.FanOut != nil {
+		errs = append(errs, validateCounts(fmt.Sprintf("table %s: hierarchy fan_out", tableName), h.FanOut)...)
+	}
+	for _, col := range []string{h.PathColumn, h.DepthColumn} {
+		if col != "" && !columnNames[col] {
+			errs = append(errs, fmt.Errorf("table %s: hierarchy column '%s' does not exist\n  → Suggestion: Add column '%s' to table '%s'", tableName, col, col, tableName))
+		}
+	}
+
+	return errs
+}
+
+func validateJunction(tableName string, t *Table, cardinalityKeys int) []error {
+	var errs []error
+
+	left, right := t.JunctionKeys()
+	for _, side := range []struct {
+		name string
+		def  JunctionSide
+		fk   *ForeignKey
+	}{{"left", t.Junction.Left, left}, {"right", t.Junction.Right, right}} {
+		if side.fk == nil {
+			errs = append(errs, fmt.Errorf("table %s: junction %s side %v does not match a foreign key\n  → Suggestion: Use the 'columns' of one of the table's foreign keys", tableName, side.name, side.def.Columns))
+			continue
+		}
+		if side.fk.ReferencedTable == tableName {
+			errs = append(errs, fmt.Errorf("table %s: junction %s side cannot reference the junction table itself", tableName, side.name))
+		}
+		if side.def.Degree != nil {
+			errs = append(errs, validateCounts(fmt.Sprintf("table %s: junction %s degree", tableName, side.name), side.def.Degree)...)
+		}
+	}
+	if left != nil && left == right {
+		errs = append(errs, fmt.Errorf("table %s: junction left and right sides must use different foreign keys", tableName))
+	}
+	if cardinalityKeys > 0 {
+		errs = append(errs, fmt.Errorf("table %s: cardinality cannot be combined with a junction\n  → Suggestion: Use the junction's 'degree' settings instead", tableName))
+	}
+
+	return errs
+}
+
+func validatePolymorphic(tableName string, t *Table, poly *Polymorphic, s *Schema, columnNames map[string]bool) []error {
+	var errs []error
+	for _, col := range []string{poly.TypeColumn, poly.IDColumn} {
+		if !columnNames[col] {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic column '%s' does not exist", tableName, col))
+		} else if t.ForeignKeyFor(col) != nil {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic column '%s' is also a foreign key column", tableName, col))
+		}
+	}
+	if poly.TypeColumn == poly.IDColumn {
+		errs = append(errs, fmt.Errorf("table %s: polymorphic type_column and id_column must differ", tableName))
+	}
+	if len(poly.Targets) == 0 {
+		errs = append(errs, fmt.Errorf("table %s: polymorphic reference '%s' needs at least one target\n  → Suggestion: Add e.g. \"targets\": [{\"table\": \"posts\"}, {\"table\": \"photos\"}]", tableName, poly.IDColumn))
+	}
+
+	values := make(map[string]bool)
+	for _, target := range poly.Targets {
+		if values[target.TypeValue()] {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic reference '%s' lists type '%s' twice", tableName, poly.IDColumn, target.TypeValue()))
+		}
+		values[target.TypeValue()] = true
+
+		if target.Weight != nil && *target.Weight <= 0 {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic target '%s' weight must be positive, got %g", tableName, target.Table, *target.Weight))
+		}
+		if target.Table == tableName {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic target '%s' cannot be the table itself", tableName, target.Table))
+			continue
+		}
+		parent, exists := s.Tables[target.Table]
+		if !exists {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic target table '%s' does not exist", tableName, target.Table))
+			continue
+		}
+		column := target.ReferencedColumn(s)
+		if column == "" {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic target '%s' has no single-column primary key\n  → Suggestion: Set the target's 'column'", tableName, target.Table))
+		} else if parent.Column(column) == nil {
+			errs = append(errs, fmt.Errorf("table %s: polymorphic target column '%s' does not exist in table '%s'", tableName, column, target.Table))
+		}
+	}
+	return errs
+}
+
+func validateCorrelations(tableName string, t *Table) []error {
+	var errs []error
+	grouped := make(map[string]bool)
+	for i, corr := range t.Correlations {
+		prefix := fmt.Sprintf("table %s, correlation %d", tableName, i+1)
+		if len(corr.Columns) < 2 {
+			errs = append(errs, fmt.Errorf("%s: needs at least two columns\n  → Suggestion: List the columns to correlate, e.g. \"columns\": [\"height\", \"weight\"]", prefix))
+		}
+
+		for _, name := range corr.Columns {
+			col := t.Column(name)
+			switch {
+			case col == nil:
+				errs = append(errs, fmt.Errorf("%s: column '%s' does not exist", prefix, name))
+			case grouped[name]:
+				errs = append(errs, fmt.Errorf("%s: column '%s' is already in a correlation", prefix, name))
+			case !isNumericType(col.Type):
+				errs = append(errs, fmt.Errorf("%s: column '%s' has type %s, only numeric columns can be correlated", prefix, name, col.Type))
+			case t.ForeignKeyFor(name) != nil:
+				errs = append(errs, fmt.Errorf("%s: column '%s' is a foreign key column", prefix, name))
+			case col.Expression != "" || len(col.Rules) > 0 || col.GeneratorType != "" || col.GeneratorConfig != nil:
+				errs = append(errs, fmt.Errorf("%s: column '%s' cannot also have a generator, expression or rules", prefix, name))
+			}
+			grouped[name] = true
+
+			marginal := corr.Marginals[name]
+			if marginal == nil {
+				errs = append(errs, fmt.Errorf("%s: column '%s' has no marginal distribution\n  → Suggestion: Add e.g. \"%s\": {\"type\": \"normal\", \"mean\": 50, \"std_dev\": 10} to 'marginals'", prefix, name, name))
+			} else if err := ValidateMarginal(marginal); err != nil {
+				errs = append(errs, fmt.Errorf("%s: column '%s': %v", prefix, name, err))
+			}
+		}
+		for _, name := range sortedKeys(corr.Marginals) {
+			if !slices.Contains(corr.Columns, name) {
+				errs = append(errs, fmt.Errorf("%s: marginal for '%s', which is not one of its columns", prefix, name))
+			}
+		}
+
+		if _, err := corr.Cholesky(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid matrix: %v", prefix, err))
+		}
+	}
+	return errs
+}
+
+func validateTuples(tableName string, t *Table) []error {
+	var errs []error
+	filled := make(map[string]bool)
+	for _, corr := range t.Correlations {
+		for _, name := range corr.Columns {
+			filled[name] = true
+		}
+	}
+
+	for i, tuple := range t.Tuples {
+		prefix := fmt.Sprintf("table %s, tuple %d (%s)", tableName, i+1, tuple.Generator)
+		roles, known := TupleRoles[tuple.Generator]
+		if !known {
+			errs = append(errs, fmt.Errorf("table %s, tuple %d: unknown generator '%s' (use %s)", tableName, i+1, tuple.Generator, strings.Join(sortedKeys(TupleRoles), ", ")))
+			continue
+		}
+		if len(tuple.Columns) == 0 {
+			errs = append(errs, fmt.Errorf("%s: needs 'columns' mapping its roles to columns\n  → Suggestion: Map some of %s to columns", prefix, strings.Join(roles, ", ")))
+		}
+		if len(tuple.Countries) > 0 && tuple.Generator != "address" && tuple.Generator != "geo" {
+			errs = append(errs, fmt.Errorf("%s: 'countries' only applies to address and geo", prefix))
+		}
+
+		for _, role := range sortedKeys(tuple.Columns) {
+			name := tuple.Columns[role]
+			if !HasRole(tuple.Generator, role) {
+				errs = append(errs, fmt.Errorf("%s: unknown role '%s' (use %s)", prefix, role, strings.Join(roles, ", ")))
+				continue
+			}
+			col := t.Column(name)
+			switch {
+			case col == nil:
+				errs = append(errs, fmt.Errorf("%s: column '%s' does not exist", prefix, name))
+			case filled[name]:
+				errs = append(errs, fmt.Errorf("%s: column '%s' is already filled by a tuple or correlation", prefix, name))
+			case t.ForeignKeyFor(name) != nil:
+				errs = append(errs, fmt.Errorf("%s: column '%s' is a foreign key column", prefix, name))
+			case col.Expression != "" || len(col.Rules) > 0 || col.GeneratorType != "" || col.GeneratorConfig != nil || t.LifecycleOwner(name) != nil:
+				errs = append(errs, fmt.Errorf("%s: column '%s' cannot also have a generator, expression, rules or lifecycle", prefix, name))
+			}
+			filled[name] = true
+		}
+	}
+	return errs
+}
+
+// validateDictionaries checks the dictionary columns of a table, and that
+// the columns sharing a draw sample its rows the same way
+func validateDictionaries(tableName string, t *Table) []error {
+	var errs []error
+	var draws []*Dictionary
+	for _, col := range t.Columns {
+		dict, err := col.Dictionary()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("table %s, column %s: %v\n  → Suggestion: Use e.g. {\"type\": \"dictionary\", \"file\": \"data/airports.csv\", \"column\": \"iata\", \"weight\": \"passengers\"}", tableName, col.Name, err))
+			continue
+		}
+		if dict == nil {
+			continue
+		}
+
+		for _, other := range draws {
+			if dict.SameDraw(other) && (dict.Weight != other.Weight || dict.Replacement != other.Replacement) {
+				errs = append(errs, fmt.Errorf("table %s, column %s: columns reading the same row of %s must agree on 'weight' and 'replacement'\n  → Suggestion: Set the same 'weight' and 'replacement' on each, or give the column another 'draw'", tableName, col.Name, dict.File))
+				break
+			}
+		}
+		draws = append(draws, dict)
+	}
+	return errs
+}
+
+func validateLookup(tableName string, t *Table, col *Column, s *Schema) []error {
+	lookup, err := col.Lookup()
+	if err != nil {
+		return []error{fmt.Errorf("table %s, column %s: %v\n  → Suggestion: Use e.g. {\"type\": \"lookup\", \"from\": \"products.price\", \"via\": \"product_id\"}", tableName, col.Name, err)}
+	}
+	if lookup == nil {
+		return nil
+	}
+
+	fk := t.ForeignKeyFor(lookup.Via)
+	if fk == nil {
+		return []error{fmt.Errorf("table %s, column %s: lookup via '%s' is not a foreign key column", tableName, col.Name, lookup.Via)}
+	}
+	if fk.ReferencedTable != lookup.Table {
+		return []error{fmt.Errorf("table %s, column %s: lookup reads from '%s' but '%s' references '%s'", tableName, col.Name, lookup.Table, lookup.Via, fk.ReferencedTable)}
+	}
+	if fk.CycleBreak == CycleBreakUpdate {
+		return []error{fmt.Errorf("table %s, column %s: lookup via '%s' is not possible, its parent is only set after loading (cycle_break update)", tableName, col.Name, lookup.Via)}
+	}
+
+	parent, exists := s.Tables[lookup.Table]
+	if !exists {
+		return nil // reported with the foreign key
+	}
+	for _, parentCol := range parent.Columns {
+		if parentCol.Name == lookup.Column {
+			return nil
+		}
+	}
+	return []error{fmt.Errorf("table %s, column %s: lookup column '%s' does not exist in table '%s'", tableName, col.Name, lookup.Column, lookup.Table)}
+}
+
+func validateAggregate(tableName string, t *Table, col *Column, s *Schema) []error {
+	agg, err := col.Aggregate()
+	if err != nil {
+		return []error{fmt.Errorf("table %s, column %s: %v", tableName, col.Name, err)}
+	}
+	if agg == nil {
+		return nil
+	}
+
+	var errs []error
+	if len(t.PrimaryKeyColumns()) == 0 {
+		errs = append(errs, fmt.Errorf("table %s, column %s: aggregates are set with UPDATE statements and need a primary key", tableName, col.Name))
+	}
+
+	child, exists := s.Tables[agg.Table]
+	if !exists {
+		return append(errs, fmt.Errorf("table %s, column %s: aggregate table '%s' does not exist", tableName, col.Name, agg.Table))
+	}
+	if fk := child.ForeignKeyFor(agg.Via); fk == nil || fk.ReferencedTable != tableName {
+		errs = append(errs, fmt.Errorf("table %s, column %s: '%s.%s' is not a foreign key referencing '%s'", tableName, col.Name, agg.Table, agg.Via, tableName))
+	}
+
+	childColumns := make(map[string]*Column, len(child.Columns))
+	for _, childCol := range child.Columns {
+		childColumns[childCol.Name] = childCol
+	}
+	for _, name := range agg.Columns() {
+		childCol, exists := childColumns[name]
+		if !exists {
+			errs = append(errs, fmt.Errorf("table %s, column %s: aggregate column '%s' does not exist in table '%s'", tableName, col.Name, name, agg.Table))
+			continue
+		}
+		if nested, _ := childCol.Aggregate(); nested != nil {
+			errs = append(errs, fmt.Errorf("table %s, column %s: aggregate over aggregate column '%s.%s' is not supported", tableName, col.Name, agg.Table, name))
+		}
+	}
+
+	return errs
+}
+
+func validateTemporal(tableName string, t *Table, col *Column, s *Schema) []error {
+	var errs []error
+	if _, err := col.Temporal.Window(); err != nil {
+		errs = append(errs, fmt.Errorf("table %s, column %s: %v\n  → Suggestion: Use a duration such as \"30d\", \"12h\" or \"90m\"", tableName, col.Name, err))
+	}
+
+	refTable, refColumn := col.Temporal.Reference()
+	if refColumn == "" {
+		return append(errs, fmt.Errorf("table %s, column %s: temporal 'after' must name a column or 'table.column'", tableName, col.Name))
+	}
+
+	if refTable == "" {
+		ref := t.Column(refColumn)
+		if ref == nil {
+			errs = append(errs, fmt.Errorf("table %s, column %s: temporal column '%s' does not exist", tableName, col.Name, refColumn))
+		}
+		return errs
+	}
+
+	fk := t.ForeignKeyTo(refTable)
+	if fk == nil {
+		return append(errs, fmt.Errorf("table %s, column %s: temporal reference '%s' needs a foreign key to '%s'", tableName, col.Name, col.Temporal.After, refTable))
+	}
+	if fk.CycleBreak == CycleBreakUpdate {
+		return append(errs, fmt.Errorf("table %s, column %s: temporal reference '%s' is not possible, its parent is only set after loading (cycle_break update)", tableName, col.Name, col.Temporal.After))
+	}
+	if parent, exists := s.Tables[refTable]; exists && parent.Column(refColumn) == nil {
+		errs = append(errs, fmt.Errorf("table %s, column %s: temporal column '%s' does not exist in table '%s'", tableName, col.Name, refColumn, refTable))
+	}
+	return errs
+}
+
+func validateExpression(tableName string, col *Column, columnNames map[string]bool) []error {
+	expr, err := expression.Parse(col.Expression)
+	if err != nil {
+		return []error{fmt.Errorf("table %s, column %s: invalid expression: %v", tableName, col.Name, err)}
+	}
+
+	var errs []error
+	if col.GeneratorType != "" || col.GeneratorConfig != nil {
+		errs = append(errs, fmt.Errorf("table %s, column %s: expression cannot be combined with a generator", tableName, col.Name))
+	}
+	for _, name := range expr.Columns() {
+		if !columnNames[name] {
+			errs = append(errs, fmt.Errorf("table %s, column %s: expression column '%s' does not exist", tableName, col.Name, name))
+		}
+	}
+	return errs
+}
+
+func validateRules(tableName string, t *Table, col *Column, s *Schema) []error {
+	var errs []error
+	if col.Expression != "" {
+		errs = append(errs, fmt.Errorf("table %s, column %s: rules cannot be combined with an expression", tableName, col.Name))
+	}
+
+	for i, rule := range col.Rules {
+		prefix := fmt.Sprintf("table %s, column %s, rule %d", tableName, col.Name, i+1)
+		if rule.Else != nil && i < len(col.Rules)-1 {
+			errs = append(errs, fmt.Errorf("%s: has an 'else', so the rules after it never apply\n  → Suggestion: Move the 'else' to the last rule", prefix))
+		}
+		if err := ValidateAction(rule.Then); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid 'then': %v", prefix, err))
+		}
+		if rule.Else != nil {
+			if err := ValidateAction(rule.Else); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid 'else': %v", prefix, err))
+			}
+		}
+
+		cond, err := ParseCondition(rule.Condition)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid condition: %v", prefix, err))
+			continue
+		}
+		for _, field := range cond.Columns() {
+			if err := validateRowReference(t, s, field); err != nil {
+				errs = append(errs, fmt.Errorf("%s: condition column %v", prefix, err))
+			}
+		}
+	}
+	return errs
+}
+
+func validateConditional(tableName string, t *Table, col *Column, s *Schema) []error {
+	cond, err := col.Conditional()
+	if err != nil {
+		return []error{fmt.Errorf("table %s, column %s: %v", tableName, col.Name, err)}
+	}
+	if cond == nil {
+		return nil
+	}
+
+	var errs []error
+	if col.Expression != "" || len(col.Rules) > 0 {
+		errs = append(errs, fmt.Errorf("table %s, column %s: conditional weights cannot be combined with an expression or rules", tableName, col.Name))
+	}
+	for _, field := range cond.Given {
+		if err := validateRowReference(t, s, field); err != nil {
+			errs = append(errs, fmt.Errorf("table %s, column %s: given column %v", tableName, col.Name, err))
+		}
+	}
+	return errs
+}
+
+func validateLifecycle(tableName string, t *Table, col *Column, s *Schema) []error {
+	prefix := fmt.Sprintf("table %s, column %s", tableName, col.Name)
+	var errs []error
+	for _, err := range col.Lifecycle.Validate() {
+		errs = append(errs, fmt.Errorf("%s: %v", prefix, err))
+	}
+	if col.Expression != "" || len(col.Rules) > 0 || col.GeneratorType != "" || col.GeneratorConfig != nil {
+		errs = append(errs, fmt.Errorf("%s: lifecycle cannot be combined with a generator, expression or rules", prefix))
+	}
+	if col.Lifecycle.Start != "" {
+		if err := validateRowReference(t, s, col.Lifecycle.Start); err != nil {
+			errs = append(errs, fmt.Errorf("%s: lifecycle start %v", prefix, err))
+		} else if owner := t.LifecycleOwner(col.Lifecycle.Start); owner != nil {
+			errs = append(errs, fmt.Errorf("%s: lifecycle start '%s' is itself filled by a lifecycle", prefix, col.Lifecycle.Start))
+		}
+	}
+
+	timestamps := make(map[string]bool)
+	for _, name := range sortedKeys(col.Lifecycle.States) {
+		state := col.Lifecycle.States[name]
+		if state == nil || state.Timestamp == "" {
+			continue
+		}
+		ts := t.Column(state.Timestamp)
+		switch {
+		case ts == nil:
+			errs = append(errs, fmt.Errorf("%s: lifecycle state '%s' timestamp column '%s' does not exist", prefix, name, state.Timestamp))
+		case timestamps[ts.Name] || t.LifecycleOwner(ts.Name) != col || ts == col:
+			errs = append(errs, fmt.Errorf("%s: lifecycle state '%s' timestamp column '%s' is already filled by another state or lifecycle", prefix, name, ts.Name))
+		case ts.Expression != "" || len(ts.Rules) > 0 || ts.GeneratorType != "" || ts.GeneratorConfig != nil || ts.Temporal != nil:
+			errs = append(errs, fmt.Errorf("%s: lifecycle state '%s' timestamp column '%s' cannot also have a generator, expression, rules or temporal constraint", prefix, name, ts.Name))
+		case !ts.Nullable && name != col.Lifecycle.Initial:
+			errs = append(errs, fmt.Errorf("%s: lifecycle state '%s' timestamp column '%s' must be nullable, rows that never reach the state leave it NULL", prefix, name, ts.Name))
+		}
+		timestamps[state.Timestamp] = true
+	}
+	return errs
+}
+
+// validateRowReference checks a column of the row, or "table.column" of the
+// parent row a foreign key points at, that a generator reads
+func validateRowReference(t *Table, s *Schema, field string) error {
+	refTable, refColumn := ConditionReference(field)
+	if refTable == "" {
+		if t.Column(refColumn) == nil {
+			return fmt.Errorf("'%s' does not exist", refColumn)
+		}
+		return nil
+	}
+
+	fk := t.ForeignKeyTo(refTable)
+	switch {
+	case fk == nil:
+		return fmt.Errorf("'%s' needs a foreign key to '%s'", field, refTable)
+	case fk.CycleBreak == CycleBreakUpdate:
+		return fmt.Errorf("'%s' is not possible, its parent is only set after loading (cycle_break update)", field)
+	}
+	if parent, exists := s.Tables[refTable]; exists && parent.Column(refColumn) == nil {
+		return fmt.Errorf("'%s' does not exist in table '%s'", refColumn, refTable)
+	}
+	return nil
+}
+
+// rowDependencyCycle reports whether a column depends on itself through the
+// expressions, rules and temporal constraints of its row
+func rowDependencyCycle(t *Table, col *Column) bool {
+	seen := make(map[string]bool)
+	var visit func(c *Column) bool
+	visit = func(c *Column) bool {
+		for _, dep := range c.RowDependencies() {
+			if dep == col.Name {
+				return true
+			}
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if next := t.Column(dep); next != nil && visit(next) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(col)
+}
+
+func validateCycleBreak(tableName string, t *Table, fk *ForeignKey) []error {
+	var errs []error
+
+	switch fk.CycleBreak {
+	case CycleBreakUpdate:
+		for _, col := range t.Columns {
+			for _, fkCol := range fk.Columns {
+				if col.Name == fkCol && !col.Nullable {
+					errs = append(errs, fmt.Errorf("table %s: column '%s' must be nullable to break a cycle with UPDATE statements\n  → Suggestion: Set \"nullable\": true or use \"cycle_break\": \"deferred\"", tableName, fkCol))
+				}
+			}
+		}
+		if len(t.PrimaryKeyColumns()) == 0 {
+			errs = append(errs, fmt.Errorf("table %s: a primary key is required to break a cycle with UPDATE statements", tableName))
+		}
+		if fk.Cardinality != nil {
+			errs = append(errs, fmt.Errorf("table %s: cardinality cannot be combined with \"cycle_break\": \"update\"", tableName))
+		}
+	case CycleBreakDeferred:
+	default:
+		errs = append(errs, fmt.Errorf("table %s: foreign key %v: unknown cycle_break '%s'\n  → Suggestion: Use one of: update, deferred", tableName, fk.Columns, fk.CycleBreak))
+	}
+
+	if fk.ReferencedTable == tableName {
+		errs = append(errs, fmt.Errorf("table %s: cycle_break is not needed on self-referencing foreign keys\n  → Suggestion: Use a 'hierarchy' block instead", tableName))
+	}
+
+	return errs
+}
+
 func validateDependencies(s *Schema) []error {
 	var errs []error
 
-	// Build dependency graph
+	// Build dependency graph (keys marked with cycle_break may close a cycle)
 	deps := make(map[string][]string)
 	for tableName, table := range s.Tables {
-		for _, fk := range table.ForeignKeys {
-			// Skip self-references (allowed pattern)
-			if fk.ReferencedTable != tableName {
-				deps[tableName] = append(deps[tableName], fk.ReferencedTable)
-			}
-		}
+		deps[tableName] = TableDependencies(tableName, table)
 	}
 
 	// Detect cycles using DFS with path tracking
@@ -196,7 +901,7 @@ func validateDependencies(s *Schema) []error {
 			if hasCycle(table, []string{}) {
 				// Format the cycle path
 				cycleStr := strings.Join(cyclePath, " → ")
-				errs = append(errs, fmt.Errorf("circular dependency detected in foreign key relationships\n  → Cycle: %s\n  → Suggestion: Remove one of the foreign key relationships in this cycle, or make the relationship nullable to allow NULL values during data generation", cycleStr))
+				errs = append(errs, fmt.Errorf("circular dependency detected in foreign key relationships\n  → Cycle: %s\n  → Suggestion: Set \"cycle_break\": \"update\" (nullable column, values set by UPDATE after loading) or \"deferred\" (DEFERRABLE INITIALLY DEFERRED constraint) on one foreign key in this cycle", cycleStr))
 				break
 			}
 		}
@@ -254,6 +959,9 @@ func isValidPostgresType(typeName string) bool {
 
 		// XML type
 		"xml": true,
+
+		// Extension types
+		"ltree": true,
 	}
 
 	return validTypes[baseType]
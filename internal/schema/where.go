@@ -0,0 +1,29 @@
+package schema
+
+import "fmt"
+
+// Qualifies reports whether a parent row matches the foreign key's Where
+// filter. Values are compared as text.
+func (fk *ForeignKey) Qualifies(parent map[string]interface{}) bool {
+	for column, expected := range fk.Where {
+		actual := fmt.Sprintf("%v", parent[column])
+		if options, ok := expected.([]interface{}); ok {
+			if !containsText(options, actual) {
+				return false
+			}
+		} else if actual != fmt.Sprintf("%v", expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsText reports whether any of the values prints as text
+func containsText(values []interface{}, text string) bool {
+	for _, v := range values {
+		if fmt.Sprintf("%v", v) == text {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const aggregateSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "order_count", "type": "integer", "generator_config": {"type": "aggregate", "function": "count", "from": "orders", "via": "customer_id"}}
+			],
+			"primary_key": ["id"],
+			"row_count": 15
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "customer_id", "type": "integer"},
+				{"name": "total_amount", "type": "numeric(10,2)", "generator_config": {
+					"type": "aggregate", "function": "sum", "from": "order_items", "via": "order_id", "value": "quantity * unit_price"
+				}},
+				{"name": "largest_quantity", "type": "integer", "nullable": true, "generator_config": {
+					"type": "aggregate", "function": "max", "from": "order_items", "via": "order_id", "value": "quantity"
+				}}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["customer_id"], "referenced_table": "customers", "referenced_columns": ["id"]}],
+			"row_count": 30
+		},
+		"order_items": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "order_id", "type": "integer"},
+				{"name": "quantity", "type": "integer", "generator_config": {"type": "integer_range", "min": 1, "max": 5}},
+				{"name": "unit_price", "type": "integer", "generator_config": {"type": "integer_range", "min": 10, "max": 99}}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["order_id"], "referenced_table": "orders", "referenced_columns": ["id"]}],
+			"row_count": 120
+		}
+	}
+}`
+
+func TestAggregateColumns(t *testing.T) {
+	generate := func(t *testing.T, configure func(c *pipeline.Coordinator)) string {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		configure(coordinator)
+
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(aggregateSchemaJSON), output, 4))
+		return output.String()
+	}
+
+	unquote := func(v string) int {
+		n, err := strconv.Atoi(strings.Trim(v, "'"))
+		require.NoError(t, err)
+		return n
+	}
+
+	dump := generate(t, func(c *pipeline.Coordinator) {})
+
+	// Expected aggregates from the generated order items
+	totals, largest := map[string]int{}, map[string]int{}
+	orderIDs := columnValues(dump, "order_items", 1)
+	quantities := columnValues(dump, "order_items", 2)
+	prices := columnValues(dump, "order_items", 3)
+	for i, orderID := range orderIDs {
+		totals[orderID] += unquote(quantities[i]) * unquote(prices[i])
+		largest[orderID] = max(largest[orderID], unquote(quantities[i]))
+	}
+
+	t.Run("sum and max over child rows", func(t *testing.T) {
+		updates := regexp.MustCompile(`UPDATE orders SET total_amount = ([^,]+), largest_quantity = ([^ ]+) WHERE id = ([^;]+);`).FindAllStringSubmatch(dump, -1)
+		require.Len(t, updates, 30)
+
+		for _, m := range updates {
+			assert.Equal(t, fmt.Sprintf("'%d.00'", totals[m[3]]), m[1], "order %s", m[3])
+			if largest[m[3]] == 0 {
+				assert.Equal(t, "NULL", m[2], "order %s has no items", m[3])
+			} else {
+				assert.Equal(t, strconv.Itoa(largest[m[3]]), m[2], "order %s", m[3])
+			}
+		}
+	})
+
+	t.Run("count of child rows", func(t *testing.T) {
+		counts := map[string]int{}
+		for _, customerID := range columnValues(dump, "orders", 1) {
+			counts[customerID]++
+		}
+
+		updates := regexp.MustCompile(`UPDATE customers SET order_count = (\d+) WHERE id = ([^;]+);`).FindAllStringSubmatch(dump, -1)
+		require.Len(t, updates, 15)
+		for _, m := range updates {
+			assert.Equal(t, strconv.Itoa(counts[m[2]]), m[1], "customer %s", m[2])
+		}
+	})
+
+	t.Run("children are generated for filtered parents", func(t *testing.T) {
+		filtered := generate(t, func(c *pipeline.Coordinator) {
+			c.SetTableFilter(&pipeline.TableFilter{Include: []string{"orders"}})
+		})
+
+		assert.NotContains(t, filtered, "INSERT INTO order_items")
+		assert.Contains(t, filtered, "UPDATE orders SET total_amount")
+		assert.Equal(t,
+			regexp.MustCompile(`UPDATE orders .*`).FindAllString(dump, -1),
+			regexp.MustCompile(`UPDATE orders .*`).FindAllString(filtered, -1))
+	})
+}
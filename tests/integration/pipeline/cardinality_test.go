@@ -0,0 +1,63 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelationshipCardinality(t *testing.T) {
+	schemaJSON := `{
+		"version": "1.0",
+		"database": {"name": "billing", "encoding": "UTF8"},
+		"tables": {
+			"invoices": {
+				"columns": [{"name": "id", "type": "serial"}],
+				"primary_key": ["id"],
+				"row_count": 40
+			},
+			"line_items": {
+				"columns": [
+					{"name": "id", "type": "serial"},
+					{"name": "invoice_id", "type": "integer"}
+				],
+				"primary_key": ["id"],
+				"foreign_keys": [{
+					"columns": ["invoice_id"],
+					"referenced_table": "invoices",
+					"referenced_columns": ["id"],
+					"cardinality": {"min": 1, "max": 5}
+				}]
+			}
+		}
+	}`
+
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(schemaJSON), output, 3))
+	dump := output.String()
+
+	t.Run("every invoice has 1 to 5 line items", func(t *testing.T) {
+		perInvoice := map[string]int{}
+		for _, invoiceID := range columnValues(dump, "line_items", 1) {
+			perInvoice[invoiceID]++
+		}
+
+		assert.Len(t, perInvoice, 40)
+		for invoiceID, n := range perInvoice {
+			assert.True(t, n >= 1 && n <= 5, "invoice %s has %d line items", invoiceID, n)
+		}
+	})
+
+	t.Run("row count is derived from the cardinality", func(t *testing.T) {
+		lineItems := strings.Count(dump, "INSERT INTO line_items")
+		assert.GreaterOrEqual(t, lineItems, 40)
+		assert.LessOrEqual(t, lineItems, 200)
+	})
+}
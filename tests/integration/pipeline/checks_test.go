@@ -0,0 +1,148 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const checksSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"products": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "name", "type": "varchar(20)"},
+				{"name": "price", "type": "numeric(10,2)"},
+				{"name": "quantity", "type": "integer"},
+				{"name": "discount", "type": "integer"},
+				{"name": "status", "type": "varchar(10)"},
+				{"name": "sku", "type": "varchar(10)"},
+				{"name": "start_date", "type": "date"},
+				{"name": "end_date", "type": "date"}
+			],
+			"primary_key": ["id"],
+			"check_constraints": [
+				{"name": "positive_price", "expression": "price > 0 AND price <= 500"},
+				{"expression": "quantity BETWEEN 1 AND 10"},
+				{"expression": "discount >= 0 AND discount <= 100 AND quantity * 10 >= discount"},
+				{"expression": "status IN ('active', 'retired') AND status NOT IN ('draft')"},
+				{"expression": "sku LIKE 'SK%' OR sku IS NULL"},
+				{"expression": "start_date >= '2024-01-01'::date AND end_date >= start_date"},
+				{"expression": "name ~ '^[A-Z]'"},
+				{"expression": "id <= 5"}
+			],
+			"row_count": 40
+		}
+	}
+}`
+
+func TestCheckConstraints(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(checksSchemaJSON), output, 43))
+	dump := output.String()
+
+	t.Run("single-column bounds", func(t *testing.T) {
+		prices := columnValues(dump, "products", 2)
+		quantities := columnValues(dump, "products", 3)
+		statuses := columnValues(dump, "products", 5)
+		require.Len(t, prices, 40)
+
+		for i := range prices {
+			price, err := strconv.ParseFloat(strings.Trim(prices[i], "'"), 64)
+			require.NoError(t, err)
+			assert.True(t, price > 0 && price <= 500, "price %v", price)
+			assert.Regexp(t, `^'\d+\.\d{2}'$`, prices[i])
+
+			quantity, err := strconv.Atoi(quantities[i])
+			require.NoError(t, err)
+			assert.True(t, quantity >= 1 && quantity <= 10, "quantity %d", quantity)
+
+			assert.Contains(t, []string{"'active'", "'retired'"}, statuses[i])
+		}
+	})
+
+	t.Run("multi-column conditions", func(t *testing.T) {
+		quantities := columnValues(dump, "products", 3)
+		discounts := columnValues(dump, "products", 4)
+		starts := columnValues(dump, "products", 7)
+		ends := columnValues(dump, "products", 8)
+
+		for i := range discounts {
+			quantity, _ := strconv.Atoi(quantities[i])
+			discount, err := strconv.Atoi(discounts[i])
+			require.NoError(t, err)
+			assert.True(t, discount >= 0 && discount <= quantity*10, "discount %d, quantity %d", discount, quantity)
+
+			start, err := time.Parse("2006-01-02 15:04:05", strings.Trim(starts[i], "'"))
+			require.NoError(t, err)
+			end, err := time.Parse("2006-01-02 15:04:05", strings.Trim(ends[i], "'"))
+			require.NoError(t, err)
+			assert.False(t, start.Before(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+			assert.False(t, end.Before(start))
+		}
+	})
+
+	t.Run("reports constraints it could not satisfy", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"table products: CHECK (sku LIKE 'SK%' OR sku IS NULL) is violated by 40 row(s)",
+			"table products: CHECK (name ~ '^[A-Z]') is not supported and was not enforced: unsupported operator ~",
+			"table products: CHECK (id <= 5) is violated by 35 row(s)",
+		}, coordinator.Warnings())
+	})
+}
+
+const stringBoundsSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"coupons": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "code", "type": "varchar(10)", "nullable": false},
+				{"name": "level", "type": "integer", "nullable": false}
+			],
+			"primary_key": ["id"],
+			"check_constraints": [
+				{"expression": "code >= 'm'"},
+				{"expression": "level IN (1, 2) AND level > 5"}
+			],
+			"row_count": 10
+		}
+	}
+}`
+
+func TestCheckConstraintsWithoutDrawableBounds(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(stringBoundsSchemaJSON), output, 43))
+	dump := output.String()
+
+	codes := columnValues(dump, "coupons", 1)
+	levels := columnValues(dump, "coupons", 2)
+	require.Len(t, codes, 10)
+	violations := 0
+	for i := range codes {
+		assert.NotEqual(t, "NULL", codes[i], "NOT NULL columns keep their value")
+		assert.NotEqual(t, "NULL", levels[i], "NOT NULL columns keep their value")
+		if strings.Trim(codes[i], "'") < "m" {
+			violations++
+		}
+	}
+	assert.Contains(t, coordinator.Warnings(), "table coupons: CHECK (level IN (1, 2) AND level > 5) is violated by 10 row(s)")
+	if violations > 0 {
+		assert.Contains(t, coordinator.Warnings(), "table coupons: CHECK (code >= 'm') is violated by "+strconv.Itoa(violations)+" row(s)")
+	}
+}
@@ -0,0 +1,99 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const conditionalSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "country", "type": "char(2)", "generator_config": {"type": "weighted_enum", "weights": {"JP": 1, "US": 1, "DE": 1}}},
+				{"name": "language", "type": "char(2)", "generator_config": {"type": "conditional", "given": ["country"], "weights": {
+					"JP": {"ja": 9, "en": 1},
+					"DE": {"de": 1},
+					"*": {"en": 1}
+				}}}
+			],
+			"primary_key": ["id"],
+			"row_count": 30
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "customer_id", "type": "integer"},
+				{"name": "channel", "type": "varchar(10)", "generator_config": {"type": "weighted_enum", "weights": {"web": 1, "store": 1}}},
+				{"name": "currency", "type": "char(3)", "generator_config": {"type": "conditional", "given": ["customers.country", "channel"], "weights": {
+					"JP|*": {"JPY": 1},
+					"US|*": {"USD": 1},
+					"DE|store": {"EUR": 1},
+					"*": {"EUR": 1, "USD": 1}
+				}}}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["customer_id"], "referenced_table": "customers", "referenced_columns": ["id"]}],
+			"row_count": 200
+		}
+	}
+}`
+
+func TestConditionalWeights(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(conditionalSchemaJSON), output, 45))
+	dump := output.String()
+
+	countries := map[string]string{}
+	customerIDs, customerCountries := columnValues(dump, "customers", 0), columnValues(dump, "customers", 1)
+	languages := columnValues(dump, "customers", 2)
+	require.Len(t, customerIDs, 30)
+	for i, id := range customerIDs {
+		countries[id] = customerCountries[i]
+	}
+
+	t.Run("weights depend on the row", func(t *testing.T) {
+		for i, country := range customerCountries {
+			switch country {
+			case "'JP'":
+				assert.Contains(t, []string{"'ja'", "'en'"}, languages[i])
+			case "'DE'":
+				assert.Equal(t, "'de'", languages[i])
+			default:
+				assert.Equal(t, "'en'", languages[i])
+			}
+		}
+	})
+
+	t.Run("weights depend on the parent row", func(t *testing.T) {
+		customers, channels, currencies := columnValues(dump, "orders", 1), columnValues(dump, "orders", 2), columnValues(dump, "orders", 3)
+		require.Len(t, currencies, 200)
+		seen := map[string]bool{}
+		for i, currency := range currencies {
+			country := countries[customers[i]]
+			require.NotEmpty(t, country)
+			switch {
+			case country == "'JP'":
+				assert.Equal(t, "'JPY'", currency)
+			case country == "'US'":
+				assert.Equal(t, "'USD'", currency)
+			case channels[i] == "'store'":
+				assert.Equal(t, "'EUR'", currency)
+			default:
+				assert.Contains(t, []string{"'EUR'", "'USD'"}, currency)
+			}
+			seen[currency] = true
+		}
+		assert.Len(t, seen, 3)
+	})
+}
@@ -0,0 +1,101 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const correlationSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "bank", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "income", "type": "numeric(10,2)"},
+				{"name": "credit_limit", "type": "integer"},
+				{"name": "utilisation", "type": "double precision"},
+				{"name": "limit_ratio", "type": "double precision", "expression": "credit_limit / income"}
+			],
+			"primary_key": ["id"],
+			"correlations": [{
+				"columns": ["income", "credit_limit", "utilisation"],
+				"marginals": {
+					"income": {"type": "log_normal", "mean": 10.8, "std_dev": 0.4},
+					"credit_limit": {"type": "normal", "mean": 8000, "std_dev": 2500, "min": 500},
+					"utilisation": {"type": "uniform", "min": 0, "max": 1}
+				},
+				"matrix": [[1, 0.85, -0.3], [0.85, 1, -0.3], [-0.3, -0.3, 1]]
+			}],
+			"row_count": 2000
+		}
+	}
+}`
+
+func TestCorrelatedColumns(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(correlationSchemaJSON), output, 44))
+	dump := output.String()
+
+	parse := func(column int) []float64 {
+		raw := columnValues(dump, "customers", column)
+		values := make([]float64, len(raw))
+		for i, v := range raw {
+			f, err := strconv.ParseFloat(strings.Trim(v, "'"), 64)
+			require.NoError(t, err, v)
+			values[i] = f
+		}
+		return values
+	}
+	incomes, limits, utilisation, ratios := parse(1), parse(2), parse(3), parse(4)
+	require.Len(t, incomes, 2000)
+
+	t.Run("values follow the column types and marginals", func(t *testing.T) {
+		for i := range incomes {
+			assert.True(t, incomes[i] > 0)
+			assert.Equal(t, math.Trunc(limits[i]), limits[i])
+			assert.True(t, limits[i] >= 500)
+			assert.True(t, utilisation[i] >= 0 && utilisation[i] <= 1)
+		}
+		assert.Regexp(t, `^'\d+\.\d{2}'$`, columnValues(dump, "customers", 1)[0])
+	})
+
+	t.Run("columns are correlated", func(t *testing.T) {
+		logIncomes := make([]float64, len(incomes))
+		for i, income := range incomes {
+			logIncomes[i] = math.Log(income)
+		}
+		assert.InDelta(t, 0.85, correlation(logIncomes, limits), 0.05)
+		assert.InDelta(t, -0.3, correlation(limits, utilisation), 0.08)
+	})
+
+	t.Run("expressions read correlated columns", func(t *testing.T) {
+		for i := range ratios {
+			assert.InDelta(t, limits[i]/incomes[i], ratios[i], 1e-3)
+		}
+	})
+}
+
+// correlation returns the Pearson correlation coefficient of two samples
+func correlation(xs, ys []float64) float64 {
+	var sx, sy, sxx, syy, sxy float64
+	n := float64(len(xs))
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		syy += ys[i] * ys[i]
+		sxy += xs[i] * ys[i]
+	}
+	return (n*sxy - sx*sy) / math.Sqrt((n*sxx-sx*sx)*(n*syy-sy*sy))
+}
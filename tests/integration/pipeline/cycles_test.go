@@ -0,0 +1,124 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cycleSchemaJSON links users and teams both ways; users.default_team_id
+// breaks the cycle with the given strategy
+func cycleSchemaJSON(cycleBreak string, nullable bool) string {
+	return `{
+		"version": "1.0",
+		"database": {"name": "workspace", "encoding": "UTF8"},
+		"tables": {
+			"users": {
+				"columns": [
+					{"name": "id", "type": "integer", "generator_config": {"type": "integer_range", "min": 100, "max": 999}},
+					{"name": "default_team_id", "type": "integer", "nullable": ` + strconv.FormatBool(nullable) + `}
+				],
+				"primary_key": ["id"],
+				"foreign_keys": [{
+					"columns": ["default_team_id"],
+					"referenced_table": "teams",
+					"referenced_columns": ["id"],
+					"cycle_break": "` + cycleBreak + `"
+				}],
+				"row_count": 12
+			},
+			"teams": {
+				"columns": [
+					{"name": "id", "type": "integer", "generator_config": {"type": "integer_range", "min": 5000, "max": 5999}},
+					{"name": "owner_id", "type": "integer"}
+				],
+				"primary_key": ["id"],
+				"foreign_keys": [{"columns": ["owner_id"], "referenced_table": "users", "referenced_columns": ["id"]}],
+				"row_count": 4
+			}
+		}
+	}`
+}
+
+func TestForeignKeyCycles(t *testing.T) {
+	generate := func(t *testing.T, schemaJSON string) string {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(schemaJSON), output, 5))
+		return output.String()
+	}
+
+	set := func(values []string) map[string]bool {
+		m := make(map[string]bool, len(values))
+		for _, v := range values {
+			m[v] = true
+		}
+		return m
+	}
+
+	t.Run("update inserts NULL and sets keys afterwards", func(t *testing.T) {
+		dump := generate(t, cycleSchemaJSON("update", true))
+
+		users := insertValues(dump, "users")
+		teams := insertValues(dump, "teams")
+		require.Len(t, users, 12)
+		require.Len(t, teams, 4)
+
+		assert.Less(t, strings.Index(dump, "INSERT INTO users"), strings.Index(dump, "INSERT INTO teams"))
+		for _, teamID := range columnValues(dump, "users", 1) {
+			assert.Equal(t, "NULL", teamID)
+		}
+		userIDs := set(users)
+		for _, ownerID := range columnValues(dump, "teams", 1) {
+			assert.True(t, userIDs[ownerID], "owner %s does not exist", ownerID)
+		}
+
+		updates := regexp.MustCompile(`UPDATE users SET default_team_id = ([^ ]+) WHERE id = ([^;]+);`).FindAllStringSubmatch(dump, -1)
+		require.Len(t, updates, 12)
+		teamIDs := set(teams)
+		for _, m := range updates {
+			assert.True(t, teamIDs[m[1]], "team %s does not exist", m[1])
+			assert.True(t, userIDs[m[2]], "user %s does not exist", m[2])
+		}
+		assert.Greater(t, strings.Index(dump, "UPDATE users"), strings.LastIndex(dump, "INSERT INTO teams"))
+	})
+
+	t.Run("deferred references rows loaded later in one transaction", func(t *testing.T) {
+		dump := generate(t, cycleSchemaJSON("deferred", false))
+
+		assert.Contains(t, dump, "ALTER TABLE users ADD CONSTRAINT users_default_team_id_fkey FOREIGN KEY (default_team_id) REFERENCES teams (id) DEFERRABLE INITIALLY DEFERRED;")
+		assert.Less(t, strings.Index(dump, "BEGIN;"), strings.Index(dump, "INSERT INTO"))
+		assert.True(t, strings.HasSuffix(dump, "COMMIT;\n"))
+
+		teamIDs := set(insertValues(dump, "teams"))
+		for _, teamID := range columnValues(dump, "users", 1) {
+			assert.True(t, teamIDs[teamID], "team %s does not exist", teamID)
+		}
+		userIDs := set(insertValues(dump, "users"))
+		for _, ownerID := range columnValues(dump, "teams", 1) {
+			assert.True(t, userIDs[ownerID], "owner %s does not exist", ownerID)
+		}
+	})
+
+	t.Run("formats without UPDATE statements are rejected", func(t *testing.T) {
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+
+		writer, err := pgdump.NewParquetWriter(t.TempDir(), pgdump.ParquetOptions{})
+		require.NoError(t, err)
+
+		err = coordinator.ExecuteWithWriter(strings.NewReader(cycleSchemaJSON("update", true)), writer, 5)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "UPDATE statements")
+	})
+}
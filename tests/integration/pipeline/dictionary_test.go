@@ -0,0 +1,114 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dictionarySchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"products": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "sku", "type": "varchar(20)", "generator_config": {"type": "dictionary", "file": "data/skus.csv", "replacement": false}},
+				{"name": "title", "type": "text", "generator_config": {"type": "dictionary", "file": "data/skus.csv", "column": "name", "replacement": false}},
+				{"name": "price", "type": "numeric(10,2)", "generator_config": {"type": "dictionary", "file": "data/skus.csv", "replacement": false}}
+			],
+			"primary_key": ["id"],
+			"row_count": ROWS
+		},
+		"flights": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "origin", "type": "char(3)", "generator_config": {"type": "dictionary", "file": "data/airports.txt"}},
+				{"name": "destination", "type": "char(3)", "generator_config": {"type": "dictionary", "file": "data/airports.txt", "draw": "destination"}}
+			],
+			"primary_key": ["id"],
+			"row_count": 300
+		}
+	}
+}`
+
+func TestDictionaryGenerator(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "data"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data", "skus.csv"), []byte(
+		"sku,name,price\n"+
+			"SKU-001,\"Kettle, 1.7L\",29.9\n"+
+			"SKU-002,Toaster,45\n"+
+			"SKU-003,Blender,79.5\n"+
+			"SKU-004,Coffee Grinder,34.25\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data", "airports.txt"), []byte("ATL\nLHR\nCDG\nHND\n"), 0o644))
+
+	generate := func(rows string, seed int64) (string, error) {
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		coordinator.SetBaseDir(dir)
+		output := new(bytes.Buffer)
+		err := coordinator.ExecuteWithFormat(strings.NewReader(strings.Replace(dictionarySchemaJSON, "ROWS", rows, 1)), output, seed, "copy")
+		return output.String(), err
+	}
+
+	dump, err := generate("4", 50)
+	require.NoError(t, err)
+
+	t.Run("columns of one draw read the same row, each row once", func(t *testing.T) {
+		_, rows, err := pgdump.ReadCopySection(strings.NewReader(dump), "products")
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		catalogue := map[string][2]string{
+			"SKU-001": {"Kettle, 1.7L", "29.90"},
+			"SKU-002": {"Toaster", "45.00"},
+			"SKU-003": {"Blender", "79.50"},
+			"SKU-004": {"Coffee Grinder", "34.25"},
+		}
+		seen := make(map[string]bool)
+		for _, row := range rows {
+			sku := row[1].(string)
+			require.Contains(t, catalogue, sku)
+			assert.Equal(t, catalogue[sku][0], row[2], sku)
+			assert.Equal(t, catalogue[sku][1], row[3], sku)
+			assert.False(t, seen[sku], "%s drawn twice", sku)
+			seen[sku] = true
+		}
+	})
+
+	t.Run("draws of another name pick their own row", func(t *testing.T) {
+		_, rows, err := pgdump.ReadCopySection(strings.NewReader(dump), "flights")
+		require.NoError(t, err)
+		require.Len(t, rows, 300)
+
+		same := 0
+		for _, row := range rows {
+			assert.Contains(t, []string{"ATL", "LHR", "CDG", "HND"}, row[1])
+			assert.Contains(t, []string{"ATL", "LHR", "CDG", "HND"}, row[2])
+			if row[1] == row[2] {
+				same++
+			}
+		}
+		assert.InDelta(t, 0.25, float64(same)/300, 0.1)
+	})
+
+	t.Run("output depends only on the seed", func(t *testing.T) {
+		again, err := generate("4", 50)
+		require.NoError(t, err)
+		assert.Equal(t, dump, again)
+	})
+
+	t.Run("more rows than the dictionary without replacement", func(t *testing.T) {
+		_, err := generate("5", 50)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dictionary rows are used up after 4 draws without replacement")
+	})
+}
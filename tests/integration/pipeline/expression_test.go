@@ -0,0 +1,106 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Expression columns are declared before the columns they read, so the
+// coordinator has to reorder generation.
+const expressionSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "email", "type": "varchar(200)", "expression": "lower(first_name || '.' || last_name) || '@example.com'"},
+				{"name": "full_name", "type": "varchar(200)", "expression": "first_name || ' ' || last_name"},
+				{"name": "first_name", "type": "varchar(50)"},
+				{"name": "last_name", "type": "varchar(50)"}
+			],
+			"primary_key": ["id"],
+			"row_count": 20
+		},
+		"products": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "sale_price", "type": "numeric(10,2)", "expression": "round(price - discount, 2)"},
+				{"name": "discount", "type": "numeric(10,2)", "expression": "if(quantity >= 50, price * 0.1, 0)"},
+				{"name": "price", "type": "numeric(10,2)", "generator": "integer_range", "generator_config": {"min": 5, "max": 500}},
+				{"name": "quantity", "type": "integer", "generator": "integer_range", "generator_config": {"min": 1, "max": 100}},
+				{"name": "tier", "type": "varchar(10)", "expression": "if(quantity >= 50, 'bulk', 'single')"}
+			],
+			"primary_key": ["id"],
+			"row_count": 40
+		}
+	}
+}`
+
+func TestExpressionColumns(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+	coordinator.RegisterSemanticGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(expressionSchemaJSON), output, 41))
+	dump := output.String()
+
+	t.Run("text derived from columns declared later", func(t *testing.T) {
+		emails := columnValues(dump, "customers", 1)
+		names := columnValues(dump, "customers", 2)
+		first := columnValues(dump, "customers", 3)
+		last := columnValues(dump, "customers", 4)
+		require.Len(t, names, 20)
+
+		for i := range names {
+			firstName, lastName := strings.Trim(first[i], "'"), strings.Trim(last[i], "'")
+			assert.Equal(t, "'"+firstName+" "+lastName+"'", names[i])
+			expected := strings.ToLower(firstName+"."+lastName) + "@example.com"
+			assert.Equal(t, "'"+strings.ReplaceAll(expected, "'", "''")+"'", emails[i])
+		}
+	})
+
+	t.Run("arithmetic and conditionals", func(t *testing.T) {
+		parse := func(v string) float64 {
+			f, err := strconv.ParseFloat(strings.Trim(v, "'"), 64)
+			require.NoError(t, err)
+			return f
+		}
+
+		salePrices := columnValues(dump, "products", 1)
+		discounts := columnValues(dump, "products", 2)
+		prices := columnValues(dump, "products", 3)
+		quantities := columnValues(dump, "products", 4)
+		tiers := columnValues(dump, "products", 5)
+		require.Len(t, salePrices, 40)
+
+		bulk := 0
+		for i := range salePrices {
+			price, discount := parse(prices[i]), parse(discounts[i])
+			if parse(quantities[i]) >= 50 {
+				bulk++
+				assert.Equal(t, "'bulk'", tiers[i])
+				assert.InDelta(t, price*0.1, discount, 0.006)
+			} else {
+				assert.Equal(t, "'single'", tiers[i])
+				assert.Zero(t, discount)
+			}
+			assert.InDelta(t, price-discount, parse(salePrices[i]), 0.006)
+		}
+		assert.NotZero(t, bulk)
+		assert.Less(t, bulk, 40)
+	})
+
+	t.Run("is deterministic", func(t *testing.T) {
+		again := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(expressionSchemaJSON), again, 41))
+		assert.Equal(t, dump, again.String())
+	})
+}
@@ -0,0 +1,155 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filterSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"order_items": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "order_id", "type": "integer"},
+				{"name": "quantity", "type": "integer"}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [
+				{"columns": ["order_id"], "referenced_table": "orders", "referenced_columns": ["id"]}
+			],
+			"row_count": 30
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "integer", "generator_config": {"type": "integer_range", "min": 1000, "max": 9999}},
+				{"name": "customer_id", "type": "integer"}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [
+				{"columns": ["customer_id"], "referenced_table": "customers", "referenced_columns": ["id"]}
+			],
+			"row_count": 10
+		},
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "integer", "generator_config": {"type": "integer_range", "min": 500, "max": 599}},
+				{"name": "name", "type": "varchar(50)"}
+			],
+			"primary_key": ["id"],
+			"row_count": 5
+		}
+	}
+}`
+
+// insertValues returns the first value of each INSERT into a table
+func insertValues(dump, table string) []string {
+	re := regexp.MustCompile(`INSERT INTO ` + table + ` \([^)]*\) VALUES \(([^,)]+)`)
+	var values []string
+	for _, m := range re.FindAllStringSubmatch(dump, -1) {
+		values = append(values, m[1])
+	}
+	return values
+}
+
+// columnValues returns the values of one column of each INSERT into a table
+func columnValues(dump, table string, index int) []string {
+	re := regexp.MustCompile(`INSERT INTO ` + table + ` \([^)]*\) VALUES \(([^)]*)\);`)
+	var values []string
+	for _, m := range re.FindAllStringSubmatch(dump, -1) {
+		values = append(values, strings.Split(m[1], ", ")[index])
+	}
+	return values
+}
+
+func generateDump(t *testing.T, configure func(c *pipeline.Coordinator)) string {
+	t.Helper()
+
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+	configure(coordinator)
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(filterSchemaJSON), output, 7))
+	return output.String()
+}
+
+func TestOutputModesAndFilters(t *testing.T) {
+	t.Run("foreign keys reference generated parent rows", func(t *testing.T) {
+		dump := generateDump(t, func(c *pipeline.Coordinator) {})
+
+		require.Len(t, columnValues(dump, "order_items", 1), 30)
+		orderIDs := map[string]bool{}
+		for _, id := range insertValues(dump, "orders") {
+			orderIDs[id] = true
+		}
+		for _, orderID := range columnValues(dump, "order_items", 1) {
+			assert.True(t, orderIDs[orderID], "order_items.order_id %s has no parent", orderID)
+		}
+
+		// Parents are written before children
+		assert.Less(t, strings.Index(dump, "INSERT INTO customers"), strings.Index(dump, "INSERT INTO orders"))
+		assert.Less(t, strings.Index(dump, "INSERT INTO orders"), strings.Index(dump, "INSERT INTO order_items"))
+	})
+
+	t.Run("schema-only writes no data", func(t *testing.T) {
+		dump := generateDump(t, func(c *pipeline.Coordinator) {
+			c.SetOutputMode(pipeline.ModeSchemaOnly)
+		})
+
+		assert.Contains(t, dump, "CREATE TABLE orders")
+		assert.NotContains(t, dump, "INSERT INTO")
+	})
+
+	t.Run("data-only writes no DDL", func(t *testing.T) {
+		dump := generateDump(t, func(c *pipeline.Coordinator) {
+			c.SetOutputMode(pipeline.ModeDataOnly)
+		})
+
+		assert.NotContains(t, dump, "CREATE DATABASE")
+		assert.NotContains(t, dump, "CREATE TABLE")
+		assert.Contains(t, dump, "\\connect shop")
+		assert.Equal(t, 45, strings.Count(dump, "INSERT INTO"))
+	})
+
+	t.Run("excluded parents keep child foreign keys consistent", func(t *testing.T) {
+		full := generateDump(t, func(c *pipeline.Coordinator) {})
+		childOnly := generateDump(t, func(c *pipeline.Coordinator) {
+			c.SetTableFilter(&pipeline.TableFilter{Include: []string{"order_*"}})
+		})
+
+		assert.NotContains(t, childOnly, "CREATE TABLE orders")
+		assert.NotContains(t, childOnly, "INSERT INTO orders")
+		assert.Contains(t, childOnly, "CREATE TABLE order_items")
+
+		// The same parent keys are generated even though orders is not written
+		require.Len(t, columnValues(childOnly, "order_items", 1), 30)
+		assert.Equal(t, columnValues(full, "order_items", 1), columnValues(childOnly, "order_items", 1))
+	})
+
+	t.Run("exclude wins over include", func(t *testing.T) {
+		dump := generateDump(t, func(c *pipeline.Coordinator) {
+			c.SetTableFilter(&pipeline.TableFilter{Include: []string{"*"}, Exclude: []string{"order*"}})
+		})
+
+		assert.Contains(t, dump, "INSERT INTO customers")
+		assert.NotContains(t, dump, "INSERT INTO orders")
+		assert.NotContains(t, dump, "INSERT INTO order_items")
+	})
+
+	t.Run("unmatched include pattern is an error", func(t *testing.T) {
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		coordinator.SetTableFilter(&pipeline.TableFilter{Include: []string{"invoices"}})
+
+		err := coordinator.Execute(strings.NewReader(filterSchemaJSON), new(bytes.Buffer), 7)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfReferencingHierarchy(t *testing.T) {
+	schemaJSON := `{
+		"version": "1.0",
+		"database": {"name": "org", "encoding": "UTF8"},
+		"tables": {
+			"employees": {
+				"columns": [
+					{"name": "id", "type": "serial"},
+					{"name": "manager_id", "type": "integer", "nullable": true},
+					{"name": "path", "type": "ltree"},
+					{"name": "level", "type": "integer"}
+				],
+				"primary_key": ["id"],
+				"foreign_keys": [{
+					"columns": ["manager_id"],
+					"referenced_table": "employees",
+					"referenced_columns": ["id"],
+					"hierarchy": {
+						"roots": 2,
+						"max_depth": 4,
+						"fan_out": {"min": 1, "max": 3},
+						"path_column": "path",
+						"path_separator": ".",
+						"depth_column": "level"
+					}
+				}],
+				"row_count": 60
+			}
+		}
+	}`
+
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(schemaJSON), output, 11))
+	dump := output.String()
+
+	ids := columnValues(dump, "employees", 0)
+	managers := columnValues(dump, "employees", 1)
+	paths := columnValues(dump, "employees", 2)
+	levels := columnValues(dump, "employees", 3)
+	require.Len(t, ids, 60)
+
+	t.Run("roots have no manager", func(t *testing.T) {
+		assert.Equal(t, []string{"NULL", "NULL"}, managers[:2])
+		assert.Equal(t, []string{"'1'", "'2'"}, paths[:2])
+	})
+
+	t.Run("managers are earlier rows and paths extend theirs", func(t *testing.T) {
+		position := map[string]int{}
+		for i, id := range ids {
+			position[id] = i
+		}
+
+		for i := 2; i < len(ids); i++ {
+			parent, ok := position[managers[i]]
+			require.True(t, ok, "manager %s of row %d does not exist", managers[i], i)
+			assert.Less(t, parent, i)
+
+			parentPath := strings.Trim(paths[parent], "'")
+			assert.Equal(t, "'"+parentPath+"."+ids[i]+"'", paths[i])
+			assert.LessOrEqual(t, levels[i], "4")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJunctionTable(t *testing.T) {
+	schemaJSON := `{
+		"version": "1.0",
+		"database": {"name": "catalog", "encoding": "UTF8"},
+		"tables": {
+			"products": {
+				"columns": [{"name": "id", "type": "serial"}],
+				"primary_key": ["id"],
+				"row_count": 12
+			},
+			"tags": {
+				"columns": [{"name": "id", "type": "serial"}],
+				"primary_key": ["id"],
+				"row_count": 6
+			},
+			"product_tags": {
+				"columns": [
+					{"name": "product_id", "type": "integer"},
+					{"name": "tag_id", "type": "integer"}
+				],
+				"primary_key": ["product_id", "tag_id"],
+				"foreign_keys": [
+					{"columns": ["product_id"], "referenced_table": "products", "referenced_columns": ["id"]},
+					{"columns": ["tag_id"], "referenced_table": "tags", "referenced_columns": ["id"]}
+				],
+				"junction": {
+					"left": {"columns": ["product_id"], "degree": {"min": 1, "max": 4}},
+					"right": {"columns": ["tag_id"], "degree": {"min": 0, "max": 12, "distribution": "zipf"}}
+				},
+				"row_count": 500
+			}
+		}
+	}`
+
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(schemaJSON), output, 9))
+	dump := output.String()
+
+	productIDs := columnValues(dump, "product_tags", 0)
+	tagIDs := columnValues(dump, "product_tags", 1)
+
+	t.Run("table is sized to the achievable pairs", func(t *testing.T) {
+		assert.Len(t, productIDs, 48)
+	})
+
+	t.Run("pairs are distinct and reference existing rows", func(t *testing.T) {
+		products := map[string]bool{}
+		for _, id := range insertValues(dump, "products") {
+			products[id] = true
+		}
+		tags := map[string]bool{}
+		for _, id := range insertValues(dump, "tags") {
+			tags[id] = true
+		}
+
+		seen := map[string]bool{}
+		for i := range productIDs {
+			pair := productIDs[i] + "/" + tagIDs[i]
+			assert.False(t, seen[pair], "pair %s repeated", pair)
+			seen[pair] = true
+			assert.True(t, products[productIDs[i]], "product %s does not exist", productIDs[i])
+			assert.True(t, tags[tagIDs[i]], "tag %s does not exist", tagIDs[i])
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lifecycleSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "created_at", "type": "timestamp"},
+				{"name": "status", "type": "varchar(20)", "lifecycle": {
+					"start": "created_at", "initial": "pending",
+					"states": {
+						"pending": {"transitions": {"paid": 0.8, "cancelled": 0.15}, "dwell": {"type": "log_normal", "mean": 1, "std_dev": 0.5}},
+						"paid": {"timestamp": "paid_at", "transitions": {"shipped": 0.9, "cancelled": 0.05}, "dwell": {"type": "uniform", "min": 2, "max": 48}},
+						"shipped": {"timestamp": "shipped_at", "transitions": {"delivered": 0.9}, "dwell": {"type": "normal", "mean": 72, "std_dev": 24, "min": 12}},
+						"delivered": {"timestamp": "delivered_at"},
+						"cancelled": {"timestamp": "cancelled_at"}
+					}
+				}},
+				{"name": "paid_at", "type": "timestamp", "nullable": true},
+				{"name": "shipped_at", "type": "timestamp", "nullable": true},
+				{"name": "delivered_at", "type": "timestamp", "nullable": true},
+				{"name": "cancelled_at", "type": "timestamp", "nullable": true},
+				{"name": "is_open", "type": "boolean", "expression": "is_null(delivered_at) and is_null(cancelled_at)"}
+			],
+			"primary_key": ["id"],
+			"row_count": 500
+		}
+	}
+}`
+
+func TestLifecycle(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+	coordinator.RegisterSemanticGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(lifecycleSchemaJSON), output, 46))
+	dump := output.String()
+
+	parse := func(t *testing.T, value string) *time.Time {
+		if value == "NULL" {
+			return nil
+		}
+		at, err := time.Parse("2006-01-02 15:04:05", strings.Trim(value, "'"))
+		require.NoError(t, err, value)
+		return &at
+	}
+
+	created, statuses := columnValues(dump, "orders", 1), columnValues(dump, "orders", 2)
+	require.Len(t, statuses, 500)
+	timestamps := map[string][]string{
+		"paid":      columnValues(dump, "orders", 3),
+		"shipped":   columnValues(dump, "orders", 4),
+		"delivered": columnValues(dump, "orders", 5),
+		"cancelled": columnValues(dump, "orders", 6),
+	}
+	open := columnValues(dump, "orders", 7)
+
+	// The states each final state is reached through, in order
+	paths := map[string][]string{
+		"pending":   {},
+		"paid":      {"paid"},
+		"shipped":   {"paid", "shipped"},
+		"delivered": {"paid", "shipped", "delivered"},
+	}
+
+	t.Run("timestamps match the status", func(t *testing.T) {
+		counts := map[string]int{}
+		for i, status := range statuses {
+			status = strings.Trim(status, "'")
+			counts[status]++
+
+			path, ok := paths[status]
+			if status == "cancelled" {
+				// Cancelled while pending or once paid
+				path, ok = []string{"cancelled"}, true
+				if timestamps["paid"][i] != "NULL" {
+					path = []string{"paid", "cancelled"}
+				}
+			}
+			require.True(t, ok, status)
+
+			previous := parse(t, created[i])
+			reached := map[string]bool{}
+			for _, state := range path {
+				at := parse(t, timestamps[state][i])
+				require.NotNil(t, at, "%s should have %s_at", status, state)
+				assert.False(t, at.Before(*previous), "%s_at before the previous state", state)
+				previous = at
+				reached[state] = true
+			}
+			for state, values := range timestamps {
+				if !reached[state] {
+					assert.Equal(t, "NULL", values[i], "%s should not have %s_at", status, state)
+				}
+			}
+			assert.Equal(t, status != "delivered" && status != "cancelled", open[i] == "TRUE", "is_open of %s", status)
+		}
+
+		for _, status := range []string{"pending", "paid", "shipped", "delivered", "cancelled"} {
+			assert.Greater(t, counts[status], 0, status)
+		}
+		assert.Greater(t, counts["delivered"], counts["shipped"])
+	})
+
+	t.Run("dwell times follow the distributions", func(t *testing.T) {
+		for i := range statuses {
+			paid, shipped := parse(t, timestamps["paid"][i]), parse(t, timestamps["shipped"][i])
+			if paid != nil && shipped != nil {
+				hours := shipped.Sub(*paid).Hours()
+				assert.True(t, hours >= 2-1.0/3600 && hours <= 48, "paid for %v hours", hours)
+			}
+			delivered := parse(t, timestamps["delivered"][i])
+			if delivered != nil {
+				assert.GreaterOrEqual(t, delivered.Sub(*shipped).Hours(), 12.0-1.0/3600)
+			}
+		}
+	})
+}
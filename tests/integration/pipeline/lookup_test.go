@@ -0,0 +1,107 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lookupSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "country", "type": "varchar(2)", "generator_config": {"type": "weighted_enum", "values": ["US", "DE", "JP"]}}
+			],
+			"primary_key": ["id"],
+			"row_count": 20
+		},
+		"products": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "price", "type": "integer", "generator_config": {"type": "integer_range", "min": 100, "max": 9999}}
+			],
+			"primary_key": ["id"],
+			"row_count": 30
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "customer_id", "type": "integer"},
+				{"name": "currency", "type": "varchar(3)", "generator_config": {
+					"type": "lookup", "from": "customers.country", "via": "customer_id",
+					"map": {"US": "USD", "DE": "EUR"}, "default": "XXX"
+				}}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["customer_id"], "referenced_table": "customers", "referenced_columns": ["id"]}],
+			"row_count": 40
+		},
+		"order_items": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "unit_price", "type": "integer", "generator_config": {"type": "lookup", "from": "products.price", "via": "product_id"}},
+				{"name": "product_id", "type": "integer"}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["product_id"], "referenced_table": "products", "referenced_columns": ["id"]}],
+			"row_count": 100
+		}
+	}
+}`
+
+func TestParentLookups(t *testing.T) {
+	generate := func(t *testing.T, spillRows int) string {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		coordinator.SetSpillThreshold(spillRows)
+
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(lookupSchemaJSON), output, 21))
+		return output.String()
+	}
+
+	// index maps the first column of each INSERT to another of its columns
+	index := func(dump, table string, column int) map[string]string {
+		m := map[string]string{}
+		keys := columnValues(dump, table, 0)
+		for i, v := range columnValues(dump, table, column) {
+			m[keys[i]] = v
+		}
+		return m
+	}
+
+	dump := generate(t, pipeline.DefaultSpillRows)
+
+	t.Run("values are copied from the parent row", func(t *testing.T) {
+		prices := index(dump, "products", 1)
+		unitPrices := columnValues(dump, "order_items", 1)
+		productIDs := columnValues(dump, "order_items", 2)
+		require.Len(t, unitPrices, 100)
+
+		for i, productID := range productIDs {
+			assert.Equal(t, prices[productID], unitPrices[i], "order item %d", i)
+		}
+	})
+
+	t.Run("values are mapped from the parent row", func(t *testing.T) {
+		countries := index(dump, "customers", 1)
+		currencies := map[string]string{"'US'": "'USD'", "'DE'": "'EUR'", "'JP'": "'XXX'"}
+		customerIDs := columnValues(dump, "orders", 1)
+
+		for i, currency := range columnValues(dump, "orders", 2) {
+			assert.Equal(t, currencies[countries[customerIDs[i]]], currency, "order %d", i)
+		}
+	})
+
+	t.Run("spilling parent rows to disk gives the same output", func(t *testing.T) {
+		assert.Equal(t, dump, generate(t, 7))
+	})
+}
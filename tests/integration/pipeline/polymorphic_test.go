@@ -0,0 +1,100 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const polymorphicSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "blog", "encoding": "UTF8"},
+	"tables": {
+		"comments": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "commentable_type", "type": "varchar(50)"},
+				{"name": "commentable_id", "type": "integer"}
+			],
+			"primary_key": ["id"],
+			"polymorphic": [{
+				"type_column": "commentable_type",
+				"id_column": "commentable_id",
+				"targets": [
+					{"table": "posts", "weight": 3, "value": "Post"},
+					{"table": "photos", "value": "Photo"}
+				]
+			}],
+			"row_count": 400
+		},
+		"photos": {
+			"columns": [{"name": "id", "type": "serial"}],
+			"primary_key": ["id"],
+			"row_count": 5
+		},
+		"posts": {
+			"columns": [{"name": "id", "type": "serial"}],
+			"primary_key": ["id"],
+			"row_count": 20
+		}
+	}
+}`
+
+func TestPolymorphicReferences(t *testing.T) {
+	generate := func(t *testing.T, filter *pipeline.TableFilter) string {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		if filter != nil {
+			coordinator.SetTableFilter(filter)
+		}
+
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(polymorphicSchemaJSON), output, 13))
+		return output.String()
+	}
+
+	t.Run("targets are generated before the referencing table", func(t *testing.T) {
+		dump := generate(t, nil)
+		comments := strings.Index(dump, "INSERT INTO comments")
+		require.NotEqual(t, -1, comments)
+		assert.Less(t, strings.Index(dump, "INSERT INTO photos"), comments)
+		assert.Less(t, strings.Index(dump, "INSERT INTO posts"), comments)
+		assert.NotContains(t, dump, "FOREIGN KEY")
+	})
+
+	t.Run("ids reference rows of the chosen table", func(t *testing.T) {
+		dump := generate(t, nil)
+		ids := map[string]map[string]bool{"'Post'": {}, "'Photo'": {}}
+		for _, id := range columnValues(dump, "posts", 0) {
+			ids["'Post'"][id] = true
+		}
+		for _, id := range columnValues(dump, "photos", 0) {
+			ids["'Photo'"][id] = true
+		}
+
+		types := columnValues(dump, "comments", 1)
+		refs := columnValues(dump, "comments", 2)
+		require.Len(t, types, 400)
+		perType := map[string]int{}
+		for i, typ := range types {
+			require.Contains(t, ids, typ)
+			assert.True(t, ids[typ][refs[i]], "%s %s does not exist", typ, refs[i])
+			perType[typ]++
+		}
+		assert.InDelta(t, 300, perType["'Post'"], 40)
+		assert.InDelta(t, 100, perType["'Photo'"], 40)
+	})
+
+	t.Run("filtered out targets are still generated for their keys", func(t *testing.T) {
+		full := generate(t, nil)
+		filtered := generate(t, &pipeline.TableFilter{Include: []string{"comments"}})
+
+		assert.NotContains(t, filtered, "INSERT INTO posts")
+		assert.Equal(t, columnValues(full, "comments", 2), columnValues(filtered, "comments", 2))
+	})
+}
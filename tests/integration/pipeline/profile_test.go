@@ -0,0 +1,98 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/NhaLeTruc/datagen-cli/internal/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "amount", "type": "numeric(10,2)", "generator_config": {"type": "distribution",
+					"distribution": {"type": "normal", "mean": 100, "std_dev": 20}}},
+				{"name": "status", "type": "varchar(20)", "generator_config": {"type": "distribution",
+					"distribution": {"type": "weighted", "null_rate": 0.2, "weights": {"paid": 3, "refunded": 1}}}}
+			],
+			"primary_key": ["id"],
+			"row_count": 4000
+		}
+	}
+}`
+
+func TestProfileRoundTrip(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+	sample := new(bytes.Buffer)
+	require.NoError(t, coordinator.ExecuteWithFormat(strings.NewReader(sampleSchemaJSON), sample, 48, "copy"))
+
+	columns, rows, err := pgdump.ReadCopySection(sample, "orders")
+	require.NoError(t, err)
+	require.Len(t, rows, 4000)
+	learned := profile.Build(columns, rows, profile.Options{})
+
+	amount, err := json.Marshal(learned.Columns[1].Distribution)
+	require.NoError(t, err)
+	status, err := json.Marshal(learned.Columns[2].Distribution)
+	require.NoError(t, err)
+	assert.Equal(t, "numeric", learned.Columns[1].Kind)
+	assert.Equal(t, "text", learned.Columns[2].Kind)
+
+	// Generate new rows from the learned distributions
+	schemaJSON := fmt.Sprintf(`{
+		"version": "1.0",
+		"database": {"name": "shop", "encoding": "UTF8"},
+		"tables": {
+			"orders": {
+				"columns": [
+					{"name": "id", "type": "serial"},
+					{"name": "amount", "type": "numeric(10,2)", "generator_config": {"type": "distribution", "distribution": %s}},
+					{"name": "status", "type": "varchar(20)", "generator_config": {"type": "distribution", "distribution": %s}}
+				],
+				"primary_key": ["id"],
+				"row_count": 4000
+			}
+		}
+	}`, amount, status)
+
+	coordinator = pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(schemaJSON), output, 49))
+	dump := output.String()
+
+	var sum, sumSquares float64
+	amounts := columnValues(dump, "orders", 1)
+	require.Len(t, amounts, 4000)
+	for _, v := range amounts {
+		f, err := strconv.ParseFloat(strings.Trim(v, "'"), 64)
+		require.NoError(t, err, v)
+		sum += f
+		sumSquares += f * f
+	}
+	mean := sum / 4000
+	assert.InDelta(t, 100, mean, 2)
+	assert.InDelta(t, 400, sumSquares/4000-mean*mean, 40)
+
+	counts := make(map[string]int)
+	for _, v := range columnValues(dump, "orders", 2) {
+		counts[v]++
+	}
+	assert.Len(t, counts, 3)
+	assert.InDelta(t, 0.2, float64(counts["NULL"])/4000, 0.03)
+	assert.InDelta(t, 0.6, float64(counts["'paid'"])/4000, 0.03)
+	assert.InDelta(t, 0.2, float64(counts["'refunded'"])/4000, 0.03)
+}
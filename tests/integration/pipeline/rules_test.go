@@ -0,0 +1,97 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Rule columns are declared before the columns their conditions read.
+const rulesSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "tier", "type": "varchar(10)", "generator": "weighted_enum", "generator_config": {"weights": {"gold": 1, "silver": 3}}}
+			],
+			"primary_key": ["id"],
+			"row_count": 30
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "customer_id", "type": "integer"},
+				{"name": "priority", "type": "varchar(10)", "rules": [
+					{"if": {"or": [{"customers.tier": "gold"}, {"amount": {"gt": 900}}]}, "then": {"value": "high"}, "else": {"value": "normal"}}
+				]},
+				{"name": "channel", "type": "varchar(10)", "rules": [
+					{"if": {"amount": {"between": [1, 100]}}, "then": {"weights": {"web": 1, "store": 1}}}
+				]},
+				{"name": "shipping_fee", "type": "integer", "rules": [
+					{"if": {"customers.tier": {"not": "gold"}, "amount": {"lt": 500}}, "then": {"min": 5, "max": 20}},
+					{"if": {}, "then": {"value": 0}}
+				]},
+				{"name": "amount", "type": "integer", "generator": "integer_range", "generator_config": {"min": 1, "max": 1000}}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["customer_id"], "referenced_table": "customers", "referenced_columns": ["id"]}],
+			"row_count": 300
+		}
+	}
+}`
+
+func TestBusinessRules(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(rulesSchemaJSON), output, 42))
+	dump := output.String()
+
+	tiers := map[string]string{}
+	ids := columnValues(dump, "customers", 0)
+	for i, tier := range columnValues(dump, "customers", 1) {
+		tiers[ids[i]] = strings.Trim(tier, "'")
+	}
+
+	customers := columnValues(dump, "orders", 1)
+	priorities := columnValues(dump, "orders", 2)
+	channels := columnValues(dump, "orders", 3)
+	fees := columnValues(dump, "orders", 4)
+	amounts := columnValues(dump, "orders", 5)
+	require.Len(t, amounts, 300)
+
+	seen := map[string]bool{}
+	for i := range amounts {
+		amount, err := strconv.Atoi(amounts[i])
+		require.NoError(t, err)
+		fee, err := strconv.Atoi(fees[i])
+		require.NoError(t, err)
+		gold := tiers[customers[i]] == "gold"
+
+		if gold || amount > 900 {
+			assert.Equal(t, "'high'", priorities[i])
+		} else {
+			assert.Equal(t, "'normal'", priorities[i])
+		}
+
+		if amount <= 100 {
+			assert.Contains(t, []string{"'web'", "'store'"}, channels[i])
+		}
+
+		if !gold && amount < 500 {
+			assert.True(t, fee >= 5 && fee <= 20, "fee %d", fee)
+		} else {
+			assert.Zero(t, fee)
+		}
+		seen[priorities[i]] = true
+	}
+	assert.Len(t, seen, 2)
+}
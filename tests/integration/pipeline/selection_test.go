@@ -0,0 +1,100 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const selectionSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "app", "encoding": "UTF8"},
+	"tables": {
+		"accounts": {
+			"columns": [{"name": "id", "type": "serial"}],
+			"primary_key": ["id"],
+			"row_count": 200
+		},
+		"events": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "account_id", "type": "integer"}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["account_id"], "referenced_table": "accounts", "referenced_columns": ["id"]%s}],
+			"row_count": 10000
+		}
+	}
+}`
+
+func TestForeignKeySelection(t *testing.T) {
+	// references returns the number of events per account, busiest first
+	references := func(t *testing.T, selection string) []int {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(fmt.Sprintf(selectionSchemaJSON, selection)), output, 5))
+
+		perAccount := map[string]int{}
+		for _, id := range columnValues(output.String(), "events", 1) {
+			perAccount[id]++
+		}
+		counts := make([]int, 0, len(perAccount))
+		for _, n := range perAccount {
+			counts = append(counts, n)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+		return counts
+	}
+
+	// topShare returns the share of references received by the busiest accounts
+	topShare := func(counts []int, accounts int) float64 {
+		top := 0
+		for _, n := range counts[:min(accounts, len(counts))] {
+			top += n
+		}
+		return float64(top) / 10000
+	}
+
+	t.Run("uniform selection matches the default", func(t *testing.T) {
+		assert.Equal(t, references(t, ""), references(t, `, "selection": {"distribution": "uniform"}`))
+		assert.Less(t, topShare(references(t, ""), 2), 0.03)
+	})
+
+	t.Run("hotspot gives the top rows their share", func(t *testing.T) {
+		counts := references(t, `, "selection": {"distribution": "hotspot", "top": 0.01, "share": 0.5}`)
+		assert.InDelta(t, 0.5, topShare(counts, 2), 0.03)
+	})
+
+	t.Run("pareto follows the 80/20 rule", func(t *testing.T) {
+		counts := references(t, `, "selection": {"distribution": "pareto"}`)
+		assert.InDelta(t, 0.8, topShare(counts, 40), 0.05)
+	})
+
+	t.Run("zipf concentrates references on few rows", func(t *testing.T) {
+		counts := references(t, `, "selection": {"distribution": "zipf", "alpha": 2}`)
+		assert.Greater(t, topShare(counts, 1), 0.5)
+	})
+
+	t.Run("skewed references point at existing rows", func(t *testing.T) {
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(fmt.Sprintf(selectionSchemaJSON, `, "selection": {"distribution": "zipf"}`)), output, 5))
+
+		for _, id := range columnValues(output.String(), "events", 1) {
+			n, err := strconv.Atoi(id)
+			require.NoError(t, err)
+			assert.True(t, n >= 1 && n <= 200, "account %d does not exist", n)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const subsetSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"coupons": {
+			"columns": [{"name": "id", "type": "serial"}],
+			"primary_key": ["id"],
+			"row_count": 10
+		},
+		"products": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "status", "type": "varchar(20)", "generator_config": {"type": "weighted_enum", "values": ["active", "discontinued", "draft"]}}
+			],
+			"primary_key": ["id"],
+			"row_count": 60
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "coupon_id", "type": "integer", "nullable": true},
+				{"name": "product_id", "type": "integer"}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [
+				{"columns": ["coupon_id"], "referenced_table": "coupons", "referenced_columns": ["id"], "null_rate": 0.8},
+				{"columns": ["product_id"], "referenced_table": "products", "referenced_columns": ["id"], "where": {"status": "active"}}
+			],
+			"row_count": 1000
+		}
+	}
+}`
+
+func TestForeignKeySubsets(t *testing.T) {
+	generate := func(t *testing.T, schemaJSON string, filter *pipeline.TableFilter) (string, error) {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		if filter != nil {
+			coordinator.SetTableFilter(filter)
+		}
+		output := new(bytes.Buffer)
+		err := coordinator.Execute(strings.NewReader(schemaJSON), output, 17)
+		return output.String(), err
+	}
+
+	t.Run("null rate leaves a share of the keys NULL", func(t *testing.T) {
+		dump, err := generate(t, subsetSchemaJSON, nil)
+		require.NoError(t, err)
+
+		nulls := 0
+		for _, v := range columnValues(dump, "orders", 1) {
+			if v == "NULL" {
+				nulls++
+			}
+		}
+		assert.InDelta(t, 800, nulls, 50)
+	})
+
+	t.Run("where only references qualifying parents", func(t *testing.T) {
+		dump, err := generate(t, subsetSchemaJSON, nil)
+		require.NoError(t, err)
+
+		status := map[string]string{}
+		ids := columnValues(dump, "products", 0)
+		for i, s := range columnValues(dump, "products", 1) {
+			status[ids[i]] = s
+		}
+		active := 0
+		for _, s := range status {
+			if s == "'active'" {
+				active++
+			}
+		}
+		require.Greater(t, active, 1)
+
+		referenced := map[string]bool{}
+		for _, id := range columnValues(dump, "orders", 2) {
+			assert.Equal(t, "'active'", status[id], "product %s", id)
+			referenced[id] = true
+		}
+		assert.Len(t, referenced, active)
+	})
+
+	t.Run("filtered out parents keep their subset", func(t *testing.T) {
+		full, err := generate(t, subsetSchemaJSON, nil)
+		require.NoError(t, err)
+		filtered, err := generate(t, subsetSchemaJSON, &pipeline.TableFilter{Include: []string{"orders"}})
+		require.NoError(t, err)
+
+		assert.NotContains(t, filtered, "INSERT INTO products")
+		assert.Equal(t, columnValues(full, "orders", 2), columnValues(filtered, "orders", 2))
+	})
+
+	t.Run("no qualifying parent is an error for NOT NULL keys", func(t *testing.T) {
+		_, err := generate(t, strings.Replace(subsetSchemaJSON, `{"status": "active"}`, `{"status": "archived"}`, 1), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no rows matching")
+	})
+}
@@ -0,0 +1,102 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const temporalSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "created_at", "type": "timestamp"}
+			],
+			"primary_key": ["id"],
+			"row_count": 25
+		},
+		"orders": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "customer_id", "type": "integer"},
+				{"name": "updated_at", "type": "timestamp", "temporal": {"after": "created_at"}},
+				{"name": "shipped_at", "type": "timestamp", "temporal": {"after": "created_at", "within": "72h"}},
+				{"name": "created_at", "type": "timestamp", "temporal": {"after": "customers.created_at", "within": "30d"}}
+			],
+			"primary_key": ["id"],
+			"foreign_keys": [{"columns": ["customer_id"], "referenced_table": "customers", "referenced_columns": ["id"]}],
+			"row_count": 150
+		}
+	}
+}`
+
+func TestTemporalConstraints(t *testing.T) {
+	generate := func(t *testing.T, tables []string) string {
+		t.Helper()
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		coordinator.RegisterSemanticGenerators()
+		if tables != nil {
+			coordinator.SetTableFilter(&pipeline.TableFilter{Include: tables})
+		}
+
+		output := new(bytes.Buffer)
+		require.NoError(t, coordinator.Execute(strings.NewReader(temporalSchemaJSON), output, 37))
+		return output.String()
+	}
+
+	parseTimes := func(t *testing.T, values []string) []time.Time {
+		t.Helper()
+		times := make([]time.Time, len(values))
+		for i, v := range values {
+			ts, err := time.Parse("2006-01-02 15:04:05", strings.Trim(v, "'"))
+			require.NoError(t, err)
+			times[i] = ts
+		}
+		return times
+	}
+
+	check := func(t *testing.T, dump string) {
+		t.Helper()
+		customerCreated := map[string]time.Time{}
+		ids := columnValues(dump, "customers", 0)
+		for i, ts := range parseTimes(t, columnValues(dump, "customers", 1)) {
+			customerCreated[ids[i]] = ts
+		}
+
+		customers := columnValues(dump, "orders", 1)
+		updated := parseTimes(t, columnValues(dump, "orders", 2))
+		shipped := parseTimes(t, columnValues(dump, "orders", 3))
+		created := parseTimes(t, columnValues(dump, "orders", 4))
+		require.Len(t, created, 150)
+
+		for i := range created {
+			parent := customerCreated[customers[i]]
+			assert.False(t, created[i].Before(parent), "order %d created before its customer", i)
+			assert.False(t, created[i].After(parent.Add(30*24*time.Hour)), "order %d created more than 30 days after its customer", i)
+			assert.False(t, updated[i].Before(created[i]), "order %d updated before it was created", i)
+			assert.False(t, shipped[i].Before(created[i]), "order %d shipped before it was created", i)
+			assert.False(t, shipped[i].After(created[i].Add(72*time.Hour)), "order %d shipped more than 72h after creation", i)
+		}
+	}
+
+	t.Run("timestamps follow the same row and the parent row", func(t *testing.T) {
+		check(t, generate(t, nil))
+	})
+
+	t.Run("parent timestamps are kept when the parent table is filtered out", func(t *testing.T) {
+		full := generate(t, nil)
+		filtered := generate(t, []string{"orders"})
+
+		assert.NotContains(t, filtered, "INSERT INTO customers")
+		assert.Equal(t, columnValues(full, "orders", 4), columnValues(filtered, "orders", 4))
+	})
+}
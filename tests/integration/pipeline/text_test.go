@@ -0,0 +1,75 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const textSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"reviews": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "title", "type": "varchar(40)", "generator_config": {"type": "text", "mode": "product_title"}},
+				{"name": "body", "type": "text", "generator_config": {"type": "text", "mode": "markov",
+					"corpus": "corpus/reviews.txt", "order": 1, "count": 2, "max_length": 120}}
+			],
+			"primary_key": ["id"],
+			"row_count": 200
+		}
+	}
+}`
+
+func TestTextGenerator(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "corpus"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corpus", "reviews.txt"),
+		[]byte("Great kettle. The lid is loose. Great price and the lid fits.\n"), 0o644))
+
+	generate := func(baseDir string, seed int64) (string, error) {
+		coordinator := pipeline.NewCoordinator()
+		coordinator.RegisterBasicGenerators()
+		coordinator.SetBaseDir(baseDir)
+		output := new(bytes.Buffer)
+		err := coordinator.ExecuteWithFormat(strings.NewReader(textSchemaJSON), output, seed, "copy")
+		return output.String(), err
+	}
+
+	dump, err := generate(dir, 49)
+	require.NoError(t, err)
+	_, rows, err := pgdump.ReadCopySection(strings.NewReader(dump), "reviews")
+	require.NoError(t, err)
+	require.Len(t, rows, 200)
+
+	vocabulary := map[string]bool{}
+	for _, word := range strings.Fields("Great kettle. The lid is loose. Great price and the lid fits.") {
+		vocabulary[word] = true
+	}
+	for _, row := range rows {
+		title, body := row[1].(string), row[2].(string)
+		assert.LessOrEqual(t, utf8.RuneCountInString(title), 40, title)
+		assert.LessOrEqual(t, utf8.RuneCountInString(body), 120, body)
+		for _, word := range strings.Fields(body) {
+			assert.True(t, vocabulary[word] || vocabulary[word+"."] || vocabulary[strings.TrimSuffix(word, ".")], "%q in %q", word, body)
+		}
+	}
+
+	again, err := generate(dir, 49)
+	require.NoError(t, err)
+	assert.Equal(t, dump, again)
+
+	_, err = generate(t.TempDir(), 49)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open corpus")
+}
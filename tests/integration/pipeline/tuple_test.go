@@ -0,0 +1,74 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tupleSchemaJSON = `{
+	"version": "1.0",
+	"database": {"name": "shop", "encoding": "UTF8"},
+	"tables": {
+		"customers": {
+			"columns": [
+				{"name": "id", "type": "serial"},
+				{"name": "first_name", "type": "varchar(50)"},
+				{"name": "last_name", "type": "varchar(50)"},
+				{"name": "email", "type": "varchar(100)"},
+				{"name": "city", "type": "varchar(50)"},
+				{"name": "country", "type": "varchar(50)"},
+				{"name": "postal_code", "type": "varchar(10)"},
+				{"name": "latitude", "type": "numeric(9,6)"},
+				{"name": "card_brand", "type": "varchar(20)"},
+				{"name": "card_expires", "type": "date"},
+				{"name": "greeting", "type": "text", "expression": "'Dear ' || first_name || ' of ' || city"}
+			],
+			"primary_key": ["id"],
+			"tuples": [
+				{"generator": "person", "columns": {"first_name": "first_name", "last_name": "last_name", "email": "email"}},
+				{"generator": "address", "countries": ["DE", "US"], "columns": {"city": "city", "country": "country", "postal_code": "postal_code", "latitude": "latitude"}},
+				{"generator": "credit_card", "columns": {"brand": "card_brand", "expiry_date": "card_expires"}}
+			],
+			"row_count": 50
+		}
+	}
+}`
+
+func TestTupleColumns(t *testing.T) {
+	coordinator := pipeline.NewCoordinator()
+	coordinator.RegisterBasicGenerators()
+	coordinator.RegisterSemanticGenerators()
+
+	output := new(bytes.Buffer)
+	require.NoError(t, coordinator.Execute(strings.NewReader(tupleSchemaJSON), output, 47))
+	dump := output.String()
+
+	firstNames, emails := columnValues(dump, "customers", 1), columnValues(dump, "customers", 3)
+	cities, countries := columnValues(dump, "customers", 4), columnValues(dump, "customers", 5)
+	postalCodes, latitudes := columnValues(dump, "customers", 6), columnValues(dump, "customers", 7)
+	expiries, greetings := columnValues(dump, "customers", 9), columnValues(dump, "customers", 10)
+	require.Len(t, cities, 50)
+
+	for i := range cities {
+		first := strings.Trim(firstNames[i], "'")
+		assert.True(t, strings.HasPrefix(strings.Trim(emails[i], "'"), strings.ToLower(first)+"."), "email %s of %s", emails[i], first)
+
+		switch countries[i] {
+		case "'Germany'":
+			assert.Contains(t, []string{"'Berlin'", "'Munich'", "'Hamburg'"}, cities[i])
+		case "'United States'":
+			assert.Contains(t, []string{"'New York'", "'Los Angeles'", "'Chicago'", "'Houston'", "'Seattle'"}, cities[i])
+		default:
+			t.Errorf("unexpected country %s", countries[i])
+		}
+		assert.Regexp(t, `^'\d{5}'$`, postalCodes[i])
+		assert.Regexp(t, `^'\d+\.\d{6}'$`, latitudes[i])
+		assert.Regexp(t, `^'20\d\d-\d\d-\d\d 00:00:00'$`, expiries[i])
+		assert.Equal(t, "'Dear "+first+" of "+strings.Trim(cities[i], "'")+"'", greetings[i])
+	}
+}
@@ -0,0 +1,86 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/cli"
+	"github.com/NhaLeTruc/datagen-cli/internal/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileCommand(t *testing.T) {
+	run := func(t *testing.T, stdin string, args ...string) (*profile.Profile, error) {
+		cmd := cli.NewProfileCommand()
+		output := new(bytes.Buffer)
+		cmd.SetOut(output)
+		cmd.SetErr(output)
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			return nil, err
+		}
+
+		var p profile.Profile
+		require.NoError(t, json.Unmarshal(output.Bytes(), &p), output.String())
+		return &p, nil
+	}
+
+	t.Run("profile command has expected metadata", func(t *testing.T) {
+		cmd := cli.NewProfileCommand()
+		assert.Equal(t, "profile", cmd.Use)
+		assert.NotEmpty(t, cmd.Short)
+		assert.NotEmpty(t, cmd.Long)
+		for _, flag := range []string{"input", "output", "table", "top", "bins"} {
+			assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+		}
+	})
+
+	t.Run("profile CSV from stdin", func(t *testing.T) {
+		p, err := run(t, "qty,tier\n1,gold\n2,\n2,silver\n3,gold\n")
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, p.Rows)
+		require.Len(t, p.Columns, 2)
+		assert.Equal(t, "qty", p.Columns[0].Name)
+		assert.Equal(t, "integer", p.Columns[0].Kind)
+		require.Len(t, p.Columns[0].Distribution.Bins, 3)
+		assert.Equal(t, 0.5, p.Columns[0].Distribution.Bins[1].Weight)
+
+		tier := p.Columns[1].Distribution
+		assert.Equal(t, "empirical", tier.Type)
+		assert.Equal(t, 0.25, tier.NullRate)
+		assert.Equal(t, map[string]interface{}{"gold": 0.666666, "silver": 0.333333}, tier.Weights)
+	})
+
+	t.Run("profile a table of a dump", func(t *testing.T) {
+		dump := filepath.Join(t.TempDir(), "dump.sql")
+		require.NoError(t, os.WriteFile(dump, []byte(strings.Join([]string{
+			"COPY public.users (id, name) FROM stdin;",
+			"1\tada",
+			"\\.",
+			"COPY public.orders (id, total) FROM stdin;",
+			"1\t10.5",
+			"2\t\\N",
+			"\\.",
+			"",
+		}, "\n")), 0o644))
+
+		p, err := run(t, "", "--input", dump, "--table", "orders", "--top", "1")
+		require.NoError(t, err)
+		assert.Equal(t, dump+":orders", p.Source)
+		assert.Equal(t, 2, p.Rows)
+		require.Len(t, p.Columns, 2)
+		assert.Equal(t, "numeric", p.Columns[1].Kind)
+		assert.Equal(t, 0.5, p.Columns[1].Distribution.NullRate)
+
+		_, err = run(t, "", "--input", dump, "--table", "items")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no COPY section for table items")
+	})
+}
@@ -0,0 +1,152 @@
+package expression_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/expression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eval(t *testing.T, source string, row map[string]interface{}) interface{} {
+	t.Helper()
+	expr, err := expression.Parse(source)
+	require.NoError(t, err, source)
+	val, err := expr.Eval(row)
+	require.NoError(t, err, source)
+	return val
+}
+
+func TestParse(t *testing.T) {
+	t.Run("records referenced columns once, in order", func(t *testing.T) {
+		expr, err := expression.Parse(`first_name || ' ' || last_name || "Last Name" || first_name`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first_name", "last_name", "Last Name"}, expr.Columns())
+	})
+
+	t.Run("rejects invalid expressions", func(t *testing.T) {
+		for source, message := range map[string]string{
+			"price *":            "unexpected end",
+			"'open":              "unterminated string",
+			"price $ 2":          "unexpected character",
+			"(price + 1":         "expected ')'",
+			"price 2":            "unexpected \"2\"",
+			"shout(name)":        "unknown function shout()",
+			"lower(a, b)":        "lower() takes 1 argument",
+			"if(a)":              "if() takes 2 or 3 arguments",
+			"substr(name)":       "substr() takes 2 to 3 arguments",
+			"price > 1 and or 2": "unexpected \"or\"",
+		} {
+			_, err := expression.Parse(source)
+			require.Error(t, err, source)
+			assert.Contains(t, err.Error(), message, source)
+		}
+	})
+}
+
+func TestEval(t *testing.T) {
+	row := map[string]interface{}{
+		"first_name": "Ada",
+		"last_name":  "Lovelace",
+		"title":      "Hello, World: Part 2!",
+		"price":      19.99,
+		"quantity":   3,
+		"stock":      int64(0),
+		"total":      "42.50",
+		"active":     true,
+		"note":       nil,
+		"ordered_at": time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC),
+	}
+
+	t.Run("arithmetic", func(t *testing.T) {
+		assert.Equal(t, int64(7), eval(t, "quantity * 2 + 1", row))
+		assert.Equal(t, int64(-3), eval(t, "-quantity", row))
+		assert.Equal(t, int64(1), eval(t, "quantity % 2", row))
+		assert.Equal(t, 1.5, eval(t, "quantity / 2", row))
+		assert.InDelta(t, 59.97, eval(t, "price * quantity", row), 1e-9)
+		assert.InDelta(t, 42.6, eval(t, "total + 0.1", row), 1e-9)
+		assert.Equal(t, int64(9), eval(t, "(quantity + 0) * (1 + 2)", row))
+	})
+
+	t.Run("text", func(t *testing.T) {
+		assert.Equal(t, "Ada Lovelace", eval(t, "first_name || ' ' || last_name", row))
+		assert.Equal(t, "ada.lovelace@example.com", eval(t, "lower(first_name || '.' || last_name) || '@example.com'", row))
+		assert.Equal(t, "hello-world-part-2", eval(t, "slug(title)", row))
+		assert.Equal(t, "it's", eval(t, "'it''s'", row))
+		assert.Equal(t, "Love", eval(t, "substr(last_name, 1, 4)", row))
+		assert.Equal(t, "lace", eval(t, "right(last_name, 4)", row))
+		assert.Equal(t, "ADA", eval(t, "upper(left(first_name, 3))", row))
+		assert.Equal(t, int64(8), eval(t, "length(last_name)", row))
+		assert.Equal(t, "Lovelaze", eval(t, "replace(last_name, 'c', 'z')", row))
+		assert.Equal(t, "3 x 19.99", eval(t, "quantity || ' x ' || price", row))
+	})
+
+	t.Run("numbers", func(t *testing.T) {
+		assert.Equal(t, 2.0, eval(t, "round(price * 0.1, 2)", row))
+		assert.Equal(t, 20.0, eval(t, "ceil(price)", row))
+		assert.Equal(t, int64(19), eval(t, "int(price)", row))
+		assert.Equal(t, int64(3), eval(t, "max(quantity, 1, 2)", row))
+		assert.Equal(t, int64(0), eval(t, "min(stock, quantity)", row))
+		assert.Equal(t, int64(5), eval(t, "abs(2 - 7)", row))
+	})
+
+	t.Run("conditionals and logic", func(t *testing.T) {
+		assert.Equal(t, "bulk", eval(t, "if(quantity >= 3, 'bulk', 'single')", row))
+		assert.Equal(t, "out", eval(t, "if(stock = 0 and active, 'out', 'in')", row))
+		assert.Equal(t, true, eval(t, "not (quantity < 2) or price > 100", row))
+		assert.Equal(t, true, eval(t, "first_name <> last_name", row))
+		assert.Nil(t, eval(t, "if(quantity > 5, 'bulk')", row))
+		assert.Equal(t, int64(0), eval(t, "if(stock = 0, 0, 10 / stock)", row), "the unused branch is not evaluated")
+	})
+
+	t.Run("null propagates", func(t *testing.T) {
+		assert.Nil(t, eval(t, "note || 'x'", row))
+		assert.Nil(t, eval(t, "upper(note)", row))
+		assert.Nil(t, eval(t, "note = 'x'", row))
+		assert.Equal(t, "x", eval(t, "coalesce(note, 'x')", row))
+		assert.Equal(t, "Ada", eval(t, "concat(note, first_name)", row))
+		assert.Equal(t, "no", eval(t, "if(note = 'x', 'yes', 'no')", row))
+		assert.Equal(t, false, eval(t, "note = 'x' and false", row))
+		assert.Nil(t, eval(t, "note = 'x' and true", row))
+		assert.Equal(t, true, eval(t, "is_null(note)", row))
+		assert.Equal(t, false, eval(t, "is_null(first_name)", row))
+	})
+
+	t.Run("patterns", func(t *testing.T) {
+		assert.Equal(t, true, eval(t, "like(last_name, 'Love%')", row))
+		assert.Equal(t, true, eval(t, "like(first_name, 'A_a')", row))
+		assert.Equal(t, false, eval(t, "like(first_name, 'a%')", row))
+		assert.Equal(t, true, eval(t, "like('50%', '50\\%')", row))
+		assert.Equal(t, false, eval(t, "like('500', '50\\%')", row))
+		assert.Nil(t, eval(t, "like(note, '%')", row))
+	})
+
+	t.Run("dates", func(t *testing.T) {
+		assert.Equal(t, time.Date(2024, 2, 3, 10, 30, 0, 0, time.UTC), eval(t, "add_days(ordered_at, 3)", row))
+		assert.Equal(t, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), eval(t, "add_minutes(add_hours(ordered_at, 1), 30)", row))
+		assert.Equal(t, time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC), eval(t, "add_months(ordered_at, 1)", row))
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), eval(t, "date(ordered_at)", row))
+		assert.Equal(t, int64(10), eval(t, "days_between(ordered_at, '2024-02-10 12:00:00')", row))
+		assert.Equal(t, int64(2024), eval(t, "year(ordered_at)", row))
+		assert.Equal(t, true, eval(t, "ordered_at > '2024-01-01'", row))
+		assert.Equal(t, "2024-01-31 10:30:00", eval(t, "text(ordered_at)", row))
+	})
+
+	t.Run("reports evaluation errors", func(t *testing.T) {
+		for source, message := range map[string]string{
+			"10 / stock":         "division by zero",
+			"first_name * 2":     "cannot apply * to text and integer",
+			"first_name > 3":     "cannot compare text with integer",
+			"if(quantity, 1, 2)": "expected a boolean condition",
+			"add_days(title, 1)": "add_days(): expected a timestamp",
+			"missing + 1":        "column missing has no value",
+		} {
+			expr, err := expression.Parse(source)
+			require.NoError(t, err, source)
+			_, err = expr.Eval(row)
+			require.Error(t, err, source)
+			assert.Contains(t, err.Error(), message, source)
+		}
+	})
+}
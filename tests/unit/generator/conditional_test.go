@@ -0,0 +1,69 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalGenerator(t *testing.T) {
+	col := &schema.Column{Name: "currency", Type: "char(3)"}
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "conditional", "given": ["country", "customers.segment"], "weights": {
+		"JP|*": {"JPY": 95, "USD": 5},
+		"US|*": {"USD": 1},
+		"*|b2b": {"USD": 1, "EUR": 1}
+	}}`), &col.GeneratorConfig))
+	cond, err := col.Conditional()
+	require.NoError(t, err)
+	gen := generator.NewConditionalGenerator(cond)
+
+	ctx := generator.NewContextWithSeed(45)
+	segment := "b2c"
+	ctx.ParentValue = func(table, column string) (interface{}, bool) {
+		if table == "customers" && column == "segment" {
+			return segment, true
+		}
+		return nil, false
+	}
+
+	t.Run("weights follow the row", func(t *testing.T) {
+		counts := map[string]int{}
+		ctx.RowData = map[string]interface{}{"country": "JP"}
+		for i := 0; i < 2000; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			counts[val.(string)]++
+		}
+		assert.InDelta(t, 1900, counts["JPY"], 60)
+		assert.Equal(t, 2000, counts["JPY"]+counts["USD"])
+
+		ctx.RowData = map[string]interface{}{"country": "US"}
+		val, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "USD", val)
+	})
+
+	t.Run("weights follow the parent row", func(t *testing.T) {
+		segment = "b2b"
+		ctx.RowData = map[string]interface{}{"country": "FR"}
+		seen := map[interface{}]bool{}
+		for i := 0; i < 100; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			seen[val] = true
+		}
+		assert.Equal(t, map[interface{}]bool{"USD": true, "EUR": true}, seen)
+	})
+
+	t.Run("reports rows without weights", func(t *testing.T) {
+		segment = "b2c"
+		ctx.RowData = map[string]interface{}{"country": "FR"}
+		_, err := gen.Generate(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no conditional weights for country=FR, customers.segment=b2c")
+	})
+}
@@ -0,0 +1,116 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCopula(t *testing.T, source string) *generator.CopulaGenerator {
+	t.Helper()
+	var corr schema.Correlation
+	require.NoError(t, json.Unmarshal([]byte(source), &corr))
+	gen, err := generator.NewCopulaGenerator(&corr)
+	require.NoError(t, err)
+	return gen
+}
+
+// pearson returns the correlation coefficient of two samples
+func pearson(xs, ys []float64) float64 {
+	var sx, sy, sxx, syy, sxy float64
+	n := float64(len(xs))
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		syy += ys[i] * ys[i]
+		sxy += xs[i] * ys[i]
+	}
+	return (n*sxy - sx*sy) / math.Sqrt((n*sxx-sx*sx)*(n*syy-sy*sy))
+}
+
+func TestCopulaGenerator(t *testing.T) {
+	const samples = 20000
+
+	t.Run("follows marginals and correlations", func(t *testing.T) {
+		gen := newCopula(t, `{
+			"columns": ["height", "weight", "score"],
+			"marginals": {
+				"height": {"type": "normal", "mean": 170, "std_dev": 10},
+				"weight": {"type": "log_normal", "mean": 4.2, "std_dev": 0.2},
+				"score": {"type": "uniform", "min": 0, "max": 100}
+			},
+			"matrix": [[1, 0.8, -0.5], [0.8, 1, -0.4], [-0.5, -0.4, 1]]
+		}`)
+
+		ctx := generator.NewContextWithSeed(44)
+		heights, weights, scores := make([]float64, samples), make([]float64, samples), make([]float64, samples)
+		var heightSum float64
+		for i := 0; i < samples; i++ {
+			values := gen.Sample(ctx)
+			heights[i], weights[i], scores[i] = values["height"], values["weight"], values["score"]
+			heightSum += heights[i]
+			assert.True(t, weights[i] > 0)
+			assert.True(t, scores[i] >= 0 && scores[i] <= 100)
+		}
+
+		assert.InDelta(t, 170, heightSum/samples, 0.5)
+		assert.InDelta(t, 0.8, pearson(heights, weights), 0.03)
+		assert.InDelta(t, -0.5, pearson(heights, scores), 0.03)
+		assert.InDelta(t, -0.4, pearson(weights, scores), 0.03)
+	})
+
+	t.Run("empirical marginals interpolate observed values", func(t *testing.T) {
+		gen := newCopula(t, `{
+			"columns": ["sessions", "page_views"],
+			"marginals": {
+				"sessions": {"type": "empirical", "values": [5, 1, 2, 3, 4]},
+				"page_views": {"type": "normal", "mean": 20, "std_dev": 5, "min": 0, "max": 30}
+			},
+			"matrix": [[1, 0.9], [0.9, 1]]
+		}`)
+
+		ctx := generator.NewContextWithSeed(7)
+		sessions, views := make([]float64, samples), make([]float64, samples)
+		for i := 0; i < samples; i++ {
+			values := gen.Sample(ctx)
+			sessions[i], views[i] = values["sessions"], values["page_views"]
+			assert.True(t, sessions[i] >= 1 && sessions[i] <= 5)
+			assert.True(t, views[i] >= 0 && views[i] <= 30)
+		}
+		assert.Greater(t, pearson(sessions, views), 0.8)
+	})
+
+	t.Run("same seed, same values", func(t *testing.T) {
+		source := `{"columns": ["a", "b"], "marginals": {"a": {"type": "normal", "mean": 0, "std_dev": 1}, "b": {"type": "uniform", "min": 1, "max": 2}}, "matrix": [[1, 0.3], [0.3, 1]]}`
+		first, second := newCopula(t, source), newCopula(t, source)
+		ctx1, ctx2 := generator.NewContextWithSeed(3), generator.NewContextWithSeed(3)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, first.Sample(ctx1), second.Sample(ctx2))
+		}
+	})
+
+	t.Run("rejects invalid matrices", func(t *testing.T) {
+		for source, message := range map[string]string{
+			`[[1, 0.5], [0.4, 1]]`: "not symmetric",
+			`[[1, 1.5], [1.5, 1]]`: "outside [-1, 1]",
+			`[[2, 0], [0, 1]]`:     "diagonal must be 1",
+			`[[1, 0]]`:             "matrix has 1 rows, expected 2",
+			`[[1, 0.9, 0.9], [0.9, 1, -0.9], [0.9, -0.9, 1]]`: "not positive definite",
+		} {
+			corr := &schema.Correlation{Columns: []string{"a", "b"}}
+			require.NoError(t, json.Unmarshal([]byte(source), &corr.Matrix))
+			if len(corr.Matrix) == 3 {
+				corr.Columns = append(corr.Columns, "c")
+			}
+			_, err := corr.Cholesky()
+			require.Error(t, err, source)
+			assert.Contains(t, err.Error(), message, source)
+		}
+	})
+}
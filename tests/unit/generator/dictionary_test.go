@@ -0,0 +1,108 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const airportsCSV = "\ufeffiata,name,passengers\n" +
+	"ATL,Hartsfield-Jackson,0.5\n" +
+	"LHR,Heathrow,0.3\n" +
+	"CDG,\"Paris, Charles de Gaulle\",0.2\n" +
+	"XXX,,0\n"
+
+func TestDictionary(t *testing.T) {
+	airports, err := generator.NewDictionary(strings.NewReader(airportsCSV), &schema.Dictionary{File: "airports.csv"})
+	require.NoError(t, err)
+	require.Equal(t, 4, airports.Len())
+
+	t.Run("columns are read by name, falling back to the first", func(t *testing.T) {
+		column, err := airports.Column("name", "")
+		require.NoError(t, err)
+		assert.Equal(t, "Paris, Charles de Gaulle", airports.Value(2, column))
+		assert.Nil(t, airports.Value(3, column), "empty fields are NULL")
+
+		column, err = airports.Column("", "IATA")
+		require.NoError(t, err)
+		assert.Equal(t, "LHR", airports.Value(1, column))
+
+		column, err = airports.Column("", "airport_code")
+		require.NoError(t, err)
+		assert.Equal(t, 0, column)
+
+		_, err = airports.Column("city", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dictionary has no column 'city' (has iata, name, passengers)")
+	})
+
+	t.Run("text files hold one value per line", func(t *testing.T) {
+		codes, err := generator.NewDictionary(strings.NewReader("A00.0\r\nA00.1\n\nB01.9\n"), &schema.Dictionary{File: "icd10.txt"})
+		require.NoError(t, err)
+		require.Equal(t, 3, codes.Len())
+		column, err := codes.Column("", "diagnosis")
+		require.NoError(t, err)
+		assert.Equal(t, "A00.1", codes.Value(1, column))
+
+		_, err = generator.NewDictionary(strings.NewReader("\n"), &schema.Dictionary{File: "empty.txt"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dictionary has no values")
+	})
+
+	t.Run("weighted draws with replacement", func(t *testing.T) {
+		const samples = 20000
+		sampler, err := generator.NewDictionarySampler(airports, "passengers", true)
+		require.NoError(t, err)
+
+		ctx := generator.NewContextWithSeed(50)
+		counts := make([]int, airports.Len())
+		for i := 0; i < samples; i++ {
+			row, err := sampler.Draw(ctx)
+			require.NoError(t, err)
+			counts[row]++
+		}
+		assert.InDelta(t, 0.5, float64(counts[0])/samples, 0.02)
+		assert.InDelta(t, 0.3, float64(counts[1])/samples, 0.02)
+		assert.InDelta(t, 0.2, float64(counts[2])/samples, 0.02)
+		assert.Zero(t, counts[3], "rows of zero weight are never drawn")
+	})
+
+	t.Run("draws without replacement use each row once", func(t *testing.T) {
+		for _, weight := range []string{"", "passengers"} {
+			sampler, err := generator.NewDictionarySampler(airports, weight, false)
+			require.NoError(t, err)
+
+			ctx := generator.NewContextWithSeed(50)
+			seen := make(map[int]bool)
+			rows := airports.Len()
+			if weight != "" {
+				rows-- // the row of zero weight
+			}
+			for i := 0; i < rows; i++ {
+				row, err := sampler.Draw(ctx)
+				require.NoError(t, err)
+				assert.False(t, seen[row], "row %d drawn twice", row)
+				seen[row] = true
+			}
+			_, err = sampler.Draw(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "dictionary rows are used up")
+		}
+	})
+
+	t.Run("invalid weights", func(t *testing.T) {
+		_, err := generator.NewDictionarySampler(airports, "name", true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dictionary weight 'name' of row 1 must be a number of at least 0")
+
+		zero, err := generator.NewDictionary(strings.NewReader("code,weight\nA,0\n"), &schema.Dictionary{File: "zero.csv"})
+		require.NoError(t, err)
+		_, err = generator.NewDictionarySampler(zero, "weight", true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "needs a positive weight")
+	})
+}
@@ -0,0 +1,115 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDistribution(t *testing.T, source string) *generator.DistributionGenerator {
+	t.Helper()
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(source), &raw))
+	config, err := schema.ParseDistribution(raw)
+	require.NoError(t, err)
+	return generator.NewDistributionGenerator(config)
+}
+
+func TestEmpiricalDistribution(t *testing.T) {
+	const samples = 20000
+
+	t.Run("bins are drawn by weight", func(t *testing.T) {
+		gen := newDistribution(t, `{"type": "empirical", "bins": [
+			{"min": 3, "max": 3, "weight": 0.5},
+			{"min": 10, "max": 20, "weight": 0.3},
+			{"min": 100, "max": 200, "weight": 0.2}
+		]}`)
+
+		ctx := generator.NewContextWithSeed(48)
+		counts := make(map[string]int)
+		for i := 0; i < samples; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			switch v := val.(float64); {
+			case v == 3:
+				counts["point"]++
+			case v >= 10 && v <= 20:
+				counts["low"]++
+			case v >= 100 && v <= 200:
+				counts["high"]++
+			default:
+				t.Fatalf("value %g outside every bin", v)
+			}
+		}
+		assert.InDelta(t, 0.5, float64(counts["point"])/samples, 0.02)
+		assert.InDelta(t, 0.3, float64(counts["low"])/samples, 0.02)
+		assert.InDelta(t, 0.2, float64(counts["high"])/samples, 0.02)
+	})
+
+	t.Run("observed values are interpolated", func(t *testing.T) {
+		gen := newDistribution(t, `{"type": "empirical", "values": [5, 1, 3]}`)
+
+		ctx := generator.NewContextWithSeed(48)
+		below := 0
+		for i := 0; i < samples; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			v := val.(float64)
+			assert.True(t, v >= 1 && v <= 5)
+			if v < 3 {
+				below++
+			}
+		}
+		assert.InDelta(t, 0.5, float64(below)/samples, 0.02)
+	})
+
+	t.Run("text shares, random strings of observed lengths and nulls", func(t *testing.T) {
+		gen := newDistribution(t, `{"type": "empirical", "null_rate": 0.1,
+			"weights": {"gold": 0.2, "silver": 0.3},
+			"length": {"type": "empirical", "bins": [{"min": 4, "max": 4, "weight": 1}]}}`)
+
+		ctx := generator.NewContextWithSeed(48)
+		counts := make(map[string]int)
+		for i := 0; i < samples; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			switch v := val.(type) {
+			case nil:
+				counts["null"]++
+			case string:
+				if v == "gold" || v == "silver" {
+					counts[v]++
+				} else {
+					assert.Len(t, v, 4)
+					counts["other"]++
+				}
+			}
+		}
+		assert.InDelta(t, 0.1, float64(counts["null"])/samples, 0.02)
+		assert.InDelta(t, 0.9*0.2, float64(counts["gold"])/samples, 0.02)
+		assert.InDelta(t, 0.9*0.3, float64(counts["silver"])/samples, 0.02)
+		assert.InDelta(t, 0.9*0.5, float64(counts["other"])/samples, 0.02)
+	})
+
+	t.Run("invalid empirical distributions", func(t *testing.T) {
+		for source, message := range map[string]string{
+			`{"type": "empirical"}`: "empirical distribution needs 'values', 'bins' or 'weights'",
+			`{"type": "empirical", "values": [1], "weights": {"a": 1}}`:                                              "not both",
+			`{"type": "empirical", "bins": [{"min": 5, "max": 1, "weight": 1}]}`:                                     "empirical bin [5, 1] needs 'min' up to 'max'",
+			`{"type": "empirical", "bins": [{"min": 1, "max": 5}]}`:                                                  "numeric 'min', 'max' and 'weight'",
+			`{"type": "empirical", "weights": {"a": 0.7, "b": 0.6}, "length": {"type": "empirical", "values": [3]}}`: "add up to 1.3, more than 1",
+			`{"type": "empirical", "weights": {"a": 1}, "length": {"type": "normal", "mean": 5, "std_dev": 1}}`:      "'length' must be an empirical distribution of numbers",
+			`{"type": "empirical", "values": [1], "null_rate": 2}`:                                                   "'null_rate' must be a number between 0 and 1",
+		} {
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(source), &raw))
+			_, err := schema.ParseDistribution(raw)
+			require.Error(t, err, source)
+			assert.Contains(t, err.Error(), message, source)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleGenerator(t *testing.T) {
+	var lifecycle schema.Lifecycle
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"start": "accounts.opened_at", "initial": "trial",
+		"states": {
+			"trial": {"timestamp": "trial_at", "transitions": {"active": 0.6, "churned": 0.3}, "dwell": {"type": "uniform", "min": 24, "max": 48}},
+			"active": {"timestamp": "active_at", "transitions": {"churned": 0.5}, "dwell": {"type": "empirical", "values": [100, 200]}},
+			"churned": {"timestamp": "churned_at"}
+		}
+	}`), &lifecycle))
+	gen, err := generator.NewLifecycleGenerator(&lifecycle)
+	require.NoError(t, err)
+
+	opened := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := generator.NewContextWithSeed(46)
+	ctx.ParentValue = func(table, column string) (interface{}, bool) {
+		return opened, table == "accounts" && column == "opened_at"
+	}
+
+	t.Run("walks the transitions", func(t *testing.T) {
+		counts := map[string]int{}
+		for i := 0; i < 5000; i++ {
+			state, timestamps, err := gen.Walk(ctx)
+			require.NoError(t, err)
+			counts[state]++
+
+			assert.Equal(t, opened, timestamps["trial_at"])
+			switch state {
+			case "trial":
+				assert.Nil(t, timestamps["active_at"])
+				assert.Nil(t, timestamps["churned_at"])
+			case "active":
+				active := timestamps["active_at"].(time.Time)
+				assert.True(t, !active.Before(opened.Add(24*time.Hour)) && !active.After(opened.Add(48*time.Hour)))
+				assert.Nil(t, timestamps["churned_at"])
+			case "churned":
+				churned := timestamps["churned_at"].(time.Time)
+				if active, ok := timestamps["active_at"].(time.Time); ok {
+					hours := churned.Sub(active).Hours()
+					assert.True(t, hours >= 100 && hours <= 200, "active for %v hours", hours)
+				}
+			}
+		}
+		// trial 10%, active 60% * 50%, churned 30% + 60% * 50%
+		assert.InDelta(t, 500, counts["trial"], 70)
+		assert.InDelta(t, 1500, counts["active"], 120)
+		assert.InDelta(t, 3000, counts["churned"], 120)
+	})
+
+	t.Run("without a start time", func(t *testing.T) {
+		ctx := generator.NewContextWithSeed(1)
+		state, timestamps, err := gen.Walk(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, []string{"trial", "active", "churned"}, state)
+		assert.Equal(t, map[string]interface{}{"trial_at": nil, "active_at": nil, "churned_at": nil}, timestamps)
+	})
+
+	t.Run("start must be a timestamp", func(t *testing.T) {
+		ctx := generator.NewContextWithSeed(1)
+		ctx.ParentValue = func(table, column string) (interface{}, bool) { return "yesterday", true }
+		_, _, err := gen.Walk(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle start accounts.opened_at is not a timestamp")
+	})
+}
@@ -0,0 +1,110 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRules(t *testing.T, source string, base generator.Generator) *generator.RulesGenerator {
+	t.Helper()
+	var rules []*schema.BusinessRule
+	require.NoError(t, json.Unmarshal([]byte(source), &rules))
+	gen, err := generator.NewRulesGenerator(rules, base)
+	require.NoError(t, err)
+	return gen
+}
+
+func TestRulesGenerator(t *testing.T) {
+	t.Run("first matching rule applies", func(t *testing.T) {
+		gen := newRules(t, `[
+			{"if": {"amount": {"gte": 1000}}, "then": {"value": "manual"}},
+			{"if": {"amount": {"gte": 100}}, "then": {"value": "review"}}
+		]`, generator.NewBooleanGenerator())
+
+		ctx := generator.NewContextWithSeed(1)
+		for amount, expected := range map[int]interface{}{5000: "manual", 250: "review"} {
+			ctx.RowData = map[string]interface{}{"amount": amount}
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, expected, val)
+		}
+
+		ctx.RowData = map[string]interface{}{"amount": 10}
+		val, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		assert.IsType(t, true, val, "falls back to the base generator")
+
+		_, applied, err := gen.Apply(ctx)
+		require.NoError(t, err)
+		assert.False(t, applied)
+	})
+
+	t.Run("else applies when the condition fails", func(t *testing.T) {
+		gen := newRules(t, `[{"if": {"country": {"in": ["US", "CA"]}}, "then": {"value": "domestic"}, "else": {"value": "international"}}]`, nil)
+
+		ctx := generator.NewContextWithSeed(1)
+		ctx.RowData = map[string]interface{}{"country": "FR"}
+		val, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "international", val)
+	})
+
+	t.Run("conditions on parent rows", func(t *testing.T) {
+		gen := newRules(t, `[{"if": {"customers.tier": "gold"}, "then": {"value": 0}, "else": {"value": 4.99}}]`, nil)
+
+		ctx := generator.NewContextWithSeed(1)
+		ctx.RowData = map[string]interface{}{}
+		ctx.ParentValue = func(table, column string) (interface{}, bool) {
+			if table == "customers" && column == "tier" {
+				return "gold", true
+			}
+			return nil, false
+		}
+		val, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, val)
+
+		ctx.ParentValue = nil
+		val, err = gen.Generate(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4.99, val, "a missing parent is NULL")
+	})
+
+	t.Run("distribution and weighted actions", func(t *testing.T) {
+		gen := newRules(t, `[
+			{"if": {"segment": "b2b"}, "then": {"distribution": {"type": "normal", "mean": 500, "std_dev": 50, "min": 0}}},
+			{"if": {}, "then": {"weights": {"card": 3, "paypal": 1}}}
+		]`, nil)
+
+		ctx := generator.NewContextWithSeed(7)
+		ctx.RowData = map[string]interface{}{"segment": "b2b"}
+		total := 0.0
+		for i := 0; i < 200; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			total += val.(float64)
+		}
+		assert.InDelta(t, 500, total/200, 15)
+
+		ctx.RowData = map[string]interface{}{"segment": "consumer"}
+		counts := map[interface{}]int{}
+		for i := 0; i < 400; i++ {
+			val, err := gen.Generate(ctx)
+			require.NoError(t, err)
+			counts[val]++
+		}
+		assert.Len(t, counts, 2)
+		assert.Greater(t, counts["card"], counts["paypal"]*2)
+	})
+
+	t.Run("rejects invalid conditions", func(t *testing.T) {
+		_, err := generator.NewRulesGenerator([]*schema.BusinessRule{{Condition: map[string]interface{}{"amount": map[string]interface{}{"over": 5}}}}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rule 1: invalid condition")
+	})
+}
@@ -0,0 +1,60 @@
+package generator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeBounds(t *testing.T) {
+	after := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	generators := map[string]generator.Generator{
+		"created_at": generator.NewCreatedAtGenerator(),
+		"updated_at": generator.NewUpdatedAtGenerator(),
+		"timestamp":  generator.NewTimestampGenerator(),
+		"timeseries": generator.NewTimeSeriesGenerator(
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), time.Hour, "uniform"),
+	}
+
+	for name, gen := range generators {
+		t.Run(name+" stays within the bounds", func(t *testing.T) {
+			ctx := generator.NewContextWithSeed(42)
+			ctx.TimeBounds = &generator.TimeBounds{After: after, Before: after.Add(48 * time.Hour)}
+
+			for i := 0; i < 100; i++ {
+				val, err := gen.Generate(ctx)
+				require.NoError(t, err)
+				ts, ok := val.(time.Time)
+				require.True(t, ok)
+				assert.True(t, ctx.TimeBounds.Contains(ts), "%s outside bounds", ts)
+			}
+		})
+
+		t.Run(name+" follows an open-ended lower bound", func(t *testing.T) {
+			ctx := generator.NewContextWithSeed(7)
+			ctx.TimeBounds = &generator.TimeBounds{After: after}
+
+			for i := 0; i < 100; i++ {
+				val, err := gen.Generate(ctx)
+				require.NoError(t, err)
+				assert.False(t, val.(time.Time).Before(after))
+			}
+		})
+	}
+
+	t.Run("range extends an open side by the window", func(t *testing.T) {
+		bounds := &generator.TimeBounds{After: after}
+		start, end := bounds.Range(24 * time.Hour)
+		assert.Equal(t, after, start)
+		assert.Equal(t, after.Add(24*time.Hour), end)
+
+		bounds = &generator.TimeBounds{Before: after}
+		start, end = bounds.Range(24 * time.Hour)
+		assert.Equal(t, after.Add(-24*time.Hour), start)
+		assert.Equal(t, after, end)
+	})
+}
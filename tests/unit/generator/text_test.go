@@ -0,0 +1,136 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const textCorpus = `The cat sat on the mat. The dog sat on the rug!
+The cat chased the dog. Did the dog like the cat? The dog did not.`
+
+func newText(t *testing.T, columnType string, config map[string]interface{}) *generator.StructuredTextGenerator {
+	t.Helper()
+	config["type"] = "text"
+	textConfig, err := (&schema.Column{Name: "body", Type: columnType, GeneratorConfig: config}).Text()
+	require.NoError(t, err)
+
+	var chain *generator.MarkovChain
+	if textConfig.Mode == "markov" {
+		chain, err = generator.NewMarkovChain(strings.NewReader(textCorpus), textConfig.Order)
+		require.NoError(t, err)
+	}
+	gen, err := generator.NewStructuredTextGenerator(textConfig, chain)
+	require.NoError(t, err)
+	return gen
+}
+
+func generateTexts(t *testing.T, gen *generator.StructuredTextGenerator, seed int64, n int) []string {
+	t.Helper()
+	ctx := generator.NewContextWithSeed(seed)
+	texts := make([]string, n)
+	for i := range texts {
+		val, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		texts[i] = val.(string)
+	}
+	return texts
+}
+
+func TestStructuredTextGenerator(t *testing.T) {
+	t.Run("words, sentences and paragraphs", func(t *testing.T) {
+		for _, text := range generateTexts(t, newText(t, "text", map[string]interface{}{
+			"mode": "words", "count": map[string]interface{}{"min": 2.0, "max": 4.0},
+		}), 49, 50) {
+			words := strings.Fields(text)
+			assert.True(t, len(words) >= 2 && len(words) <= 4, text)
+			assert.Equal(t, strings.ToLower(text), text)
+		}
+
+		for _, text := range generateTexts(t, newText(t, "text", map[string]interface{}{
+			"mode": "sentences", "count": 3.0, "words": 5.0,
+		}), 49, 50) {
+			assert.Equal(t, 3, strings.Count(text, "."), text)
+			assert.Len(t, strings.Fields(text), 15, text)
+			assert.Equal(t, strings.ToUpper(text[:1]), text[:1])
+		}
+
+		for _, text := range generateTexts(t, newText(t, "text", map[string]interface{}{
+			"mode": "paragraphs", "count": 2.0, "sentences": 4.0,
+		}), 49, 50) {
+			paragraphs := strings.Split(text, "\n\n")
+			require.Len(t, paragraphs, 2, text)
+			for _, p := range paragraphs {
+				assert.Equal(t, 4, strings.Count(p, "."), p)
+			}
+		}
+	})
+
+	t.Run("markov text follows the corpus", func(t *testing.T) {
+		followers := map[string]map[string]bool{}
+		words := strings.Fields(textCorpus)
+		for i := 0; i+1 < len(words); i++ {
+			if followers[words[i]] == nil {
+				followers[words[i]] = map[string]bool{}
+			}
+			followers[words[i]][words[i+1]] = true
+		}
+
+		gen := newText(t, "text", map[string]interface{}{"mode": "markov", "corpus": "corpus.txt", "order": 1.0, "count": 2.0})
+		for _, text := range generateTexts(t, gen, 49, 100) {
+			sentenceWords := strings.Fields(text)
+			assert.Contains(t, []string{"The", "Did"}, sentenceWords[0], text)
+			for i := 0; i+1 < len(sentenceWords); i++ {
+				if !strings.ContainsAny(sentenceWords[i], ".!?") {
+					assert.True(t, followers[sentenceWords[i]][sentenceWords[i+1]], "%q then %q in %q", sentenceWords[i], sentenceWords[i+1], text)
+				}
+			}
+		}
+
+		_, err := generator.NewMarkovChain(strings.NewReader("  \n "), 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "corpus has no text")
+	})
+
+	t.Run("domain text", func(t *testing.T) {
+		for _, title := range generateTexts(t, newText(t, "varchar(200)", map[string]interface{}{"mode": "product_title"}), 49, 20) {
+			assert.True(t, len(strings.Fields(title)) >= 3, title)
+		}
+
+		positive := map[string]interface{}{"positive": 1.0}
+		for _, review := range generateTexts(t, newText(t, "text", map[string]interface{}{"mode": "review", "sentiment": positive, "count": 2.0}), 49, 50) {
+			assert.NotContains(t, review, "{")
+			assert.NotContains(t, review, "disappointed")
+			assert.NotContains(t, review, "Not worth")
+		}
+
+		for _, ticket := range generateTexts(t, newText(t, "text", map[string]interface{}{"mode": "support_ticket"}), 49, 20) {
+			lines := strings.Split(ticket, "\n")
+			require.Len(t, lines, 4, ticket)
+			assert.True(t, strings.HasSuffix(lines[0], ","), ticket)
+			assert.NotContains(t, ticket, "{")
+		}
+	})
+
+	t.Run("fits the column and is deterministic", func(t *testing.T) {
+		gen := newText(t, "varchar(30)", map[string]interface{}{"mode": "paragraphs"})
+		texts := generateTexts(t, gen, 49, 100)
+		for _, text := range texts {
+			assert.LessOrEqual(t, utf8.RuneCountInString(text), 30, text)
+			assert.Equal(t, strings.TrimSpace(text), text)
+			assert.False(t, strings.HasSuffix(text, ","), text)
+		}
+		assert.Equal(t, texts, generateTexts(t, gen, 49, 100))
+		assert.NotEqual(t, texts, generateTexts(t, gen, 50, 100))
+
+		limited := newText(t, "text", map[string]interface{}{"mode": "support_ticket", "max_length": 25.0})
+		for _, text := range generateTexts(t, limited, 49, 20) {
+			assert.LessOrEqual(t, utf8.RuneCountInString(text), 25, text)
+		}
+	})
+}
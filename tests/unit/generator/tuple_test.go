@@ -0,0 +1,116 @@
+package generator_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/generator"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTuple(t *testing.T, tuple *schema.Tuple) generator.TupleGenerator {
+	t.Helper()
+	gen, err := generator.NewTupleGenerator(tuple)
+	require.NoError(t, err)
+	return gen
+}
+
+// luhnValid reports whether a card number passes the Luhn check
+func luhnValid(number string) bool {
+	sum := 0
+	for i := range number {
+		d := int(number[len(number)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func TestTupleGenerators(t *testing.T) {
+	ctx := generator.NewContextWithSeed(47)
+
+	t.Run("address parts belong together", func(t *testing.T) {
+		gen := newTuple(t, &schema.Tuple{Generator: "address", Countries: []string{"FR", "jp"}})
+		cities := map[string]string{"Paris": "FR", "Lyon": "FR", "Marseille": "FR", "Tokyo": "JP", "Osaka": "JP", "Nagoya": "JP"}
+		for i := 0; i < 200; i++ {
+			address, err := gen.GenerateTuple(ctx)
+			require.NoError(t, err)
+
+			code := address["country_code"].(string)
+			assert.Equal(t, code, cities[address["city"].(string)], "city %v", address["city"])
+			switch code {
+			case "FR":
+				assert.Equal(t, "France", address["country"])
+				assert.Regexp(t, `^\d{5}$`, address["postal_code"])
+				assert.InDelta(t, 46, address["latitude"], 4)
+				assert.Regexp(t, `^\d+ \D+$`, address["street"])
+			case "JP":
+				assert.Equal(t, "Japan", address["country"])
+				assert.Regexp(t, `^\d{3}-\d{4}$`, address["postal_code"])
+				assert.InDelta(t, 136, address["longitude"], 4)
+			}
+			if address["city"] == "Paris" {
+				assert.True(t, strings.HasPrefix(address["postal_code"].(string), "750"))
+				assert.Equal(t, "Île-de-France", address["state"])
+			}
+		}
+	})
+
+	t.Run("unknown countries", func(t *testing.T) {
+		_, err := generator.NewTupleGenerator(&schema.Tuple{Generator: "geo", Countries: []string{"XX"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no address data for country "XX"`)
+	})
+
+	t.Run("person names, gender and email agree", func(t *testing.T) {
+		gen := newTuple(t, &schema.Tuple{Generator: "person"})
+		genders := map[string]bool{}
+		for i := 0; i < 200; i++ {
+			person, err := gen.GenerateTuple(ctx)
+			require.NoError(t, err)
+
+			first, last := person["first_name"].(string), person["last_name"].(string)
+			assert.Equal(t, first+" "+last, person["full_name"])
+			assert.Regexp(t, "^"+regexp.QuoteMeta(strings.ToLower(first))+`\.[a-z]+\d*@[a-z.]+$`, person["email"])
+			assert.True(t, strings.HasPrefix(person["username"].(string), strings.ToLower(first[:1])))
+			genders[person["gender"].(string)] = true
+		}
+		assert.Equal(t, map[string]bool{"male": true, "female": true}, genders)
+	})
+
+	t.Run("card numbers are valid for their brand", func(t *testing.T) {
+		gen := newTuple(t, &schema.Tuple{Generator: "credit_card"})
+		brands := map[string]string{
+			"Visa":             `^4\d{15}$`,
+			"Mastercard":       `^5[1-5]\d{14}$`,
+			"American Express": `^3[47]\d{13}$`,
+			"Discover":         `^6(011|5)\d+$`,
+		}
+		for i := 0; i < 200; i++ {
+			card, err := gen.GenerateTuple(ctx)
+			require.NoError(t, err)
+
+			number := card["number"].(string)
+			assert.Regexp(t, brands[card["brand"].(string)], number)
+			assert.True(t, luhnValid(number), number)
+
+			expiry := card["expiry_date"].(time.Time)
+			assert.Equal(t, expiry.Format("01/06"), card["expiry"])
+			assert.Equal(t, 1, expiry.AddDate(0, 0, 1).Day(), "valid until the end of the month")
+			if card["brand"] == "American Express" {
+				assert.Len(t, card["cvv"], 4)
+			} else {
+				assert.Len(t, card["cvv"], 3)
+			}
+		}
+	})
+}
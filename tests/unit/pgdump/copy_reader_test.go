@@ -0,0 +1,55 @@
+package pgdump_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCopySection(t *testing.T) {
+	dump := strings.Join([]string{
+		"COPY users (id, name) FROM stdin;",
+		"1\tada",
+		"\\.",
+		"",
+		`COPY sales."Order Items" (id, "Note", total) FROM stdin;`,
+		"1\tfirst\\tline\\nsecond \\\\ line\t12.50",
+		"2\t\\N\t3",
+		"\\.",
+	}, "\n")
+
+	t.Run("reads the rows of a table, with or without its schema", func(t *testing.T) {
+		for _, name := range []string{"Order Items", "sales.Order Items"} {
+			columns, rows, err := pgdump.ReadCopySection(strings.NewReader(dump), name)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"id", "Note", "total"}, columns)
+			assert.Equal(t, [][]interface{}{
+				{"1", "first\tline\nsecond \\ line", "12.50"},
+				{"2", nil, "3"},
+			}, rows)
+		}
+	})
+
+	t.Run("parses what FormatCopyRow writes", func(t *testing.T) {
+		row := map[string]interface{}{"a": "tab\there", "b": nil, "c": `back\slash`}
+		line := pgdump.FormatCopyRow([]string{"a", "b", "c"}, row)
+		assert.Equal(t, []interface{}{"tab\there", nil, `back\slash`}, pgdump.ParseCopyRow(line))
+	})
+
+	t.Run("missing and unterminated sections", func(t *testing.T) {
+		_, _, err := pgdump.ReadCopySection(strings.NewReader(dump), "orders")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no COPY section for table orders")
+
+		_, _, err = pgdump.ReadCopySection(strings.NewReader("COPY users (id) FROM stdin;\n1\n"), "users")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is not terminated")
+
+		_, _, err = pgdump.ReadCopySection(strings.NewReader("COPY users (id) FROM stdin;\n1\t2\n\\.\n"), "users")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "COPY row 1 of users has 2 values, expected 1")
+	})
+}
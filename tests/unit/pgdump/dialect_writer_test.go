@@ -0,0 +1,154 @@
+package pgdump_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dialectTestTable() *schema.Table {
+	return &schema.Table{
+		Columns: []*schema.Column{
+			{Name: "id", Type: "serial"},
+			{Name: "email", Type: "varchar(255)"},
+			{Name: "active", Type: "boolean", DefaultValue: "true"},
+			{Name: "profile", Type: "jsonb", Nullable: true},
+			{Name: "tags", Type: "text[]", Nullable: true},
+			{Name: "created_at", Type: "timestamptz", DefaultValue: "now()"},
+			{Name: "location", Type: "point", Nullable: true},
+		},
+		PrimaryKey:        []string{"id"},
+		UniqueConstraints: []*schema.UniqueConstraint{{Columns: []string{"email"}, Name: "users_email_key"}},
+		Indexes:           []*schema.Index{{Columns: []string{"profile"}, Name: "users_profile_idx", Type: "gin"}},
+	}
+}
+
+func TestDialectWriter(t *testing.T) {
+	t.Run("postgres returns the SQL writer", func(t *testing.T) {
+		writer, err := pgdump.NewDialectWriter(new(bytes.Buffer), "postgres")
+		require.NoError(t, err)
+		assert.IsType(t, &pgdump.SQLWriter{}, writer)
+	})
+
+	t.Run("reject unknown dialect", func(t *testing.T) {
+		_, err := pgdump.NewDialectWriter(new(bytes.Buffer), "oracle")
+		assert.Error(t, err)
+	})
+
+	t.Run("translate DDL to MySQL", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer, err := pgdump.NewDialectWriter(buf, "mysql")
+		require.NoError(t, err)
+
+		s := &schema.Schema{
+			Database: schema.DatabaseConfig{Name: "app"},
+			Tables:   map[string]*schema.Table{"users": dialectTestTable()},
+		}
+		require.NoError(t, writer.WriteSchema(s))
+
+		output := buf.String()
+		assert.Contains(t, output, "CREATE DATABASE IF NOT EXISTS `app`")
+		assert.Contains(t, output, "USE `app`;")
+		assert.NotContains(t, output, "\\connect")
+		assert.Contains(t, output, "`id` INT AUTO_INCREMENT NOT NULL")
+		assert.Contains(t, output, "`email` VARCHAR(255) NOT NULL")
+		assert.Contains(t, output, "`active` BOOLEAN NOT NULL DEFAULT TRUE")
+		assert.Contains(t, output, "`profile` JSON")
+		assert.Contains(t, output, "`tags` JSON")
+		assert.Contains(t, output, "`created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)")
+		assert.Contains(t, output, "PRIMARY KEY (`id`)")
+		assert.Contains(t, output, "CONSTRAINT `users_email_key` UNIQUE (`email`)")
+
+		// Unsupported constructs become warnings rather than silent mistranslations
+		warnings := writer.(*pgdump.DialectWriter).Warnings()
+		joined := strings.Join(warnings, "\n")
+		assert.Contains(t, joined, "time zone")
+		assert.Contains(t, joined, "point has no MySQL equivalent")
+		assert.Contains(t, joined, "gin indexes are not supported by MySQL")
+		assert.Contains(t, output, "-- WARNING:")
+	})
+
+	t.Run("skip indexes MySQL cannot create", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer, err := pgdump.NewDialectWriter(buf, "mysql")
+		require.NoError(t, err)
+
+		table := dialectTestTable()
+		table.Indexes = []*schema.Index{
+			{Columns: []string{"profile"}, Name: "users_profile_idx", Type: "gin"},
+			{Columns: []string{"profile"}, Name: "users_profile_btree_idx"},
+			{Columns: []string{"email", "created_at"}, Name: "users_email_created_idx", Type: "hash"},
+		}
+		s := &schema.Schema{
+			Database: schema.DatabaseConfig{Name: "app"},
+			Tables:   map[string]*schema.Table{"users": table},
+		}
+		require.NoError(t, writer.WriteSchema(s))
+
+		output := buf.String()
+		assert.NotContains(t, output, "CREATE INDEX `users_profile_idx`")
+		assert.NotContains(t, output, "CREATE INDEX `users_profile_btree_idx`")
+		assert.Contains(t, output, "CREATE INDEX `users_email_created_idx` ON `users` (`email`, `created_at`);")
+
+		warnings := writer.(*pgdump.DialectWriter).Warnings()
+		assert.Contains(t, warnings, "table users: index users_profile_idx: gin indexes are not supported by MySQL and were skipped")
+		assert.Contains(t, warnings, "table users: index users_profile_btree_idx: column profile is stored as JSON, which MySQL cannot index; index skipped")
+		assert.Contains(t, warnings, "table users: index users_email_created_idx: hash index created as a regular index")
+	})
+
+	t.Run("translate DDL to SQLite", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer, err := pgdump.NewDialectWriter(buf, "sqlite")
+		require.NoError(t, err)
+
+		s := &schema.Schema{
+			Database: schema.DatabaseConfig{Name: "app"},
+			Tables:   map[string]*schema.Table{"users": dialectTestTable()},
+		}
+		require.NoError(t, writer.WriteSchema(s))
+
+		output := buf.String()
+		assert.NotContains(t, output, "CREATE DATABASE")
+		assert.Contains(t, output, `"id" INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL`)
+		assert.NotContains(t, output, "PRIMARY KEY (")
+		assert.Contains(t, output, `"active" INTEGER NOT NULL DEFAULT 1`)
+		assert.Contains(t, output, `"profile" TEXT`)
+		assert.Contains(t, output, `CONSTRAINT "users_email_key" UNIQUE ("email")`)
+		assert.Contains(t, output, `CREATE INDEX "users_profile_idx" ON "users" ("profile");`)
+	})
+
+	t.Run("escape values for MySQL", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer, err := pgdump.NewDialectWriter(buf, "mysql")
+		require.NoError(t, err)
+
+		row := map[string]interface{}{
+			"email": `o'brien\x@example.com`,
+			"tags":  []interface{}{"a", "b"},
+		}
+		require.NoError(t, writer.(pgdump.RowWriter).WriteInsert("users", []string{"email", "tags"}, row))
+
+		assert.Equal(t, "INSERT INTO `users` (`email`, `tags`) VALUES ('o''brien\\\\x@example.com', '[\"a\",\"b\"]');\n", buf.String())
+	})
+
+	t.Run("escape values for SQLite", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer, err := pgdump.NewDialectWriter(buf, "sqlite")
+		require.NoError(t, err)
+
+		row := map[string]interface{}{
+			"email":      `o'brien\x@example.com`,
+			"active":     true,
+			"created_at": time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		}
+		require.NoError(t, writer.(pgdump.RowWriter).WriteInsert("users", []string{"email", "active", "created_at"}, row))
+
+		assert.Equal(t, `INSERT INTO "users" ("email", "active", "created_at") VALUES ('o''brien\x@example.com', 1, '2024-01-15 10:30:00');`+"\n", buf.String())
+	})
+}
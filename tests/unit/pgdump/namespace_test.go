@@ -0,0 +1,130 @@
+package pgdump_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func namespaceTestSchema() *schema.Schema {
+	createDatabase := false
+	return &schema.Schema{
+		Database: schema.DatabaseConfig{Name: "app", Encoding: "UTF8", CreateDatabase: &createDatabase, Schema: "staging"},
+		Tables: map[string]*schema.Table{
+			"users": {
+				Columns:    []*schema.Column{{Name: "id", Type: "serial"}},
+				PrimaryKey: []string{"id"},
+			},
+			"billing.invoices": {
+				Columns: []*schema.Column{{Name: "id", Type: "serial"}},
+			},
+			"events": {
+				Columns: []*schema.Column{{Name: "id", Type: "bigserial"}},
+				Schema:  "audit",
+			},
+		},
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	t.Run("resolve table namespaces", func(t *testing.T) {
+		s := namespaceTestSchema()
+
+		ns, name := s.QualifiedTable("users")
+		assert.Equal(t, "staging", ns)
+		assert.Equal(t, "users", name)
+
+		ns, name = s.QualifiedTable("billing.invoices")
+		assert.Equal(t, "billing", ns)
+		assert.Equal(t, "invoices", name)
+
+		ns, _ = s.QualifiedTable("events")
+		assert.Equal(t, "audit", ns)
+
+		assert.Equal(t, []string{"audit", "billing", "staging"}, s.Namespaces())
+	})
+
+	t.Run("qualified keys are not qualified twice", func(t *testing.T) {
+		s := namespaceTestSchema()
+		s.Tables["sales.orders"] = &schema.Table{Columns: []*schema.Column{{Name: "id", Type: "serial"}}, Schema: "sales"}
+
+		ns, name := s.QualifiedTable("sales.orders")
+		assert.Equal(t, "sales", ns)
+		assert.Equal(t, "orders", name)
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, pgdump.NewSQLWriter(buf).WriteSchema(s))
+		assert.Contains(t, buf.String(), "CREATE TABLE sales.orders (")
+		assert.NotContains(t, buf.String(), "sales.sales")
+	})
+
+	t.Run("SQL writer skips the database preamble and qualifies tables", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := pgdump.NewSQLWriter(buf)
+		require.NoError(t, writer.WriteSchema(namespaceTestSchema()))
+		require.NoError(t, writer.WriteInsert("billing.invoices", []string{"id"}, map[string]interface{}{"id": 1}))
+
+		output := buf.String()
+		assert.NotContains(t, output, "CREATE DATABASE")
+		assert.NotContains(t, output, "\\connect")
+		assert.Contains(t, output, "CREATE SCHEMA IF NOT EXISTS audit;\nCREATE SCHEMA IF NOT EXISTS billing;\nCREATE SCHEMA IF NOT EXISTS staging;\n")
+		assert.Contains(t, output, "SET search_path TO staging, public;")
+		assert.Contains(t, output, "CREATE TABLE staging.users (")
+		assert.Contains(t, output, "CREATE TABLE billing.invoices (")
+		assert.Contains(t, output, "CREATE TABLE audit.events (")
+		assert.Contains(t, output, "INSERT INTO billing.invoices (id) VALUES (1);")
+	})
+
+	t.Run("COPY writer qualifies tables", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := pgdump.NewCOPYWriter(buf)
+		require.NoError(t, writer.WriteSchema(namespaceTestSchema()))
+		require.NoError(t, writer.WriteCopyHeader("events", []string{"id"}))
+
+		output := buf.String()
+		assert.NotContains(t, output, "CREATE DATABASE")
+		assert.Contains(t, output, "COPY audit.events (id) FROM stdin;")
+	})
+
+	t.Run("data-only header sets search_path without creating schemas", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := pgdump.NewSQLWriter(buf)
+		require.NoError(t, writer.WriteDataHeader(namespaceTestSchema()))
+
+		output := buf.String()
+		assert.NotContains(t, output, "CREATE SCHEMA")
+		assert.Contains(t, output, "SET search_path TO staging, public;")
+	})
+
+	t.Run("default schema keeps the preamble and unqualified names", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer := pgdump.NewSQLWriter(buf)
+		s := &schema.Schema{
+			Database: schema.DatabaseConfig{Name: "app", Encoding: "UTF8"},
+			Tables:   map[string]*schema.Table{"users": {Columns: []*schema.Column{{Name: "id", Type: "serial"}}}},
+		}
+		require.NoError(t, writer.WriteSchema(s))
+
+		output := buf.String()
+		assert.Contains(t, output, "CREATE DATABASE app")
+		assert.Contains(t, output, "\\connect app")
+		assert.NotContains(t, output, "search_path")
+		assert.Contains(t, output, "CREATE TABLE users (")
+	})
+
+	t.Run("MySQL writes unqualified names with a warning", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writer, err := pgdump.NewDialectWriter(buf, "mysql")
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteSchema(namespaceTestSchema()))
+
+		output := buf.String()
+		assert.NotContains(t, output, "CREATE DATABASE")
+		assert.Contains(t, output, "CREATE TABLE `invoices`")
+		assert.Contains(t, writer.(*pgdump.DialectWriter).Warnings()[0], "namespaces")
+	})
+}
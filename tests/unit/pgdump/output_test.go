@@ -0,0 +1,149 @@
+package pgdump_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readDumpFile(t *testing.T, path string) string {
+	t.Helper()
+
+	rc, err := pgdump.OpenDumpFile(path)
+	require.NoError(t, err)
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestOutput(t *testing.T) {
+	t.Run("parse sizes", func(t *testing.T) {
+		cases := map[string]int64{
+			"":      0,
+			"512":   512,
+			"10KB":  10 << 10,
+			"500MB": 500 << 20,
+			"1GB":   1 << 30,
+			"1.5g":  3 << 29,
+		}
+		for input, expected := range cases {
+			size, err := pgdump.ParseSize(input)
+			require.NoError(t, err, input)
+			assert.Equal(t, expected, size, input)
+		}
+
+		_, err := pgdump.ParseSize("lots")
+		assert.Error(t, err)
+	})
+
+	t.Run("detect compression from extension", func(t *testing.T) {
+		assert.Equal(t, "gzip", pgdump.DetectCompression("dump.sql.gz"))
+		assert.Equal(t, "zstd", pgdump.DetectCompression("dump.sql.zst"))
+		assert.Equal(t, "none", pgdump.DetectCompression("dump.sql"))
+	})
+
+	for _, compression := range []string{"gzip", "zstd"} {
+		t.Run("round-trip "+compression, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dump.sql")
+			out, err := pgdump.NewOutput(pgdump.OutputOptions{Path: path, Compression: compression})
+			require.NoError(t, err)
+
+			_, err = io.WriteString(out, "INSERT INTO t VALUES (1);\n")
+			require.NoError(t, err)
+			require.NoError(t, out.Close())
+
+			// The compressed extension is appended so readers can detect it
+			assert.NotEqual(t, path, out.Path())
+			assert.Equal(t, "INSERT INTO t VALUES (1);\n", readDumpFile(t, out.Path()))
+		})
+	}
+
+	t.Run("split by size with index script", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dump.sql.gz")
+		out, err := pgdump.NewOutput(pgdump.OutputOptions{Path: path, SplitSize: 64})
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			_, err := fmt.Fprintf(out, "INSERT INTO t VALUES (%d);\n", i)
+			require.NoError(t, err)
+			if out.NeedsSplit() {
+				require.NoError(t, out.Split())
+			}
+		}
+		require.NoError(t, out.Close())
+
+		parts := out.Parts()
+		require.Greater(t, len(parts), 1)
+		assert.Equal(t, filepath.Join(filepath.Dir(path), "dump-0001.sql.gz"), parts[0])
+
+		indexPath := out.Path()
+		assert.Equal(t, strings.TrimSuffix(path, ".gz"), indexPath)
+		index, err := os.ReadFile(indexPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(index), "\\i dump-0001.sql\n")
+
+		// Index resolution finds the compressed parts and their content is complete
+		resolved, err := pgdump.DumpParts(indexPath)
+		require.NoError(t, err)
+		assert.Equal(t, parts, resolved)
+
+		var all strings.Builder
+		for _, part := range resolved {
+			all.WriteString(readDumpFile(t, part))
+		}
+		assert.Equal(t, 10, strings.Count(all.String(), "INSERT INTO"))
+	})
+
+	t.Run("split per table", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "dump.sql")
+		out, err := pgdump.NewOutput(pgdump.OutputOptions{Path: path, SplitPerTable: true})
+		require.NoError(t, err)
+
+		_, err = io.WriteString(out, "CREATE TABLE a (id int);\n")
+		require.NoError(t, err)
+		for _, table := range []string{"a", "b"} {
+			require.NoError(t, out.BeginTable(table))
+			_, err = fmt.Fprintf(out, "INSERT INTO %s VALUES (1);\n", table)
+			require.NoError(t, err)
+		}
+		require.NoError(t, out.Close())
+
+		require.Len(t, out.Parts(), 3)
+		assert.Equal(t, "INSERT INTO b VALUES (1);\n", readDumpFile(t, out.Parts()[2]))
+
+		resolved, err := pgdump.DumpParts(path)
+		require.NoError(t, err)
+		assert.Equal(t, out.Parts(), resolved)
+	})
+
+	t.Run("reject split to stdout", func(t *testing.T) {
+		_, err := pgdump.NewOutput(pgdump.OutputOptions{Path: "-", SplitPerTable: true})
+		assert.Error(t, err)
+	})
+
+	t.Run("reject unknown compression", func(t *testing.T) {
+		_, err := pgdump.NewOutput(pgdump.OutputOptions{Path: filepath.Join(t.TempDir(), "dump.sql"), Compression: "lz4"})
+		assert.Error(t, err)
+	})
+
+	t.Run("reject a compressed extension with other compression", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := pgdump.NewOutput(pgdump.OutputOptions{Path: filepath.Join(dir, "dump.sql.gz"), Compression: "none"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has a gzip extension but compression is none")
+
+		_, err = pgdump.NewOutput(pgdump.OutputOptions{Path: filepath.Join(dir, "dump.sql.zst"), Compression: "gzip"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has a zstd extension but compression is gzip")
+		assert.NoFileExists(t, filepath.Join(dir, "dump.sql.gz"))
+	})
+}
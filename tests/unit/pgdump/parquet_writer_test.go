@@ -0,0 +1,168 @@
+package pgdump_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pgdump"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openParquet(t *testing.T, path string) *parquet.File {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+
+	info, err := f.Stat()
+	require.NoError(t, err)
+
+	pf, err := parquet.OpenFile(f, info.Size())
+	require.NoError(t, err)
+	return pf
+}
+
+func TestParquetWriter(t *testing.T) {
+	table := &schema.Table{
+		Columns: []*schema.Column{
+			{Name: "id", Type: "bigserial"},
+			{Name: "quantity", Type: "integer"},
+			{Name: "price", Type: "numeric(10,2)"},
+			{Name: "balance", Type: "decimal(30,4)", Nullable: true},
+			{Name: "created_at", Type: "timestamptz"},
+			{Name: "ref", Type: "uuid"},
+			{Name: "tags", Type: "text[]"},
+			{Name: "payload", Type: "jsonb", Nullable: true},
+		},
+		RowCount: 5,
+	}
+
+	writeTable := func(t *testing.T, opts pgdump.ParquetOptions) string {
+		dir := t.TempDir()
+		writer, err := pgdump.NewParquetWriter(dir, opts)
+		require.NoError(t, err)
+
+		require.NoError(t, writer.WriteSchema(&schema.Schema{Tables: map[string]*schema.Table{"orders": table}}))
+		require.NoError(t, writer.BeginTable("orders", table))
+		for i := 0; i < table.RowCount; i++ {
+			row := map[string]interface{}{
+				"id":         int64(i + 1),
+				"quantity":   int64(3),
+				"price":      12.345,
+				"balance":    nil,
+				"created_at": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				"ref":        "7f1c2d3e-4b5a-6978-8a9b-0c1d2e3f4a5b",
+				"tags":       []interface{}{"a", "b"},
+				"payload":    map[string]interface{}{"k": "v"},
+			}
+			require.NoError(t, writer.WriteRow(row))
+		}
+		require.NoError(t, writer.EndTable())
+
+		return filepath.Join(dir, "orders.parquet")
+	}
+
+	t.Run("map PostgreSQL types to Parquet logical types", func(t *testing.T) {
+		pf := openParquet(t, writeTable(t, pgdump.DefaultParquetOptions()))
+		s := pf.Schema()
+
+		price, ok := s.Lookup("price")
+		require.True(t, ok)
+		require.NotNil(t, price.Node.Type().LogicalType().Decimal)
+		assert.Equal(t, int32(10), price.Node.Type().LogicalType().Decimal.Precision)
+		assert.Equal(t, int32(2), price.Node.Type().LogicalType().Decimal.Scale)
+
+		balance, ok := s.Lookup("balance")
+		require.True(t, ok)
+		assert.True(t, balance.Node.Optional())
+		assert.Equal(t, parquet.FixedLenByteArray, balance.Node.Type().Kind())
+
+		createdAt, ok := s.Lookup("created_at")
+		require.True(t, ok)
+		require.NotNil(t, createdAt.Node.Type().LogicalType().Timestamp)
+		assert.True(t, createdAt.Node.Type().LogicalType().Timestamp.IsAdjustedToUTC)
+
+		ref, ok := s.Lookup("ref")
+		require.True(t, ok)
+		assert.NotNil(t, ref.Node.Type().LogicalType().UUID)
+
+		tags, ok := s.Lookup("tags", "list", "element")
+		require.True(t, ok)
+		assert.NotNil(t, tags.Node.Type().LogicalType().UTF8)
+
+		payload, ok := s.Lookup("payload")
+		require.True(t, ok)
+		assert.NotNil(t, payload.Node.Type().LogicalType().UTF8)
+	})
+
+	t.Run("round-trip row values", func(t *testing.T) {
+		path := writeTable(t, pgdump.DefaultParquetOptions())
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+
+		reader := parquet.NewReader(f)
+		defer reader.Close()
+		assert.Equal(t, int64(5), reader.NumRows())
+
+		row := map[string]interface{}{}
+		require.NoError(t, reader.Read(&row))
+		assert.EqualValues(t, 1, row["id"])
+		assert.EqualValues(t, 1235, row["price"]) // 12.345 rounded to scale 2
+		assert.Nil(t, row["balance"])
+		assert.Equal(t, `{"k":"v"}`, row["payload"])
+		assert.Equal(t, []interface{}{"a", "b"}, row["tags"])
+	})
+
+	t.Run("split rows into row groups", func(t *testing.T) {
+		opts := pgdump.DefaultParquetOptions()
+		opts.RowGroupSize = 2
+		pf := openParquet(t, writeTable(t, opts))
+
+		assert.Equal(t, int64(5), pf.NumRows())
+		assert.Len(t, pf.RowGroups(), 3)
+	})
+
+	t.Run("zstd compression", func(t *testing.T) {
+		opts := pgdump.DefaultParquetOptions()
+		opts.Compression = "zstd"
+		pf := openParquet(t, writeTable(t, opts))
+
+		chunk := pf.Metadata().RowGroups[0].Columns[0].MetaData
+		assert.Equal(t, "ZSTD", chunk.Codec.String())
+	})
+
+	t.Run("abort removes the partial file", func(t *testing.T) {
+		dir := t.TempDir()
+		writer, err := pgdump.NewParquetWriter(dir, pgdump.DefaultParquetOptions())
+		require.NoError(t, err)
+
+		require.NoError(t, writer.BeginTable("orders", table))
+		assert.FileExists(t, filepath.Join(dir, "orders.parquet"))
+		assert.Error(t, writer.WriteRow(map[string]interface{}{"id": "not a number"}))
+		require.NoError(t, writer.AbortTable())
+		assert.NoFileExists(t, filepath.Join(dir, "orders.parquet"))
+
+		// The writer is ready for the next table
+		require.NoError(t, writer.BeginTable("orders", table))
+		require.NoError(t, writer.EndTable())
+		assert.FileExists(t, filepath.Join(dir, "orders.parquet"))
+	})
+
+	t.Run("reject unknown compression", func(t *testing.T) {
+		_, err := pgdump.NewParquetWriter(t.TempDir(), pgdump.ParquetOptions{Compression: "lzo"})
+		assert.Error(t, err)
+	})
+
+	t.Run("NewWriter rejects parquet", func(t *testing.T) {
+		_, err := pgdump.NewWriter(os.Stdout, "parquet")
+		assert.Error(t, err)
+	})
+}
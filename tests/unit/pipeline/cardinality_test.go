@@ -0,0 +1,117 @@
+package pipeline_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// childCounts returns the number of children planned for each parent
+func childCounts(parents []int, parentCount int) []int {
+	counts := make([]int, parentCount)
+	for _, p := range parents {
+		counts[p]++
+	}
+	return counts
+}
+
+func sumCounts(counts []int) int {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total
+}
+
+func TestPlanChildren(t *testing.T) {
+	t.Run("counts stay within min and max", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 1, Max: 5}
+		parents := pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 200, 0)
+
+		for _, n := range childCounts(parents, 200) {
+			assert.GreaterOrEqual(t, n, 1)
+			assert.LessOrEqual(t, n, 5)
+		}
+	})
+
+	t.Run("children are grouped by parent", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 2, Max: 2}
+		parents := pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 3, 0)
+
+		assert.Equal(t, []int{0, 0, 1, 1, 2, 2}, parents)
+	})
+
+	t.Run("empty ratio leaves parents without children", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 1, Max: 3, EmptyRatio: 0.3}
+		parents := pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 1000, 0)
+
+		empty := 0
+		for _, n := range childCounts(parents, 1000) {
+			if n == 0 {
+				empty++
+			}
+		}
+		assert.InDelta(t, 300, empty, 50)
+	})
+
+	t.Run("zipf skews towards min", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 0, Max: 20, Distribution: "zipf"}
+		counts := childCounts(pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 1000, 0), 1000)
+
+		low, high := 0, 0
+		for _, n := range counts {
+			assert.LessOrEqual(t, n, 20)
+			if n <= 2 {
+				low++
+			} else if n >= 10 {
+				high++
+			}
+		}
+		assert.Greater(t, low, high*3)
+	})
+
+	t.Run("fixed row count scales the plan", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 1, Max: 5, Distribution: "normal"}
+		parents := pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 50, 137)
+
+		assert.Len(t, parents, 137)
+	})
+
+	t.Run("scaling keeps counts within min and max", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 1, Max: 3}
+		counts := childCounts(pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 10, 28), 10)
+		assert.Equal(t, 28, sumCounts(counts))
+		for _, n := range counts {
+			assert.GreaterOrEqual(t, n, 1)
+			assert.LessOrEqual(t, n, 3)
+		}
+
+		// Out of reach: every parent gets max children rather than ten each
+		counts = childCounts(pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 10, 100), 10)
+		assert.Equal(t, []int{3, 3, 3, 3, 3, 3, 3, 3, 3, 3}, counts)
+	})
+
+	t.Run("scaling keeps empty parents empty while it can", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 2, Max: 6, EmptyRatio: 0.3}
+		counts := childCounts(pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 1000, 3000), 1000)
+		assert.Equal(t, 3000, sumCounts(counts))
+		empty := 0
+		for _, n := range counts {
+			if n == 0 {
+				empty++
+				continue
+			}
+			assert.GreaterOrEqual(t, n, 2)
+			assert.LessOrEqual(t, n, 6)
+		}
+		assert.InDelta(t, 300, empty, 50)
+	})
+
+	t.Run("no parents means no children", func(t *testing.T) {
+		c := &schema.Cardinality{Min: 1, Max: 5}
+		assert.Empty(t, pipeline.PlanChildren(rand.New(rand.NewSource(1)), c, 0, 0))
+	})
+}
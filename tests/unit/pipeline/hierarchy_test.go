@@ -0,0 +1,82 @@
+package pipeline_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanHierarchy(t *testing.T) {
+	t.Run("parents precede children", func(t *testing.T) {
+		h := &schema.Hierarchy{Roots: 3, FanOut: &schema.Cardinality{Min: 0, Max: 4, Distribution: "zipf"}}
+		parents, depths := pipeline.PlanHierarchy(rand.New(rand.NewSource(1)), h, 500)
+
+		require.Len(t, parents, 500)
+		require.Len(t, depths, 500)
+		assert.Equal(t, []int{-1, -1, -1}, parents[:3])
+		for i, parent := range parents[3:] {
+			row := i + 3
+			assert.Less(t, parent, row, "row %d points forward", row)
+			assert.GreaterOrEqual(t, parent, 0, "only the first rows are roots")
+			assert.Equal(t, depths[parent]+1, depths[row])
+		}
+	})
+
+	t.Run("respect max depth", func(t *testing.T) {
+		h := &schema.Hierarchy{Roots: 2, MaxDepth: 3, FanOut: &schema.Cardinality{Min: 1, Max: 2}}
+		_, depths := pipeline.PlanHierarchy(rand.New(rand.NewSource(1)), h, 300)
+
+		for _, depth := range depths {
+			assert.LessOrEqual(t, depth, 3)
+		}
+	})
+
+	t.Run("fan-out bounds each node", func(t *testing.T) {
+		h := &schema.Hierarchy{Roots: 1, FanOut: &schema.Cardinality{Min: 2, Max: 3}}
+		parents, _ := pipeline.PlanHierarchy(rand.New(rand.NewSource(1)), h, 200)
+
+		children := make(map[int]int)
+		for _, parent := range parents {
+			children[parent]++
+		}
+		for parent, n := range children {
+			if parent >= 0 {
+				assert.LessOrEqual(t, n, 3)
+			}
+		}
+	})
+
+	t.Run("empty ratio leaves nodes without children", func(t *testing.T) {
+		h := &schema.Hierarchy{Roots: 5, FanOut: &schema.Cardinality{Min: 3, Max: 3, EmptyRatio: 0.4}}
+		parents, _ := pipeline.PlanHierarchy(rand.New(rand.NewSource(1)), h, 1000)
+		require.Len(t, parents, 1000)
+
+		// Every node up to the last parent was expanded
+		children := make(map[int]int)
+		last := 0
+		for _, parent := range parents {
+			children[parent]++
+			if parent > last {
+				last = parent
+			}
+		}
+		leaves := 0
+		for node := 0; node < last; node++ {
+			if children[node] == 0 {
+				leaves++
+			}
+		}
+		assert.InDelta(t, 0.4, float64(leaves)/float64(last), 0.08)
+	})
+
+	t.Run("single level makes every row a root", func(t *testing.T) {
+		h := &schema.Hierarchy{Roots: 2, MaxDepth: 1}
+		parents, _ := pipeline.PlanHierarchy(rand.New(rand.NewSource(1)), h, 5)
+
+		assert.Equal(t, []int{-1, -1, -1, -1, -1}, parents)
+	})
+}
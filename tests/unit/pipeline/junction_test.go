@@ -0,0 +1,66 @@
+package pipeline_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanJunction(t *testing.T) {
+	distinct := func(t *testing.T, pairs [][2]int) {
+		t.Helper()
+		seen := make(map[[2]int]bool, len(pairs))
+		for _, pair := range pairs {
+			assert.False(t, seen[pair], "pair %v repeated", pair)
+			seen[pair] = true
+		}
+	}
+
+	t.Run("pairs are distinct and within range", func(t *testing.T) {
+		j := &schema.Junction{}
+		pairs := pipeline.PlanJunction(rand.New(rand.NewSource(1)), j, 20, 8, 100)
+
+		require.Len(t, pairs, 100)
+		distinct(t, pairs)
+		for _, pair := range pairs {
+			assert.True(t, pair[0] >= 0 && pair[0] < 20)
+			assert.True(t, pair[1] >= 0 && pair[1] < 8)
+		}
+	})
+
+	t.Run("row count is reduced to the achievable pairs", func(t *testing.T) {
+		pairs := pipeline.PlanJunction(rand.New(rand.NewSource(1)), &schema.Junction{}, 5, 4, 1000)
+		assert.Len(t, pairs, 20)
+		distinct(t, pairs)
+
+		j := &schema.Junction{Left: schema.JunctionSide{Degree: &schema.Cardinality{Min: 1, Max: 2}}}
+		pairs = pipeline.PlanJunction(rand.New(rand.NewSource(1)), j, 5, 4, 1000)
+		assert.Len(t, pairs, 10)
+	})
+
+	t.Run("degrees bound each side", func(t *testing.T) {
+		j := &schema.Junction{
+			Left:  schema.JunctionSide{Degree: &schema.Cardinality{Min: 1, Max: 3}},
+			Right: schema.JunctionSide{Degree: &schema.Cardinality{Min: 0, Max: 10, Distribution: "zipf"}},
+		}
+		pairs := pipeline.PlanJunction(rand.New(rand.NewSource(2)), j, 50, 30, 0)
+		distinct(t, pairs)
+
+		perLeft := map[int]int{}
+		for _, pair := range pairs {
+			perLeft[pair[0]]++
+		}
+		assert.Len(t, perLeft, 50)
+		for left, n := range perLeft {
+			assert.True(t, n >= 1 && n <= 3, "left row %d has %d links", left, n)
+		}
+	})
+
+	t.Run("no parents", func(t *testing.T) {
+		assert.Empty(t, pipeline.PlanJunction(rand.New(rand.NewSource(1)), &schema.Junction{}, 0, 10, 5))
+	})
+}
@@ -0,0 +1,111 @@
+package pipeline_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyStore(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fill := func(ks *pipeline.KeyStore) {
+		ks.Track("users", []string{"id"})
+		ks.Track("users", []string{"name", "created_at"})
+		for i := 0; i < 10; i++ {
+			ks.Record("users", map[string]interface{}{"id": int64(i), "name": nil, "created_at": created})
+		}
+	}
+
+	t.Run("records tracked columns per row", func(t *testing.T) {
+		ks := pipeline.NewKeyStore()
+		fill(ks)
+
+		assert.Equal(t, 10, ks.Count("users", []string{"id"}))
+		tuple, ok := ks.Get("users", []string{"id"}, 3)
+		require.True(t, ok)
+		assert.Equal(t, []interface{}{int64(3)}, tuple)
+
+		_, ok = ks.Get("users", []string{"email"}, 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("spilled rows read back unchanged", func(t *testing.T) {
+		ks := pipeline.NewKeyStore()
+		ks.SetSpill(4, t.TempDir())
+		defer ks.Close()
+		fill(ks)
+		require.NoError(t, ks.Err())
+
+		assert.Equal(t, 10, ks.Count("users", []string{"id"}))
+		for i := 0; i < 10; i++ {
+			tuple, ok := ks.Get("users", []string{"id"}, i)
+			require.True(t, ok)
+			assert.Equal(t, []interface{}{int64(i)}, tuple)
+
+			tuple, ok = ks.Get("users", []string{"name", "created_at"}, i)
+			require.True(t, ok)
+			assert.Nil(t, tuple[0])
+			assert.True(t, created.Equal(tuple[1].(time.Time)))
+		}
+
+		index, ok := ks.Pick(rand.New(rand.NewSource(1)), "users", []string{"id"})
+		require.True(t, ok)
+		assert.True(t, index >= 0 && index < 10)
+	})
+
+	t.Run("reset discards rows", func(t *testing.T) {
+		ks := pipeline.NewKeyStore()
+		ks.SetSpill(4, t.TempDir())
+		defer ks.Close()
+		fill(ks)
+
+		ks.Reset("users")
+		assert.Equal(t, 0, ks.Count("users", []string{"id"}))
+		_, ok := ks.Pick(rand.New(rand.NewSource(1)), "users", []string{"id"})
+		assert.False(t, ok)
+	})
+}
+
+func TestKeyStoreSubsets(t *testing.T) {
+	even := func(row map[string]interface{}) bool { return row["id"].(int64)%2 == 0 }
+
+	t.Run("keeps the row indexes of matching rows", func(t *testing.T) {
+		ks := pipeline.NewKeyStore()
+		ks.Track("users", []string{"id"})
+		ks.TrackSubset("users", "even", even)
+		for i := 0; i < 10; i++ {
+			ks.Record("users", map[string]interface{}{"id": int64(i + 1)})
+		}
+
+		require.Equal(t, 5, ks.SubsetCount("users", "even"))
+		row, ok := ks.SubsetRow("users", "even", 2)
+		require.True(t, ok)
+		tuple, ok := ks.Get("users", []string{"id"}, row)
+		require.True(t, ok)
+		assert.Equal(t, []interface{}{int64(6)}, tuple)
+
+		_, ok = ks.SubsetRow("users", "even", 5)
+		assert.False(t, ok)
+		assert.Zero(t, ks.SubsetCount("users", "odd"))
+	})
+
+	t.Run("reset discards matching rows", func(t *testing.T) {
+		ks := pipeline.NewKeyStore()
+		ks.TrackSubset("users", "even", even)
+		assert.True(t, ks.Tracks("users"))
+
+		ks.Record("users", map[string]interface{}{"id": int64(2)})
+		ks.Reset("users")
+		ks.Record("users", map[string]interface{}{"id": int64(1)})
+		ks.Record("users", map[string]interface{}{"id": int64(4)})
+
+		row, ok := ks.SubsetRow("users", "even", 0)
+		require.True(t, ok)
+		assert.Equal(t, 1, row)
+		assert.Equal(t, 1, ks.SubsetCount("users", "even"))
+	})
+}
@@ -0,0 +1,103 @@
+package profile_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/profile"
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	columns, rows, err := profile.ReadCSV(strings.NewReader("id,name\n1,ada\n2,\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, columns)
+	assert.Equal(t, [][]interface{}{{"1", "ada"}, {"2", nil}}, rows)
+
+	_, _, err = profile.ReadCSV(strings.NewReader(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected a header line")
+}
+
+func TestBuild(t *testing.T) {
+	// 100 rows: a rating from 1 to 5, an amount from 0 to 99, a flag, a tier
+	// with 3 values and NULLs, and a code with a distinct value per row
+	var rows [][]interface{}
+	for i := 0; i < 100; i++ {
+		var tier interface{}
+		switch {
+		case i < 50:
+			tier = "bronze"
+		case i < 80:
+			tier = "silver"
+		case i < 90:
+			tier = "gold"
+		}
+		rows = append(rows, []interface{}{
+			fmt.Sprintf("%d", 1+i%5),
+			fmt.Sprintf("%.2f", float64(i)),
+			[]string{"t", "f", "F", "false"}[i%4],
+			tier,
+			fmt.Sprintf("c%d", i),
+		})
+	}
+	p := profile.Build([]string{"rating", "amount", "active", "tier", "code"}, rows, profile.Options{TopK: 5, Bins: 4})
+	require.Len(t, p.Columns, 5)
+	assert.Equal(t, 100, p.Rows)
+
+	t.Run("few distinct numbers keep every value", func(t *testing.T) {
+		rating := p.Columns[0]
+		assert.Equal(t, "integer", rating.Kind)
+		assert.Equal(t, 5, rating.Distinct)
+		require.Len(t, rating.Distribution.Bins, 5)
+		for i, bin := range rating.Distribution.Bins {
+			assert.Equal(t, &schema.HistogramBin{Min: float64(i + 1), Max: float64(i + 1), Weight: 0.2}, bin)
+		}
+		assert.Zero(t, rating.Distribution.NullRate)
+	})
+
+	t.Run("other numbers get a histogram", func(t *testing.T) {
+		amount := p.Columns[1]
+		assert.Equal(t, "numeric", amount.Kind)
+		bins := amount.Distribution.Bins
+		require.NotEmpty(t, bins)
+		assert.Equal(t, 0.0, bins[0].Min)
+		assert.Equal(t, 99.0, bins[len(bins)-1].Max)
+		require.Len(t, bins, 4)
+		for _, bin := range bins {
+			assert.Equal(t, 0.25, bin.Weight)
+		}
+		assert.NoError(t, schema.ValidateMarginal(amount.Distribution))
+	})
+
+	t.Run("booleans and categories get shares and a null rate", func(t *testing.T) {
+		active := p.Columns[2]
+		assert.Equal(t, "boolean", active.Kind)
+		assert.Equal(t, map[string]interface{}{"true": 0.25, "false": 0.75}, active.Distribution.Weights)
+
+		tier := p.Columns[3]
+		assert.Equal(t, "text", tier.Kind)
+		assert.Equal(t, 0.1, tier.Distribution.NullRate)
+		assert.Equal(t, map[string]interface{}{"bronze": 0.555555, "silver": 0.333333, "gold": 0.111111}, tier.Distribution.Weights)
+		assert.Nil(t, tier.Distribution.Length)
+	})
+
+	t.Run("values beyond the top ones are described by their length", func(t *testing.T) {
+		code := p.Columns[4]
+		assert.Equal(t, 100, code.Distinct)
+		assert.Equal(t, map[string]interface{}{"c0": 0.01, "c1": 0.01, "c10": 0.01, "c11": 0.01, "c12": 0.01}, code.Distribution.Weights)
+		require.NotNil(t, code.Distribution.Length)
+		// c2 to c9, then c13 to c99
+		assert.Equal(t, []*schema.HistogramBin{{Min: 2, Max: 2, Weight: 0.08421}, {Min: 3, Max: 3, Weight: 0.915789}}, code.Distribution.Length.Bins)
+		assert.NoError(t, code.Distribution.ValidateEmpirical())
+	})
+
+	t.Run("columns of NULLs have no distribution", func(t *testing.T) {
+		p := profile.Build([]string{"empty"}, [][]interface{}{{nil}, {nil}}, profile.Options{})
+		assert.Equal(t, "", p.Columns[0].Kind)
+		assert.Nil(t, p.Columns[0].Distribution)
+	})
+}
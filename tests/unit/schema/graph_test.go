@@ -0,0 +1,53 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/NhaLeTruc/datagen-cli/internal/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableOrder(t *testing.T) {
+	fk := func(table string) *schema.ForeignKey {
+		return &schema.ForeignKey{Columns: []string{table + "_id"}, ReferencedTable: table, ReferencedColumns: []string{"id"}}
+	}
+
+	t.Run("referenced tables come first", func(t *testing.T) {
+		s := &schema.Schema{
+			Tables: map[string]*schema.Table{
+				"order_items": {ForeignKeys: []*schema.ForeignKey{fk("orders"), fk("products")}},
+				"orders":      {ForeignKeys: []*schema.ForeignKey{fk("customers")}},
+				"customers":   {},
+				"products":    {},
+				"audit":       {},
+			},
+		}
+
+		assert.Equal(t, []string{"audit", "customers", "orders", "products", "order_items"}, schema.TableOrder(s))
+		assert.Equal(t, []string{"orders", "products"}, schema.TableDependencies("order_items", s.Tables["order_items"]))
+		assert.Nil(t, s.Tables["order_items"].Dependencies, "ordering leaves the schema unchanged")
+	})
+
+	t.Run("self-references do not block ordering", func(t *testing.T) {
+		s := &schema.Schema{
+			Tables: map[string]*schema.Table{
+				"employees": {ForeignKeys: []*schema.ForeignKey{fk("employees")}},
+			},
+		}
+
+		assert.Equal(t, []string{"employees"}, schema.TableOrder(s))
+		assert.Empty(t, schema.TableDependencies("employees", s.Tables["employees"]))
+	})
+
+	t.Run("cycles are appended by name", func(t *testing.T) {
+		s := &schema.Schema{
+			Tables: map[string]*schema.Table{
+				"b":    {ForeignKeys: []*schema.ForeignKey{fk("a")}},
+				"a":    {ForeignKeys: []*schema.ForeignKey{fk("b")}},
+				"root": {},
+			},
+		}
+
+		assert.Equal(t, []string{"root", "a", "b"}, schema.TableOrder(s))
+	})
+}
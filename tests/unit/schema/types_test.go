@@ -1,7 +1,9 @@
 package schema_test
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/NhaLeTruc/datagen-cli/internal/schema"
 	"github.com/stretchr/testify/assert"
@@ -254,4 +256,169 @@ func TestCustomType(t *testing.T) {
 		require.True(t, ok)
 		assert.Len(t, enumDef.Values, 3)
 	})
-}
\ No newline at end of file
+}
+
+func TestForeignKeyQualifies(t *testing.T) {
+	fk := &schema.ForeignKey{Where: map[string]interface{}{"status": []interface{}{"active", "preorder"}, "visible": true}}
+
+	assert.True(t, fk.Qualifies(map[string]interface{}{"status": "active", "visible": true}))
+	assert.True(t, fk.Qualifies(map[string]interface{}{"status": "preorder", "visible": true}))
+	assert.False(t, fk.Qualifies(map[string]interface{}{"status": "draft", "visible": true}))
+	assert.False(t, fk.Qualifies(map[string]interface{}{"status": "active", "visible": false}))
+	assert.True(t, (&schema.ForeignKey{}).Qualifies(map[string]interface{}{}))
+}
+
+func TestCondition(t *testing.T) {
+	parse := func(t *testing.T, source string) *schema.Condition {
+		t.Helper()
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(source), &raw))
+		cond, err := schema.ParseCondition(raw)
+		require.NoError(t, err)
+		return cond
+	}
+	row := map[string]interface{}{
+		"status":     "shipped",
+		"amount":     120,
+		"country":    "CA",
+		"email":      nil,
+		"created_at": time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC),
+		"sku":        "AB-1234",
+	}
+	parents := map[string]interface{}{"customers.tier": "gold"}
+	value := func(field string) (interface{}, bool) {
+		if v, ok := row[field]; ok {
+			return v, true
+		}
+		v, ok := parents[field]
+		return v, ok
+	}
+
+	t.Run("operators", func(t *testing.T) {
+		for source, expected := range map[string]bool{
+			`{}`:                                      true,
+			`{"status": "shipped"}`:                   true,
+			`{"status": "pending"}`:                   false,
+			`{"amount": 120}`:                         true,
+			`{"amount": "120.0"}`:                     true,
+			`{"amount": {"gt": 100}}`:                 true,
+			`{"amount": {"gte": 120, "lt": 121}}`:     true,
+			`{"amount": {"lte": 100}}`:                false,
+			`{"amount": {"between": [100, 150]}}`:     true,
+			`{"amount": {"between": [121, 150]}}`:     false,
+			`{"country": {"in": ["US", "CA"]}}`:       true,
+			`{"country": {"in": ["US", "MX"]}}`:       false,
+			`{"country": {"not": "US"}}`:              true,
+			`{"country": {"not": {"in": ["CA"]}}}`:    false,
+			`{"sku": {"regex": "^[A-Z]{2}-\\d{4}$"}}`: true,
+			`{"email": {"is_null": true}}`:            true,
+			`{"email": null}`:                         true,
+			`{"status": {"is_null": true}}`:           false,
+			`{"email": {"gt": 1}}`:                    false,
+			`{"missing": {"is_null": true}}`:          true,
+			`{"created_at": {"gte": "2024-03-01"}}`:   true,
+			`{"customers.tier": "gold"}`:              true,
+			`{"customers.tier": {"in": ["silver"]}}`:  false,
+		} {
+			assert.Equal(t, expected, parse(t, source).Matches(value), source)
+		}
+	})
+
+	t.Run("boolean logic", func(t *testing.T) {
+		for source, expected := range map[string]bool{
+			`{"status": "shipped", "amount": {"gt": 500}}`:                                             false,
+			`{"or": [{"amount": {"gt": 500}}, {"country": "CA"}]}`:                                     true,
+			`{"and": [{"status": "shipped"}, {"or": [{"country": "US"}, {"sku": {"regex": "^AB"}}]}]}`: true,
+			`{"not": {"status": "shipped"}}`:                                                           false,
+			`{"not": {"or": [{"country": "US"}, {"country": "MX"}]}}`:                                  true,
+		} {
+			assert.Equal(t, expected, parse(t, source).Matches(value), source)
+		}
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		cond := parse(t, `{"status": "shipped", "or": [{"customers.tier": "gold"}, {"not": {"amount": {"lt": 5}}}]}`)
+		assert.Equal(t, []string{"amount", "customers.tier", "status"}, cond.Columns())
+	})
+
+	t.Run("invalid conditions", func(t *testing.T) {
+		for source, message := range map[string]string{
+			`{"amount": {"above": 1}}`:      "unknown operator 'above'",
+			`{"amount": {"between": [1]}}`:  "expects [low, high]",
+			`{"country": {"in": "US"}}`:     "expects a list of values",
+			`{"sku": {"regex": "("}}`:       "invalid regex",
+			`{"email": {"is_null": "yes"}}`: "expects true or false",
+			`{"or": {"status": "shipped"}}`: "expects a list of conditions",
+			`{"not": "shipped"}`:            "expects a condition",
+			`{"amount": {}}`:                "has no operators",
+		} {
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(source), &raw))
+			_, err := schema.ParseCondition(raw)
+			require.Error(t, err, source)
+			assert.Contains(t, err.Error(), message, source)
+		}
+	})
+}
+
+func TestConditional(t *testing.T) {
+	parse := func(source string) (*schema.Conditional, error) {
+		col := &schema.Column{Name: "currency", Type: "char(3)"}
+		if err := json.Unmarshal([]byte(source), &col.GeneratorConfig); err != nil {
+			return nil, err
+		}
+		return col.Conditional()
+	}
+
+	t.Run("other generators", func(t *testing.T) {
+		cond, err := parse(`{"type": "weighted_enum"}`)
+		require.NoError(t, err)
+		assert.Nil(t, cond)
+	})
+
+	t.Run("most specific entry matches", func(t *testing.T) {
+		cond, err := parse(`{"type": "conditional", "given": ["country", "customers.segment"], "weights": {
+			"*": {"USD": 1},
+			"JP|*": {"JPY": 95, "USD": 5},
+			"JP|b2b": {"USD": 1},
+			"*|b2b": {"EUR": 1}
+		}}`)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"country", "customers.segment"}, cond.Given)
+
+		for values, expected := range map[[2]interface{}][]string{
+			{"JP", "b2b"}: {"JP", "b2b"},
+			{"JP", "b2c"}: {"JP", "*"},
+			{"FR", "b2b"}: {"*", "b2b"},
+			{"FR", "b2c"}: {"*", "*"},
+			{"JP", nil}:   {"JP", "*"},
+			{nil, "b2c"}:  {"*", "*"},
+		} {
+			entry := cond.Match(values[:])
+			require.NotNil(t, entry, values)
+			assert.Equal(t, expected, entry.Key, values)
+		}
+		assert.Equal(t, map[string]float64{"JPY": 95, "USD": 5}, cond.Match([]interface{}{"JP", "b2c"}).Weights)
+	})
+
+	t.Run("no match without a wildcard", func(t *testing.T) {
+		cond, err := parse(`{"type": "conditional", "given": ["level"], "weights": {"1": {"a": 1}, "2": {"b": 1}}}`)
+		require.NoError(t, err)
+		assert.NotNil(t, cond.Match([]interface{}{int64(2)}), "values are compared as text")
+		assert.Nil(t, cond.Match([]interface{}{int64(3)}))
+	})
+
+	t.Run("rejects invalid tables", func(t *testing.T) {
+		for source, message := range map[string]string{
+			`{"type": "conditional", "weights": {"*": {"a": 1}}}`:                      "'given' must list the columns",
+			`{"type": "conditional", "given": ["country"]}`:                            "'weights' must map values of country",
+			`{"type": "conditional", "given": ["a", "b"], "weights": {"x": {"a": 1}}}`: `key "x" needs 2 value(s)`,
+			`{"type": "conditional", "given": ["a"], "weights": {"x": {"a": -1}}}`:     `weight of "a" must be a non-negative number`,
+			`{"type": "conditional", "given": ["a"], "weights": {"x": {"a": 0}}}`:      "must not all be zero",
+		} {
+			_, err := parse(source)
+			require.Error(t, err, source)
+			assert.Contains(t, err.Error(), message, source)
+		}
+	})
+}
@@ -380,6 +380,34 @@ func TestValidateRowCount(t *testing.T) {
 	})
 }
 
+func TestValidateNamespace(t *testing.T) {
+	newSchema := func(tableName, namespace string) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				tableName: {
+					Columns:  []*schema.Column{{Name: "id", Type: "serial"}},
+					Schema:   namespace,
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid namespaces", func(t *testing.T) {
+		assert.Empty(t, schema.Validate(newSchema("orders", "sales")))
+		assert.Empty(t, schema.Validate(newSchema("sales.orders", "")))
+		assert.Empty(t, schema.Validate(newSchema("sales.orders", "sales")))
+	})
+
+	t.Run("schema conflicts with the table key", func(t *testing.T) {
+		errs := schema.Validate(newSchema("sales.orders", "billing"))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), `table sales.orders: schema "billing" conflicts with the namespace "sales" of the table key`)
+	})
+}
+
 func TestValidateMultipleErrors(t *testing.T) {
 	t.Run("accumulate multiple errors", func(t *testing.T) {
 		s := &schema.Schema{
@@ -400,4 +428,1201 @@ func TestValidateMultipleErrors(t *testing.T) {
 		errs := schema.Validate(s)
 		assert.GreaterOrEqual(t, len(errs), 3, "should have at least 3 errors")
 	})
-}
\ No newline at end of file
+}
+
+func TestValidateCardinality(t *testing.T) {
+	newSchema := func(rowCount int, c *schema.Cardinality) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"customers": {
+					Columns:  []*schema.Column{{Name: "id", Type: "serial"}},
+					RowCount: 10,
+				},
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "customer_id", Type: "integer"},
+					},
+					ForeignKeys: []*schema.ForeignKey{{
+						Columns:           []string{"customer_id"},
+						ReferencedTable:   "customers",
+						ReferencedColumns: []string{"id"},
+						Cardinality:       c,
+					}},
+					RowCount: rowCount,
+				},
+			},
+		}
+	}
+
+	t.Run("row count may be derived from the cardinality", func(t *testing.T) {
+		errs := schema.Validate(newSchema(0, &schema.Cardinality{Min: 0, Max: 20, Distribution: "zipf"}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("row count is required without a cardinality", func(t *testing.T) {
+		errs := schema.Validate(newSchema(0, nil))
+		assert.NotEmpty(t, errs)
+	})
+
+	t.Run("reject invalid bounds", func(t *testing.T) {
+		errs := schema.Validate(newSchema(0, &schema.Cardinality{Min: 5, Max: 1}))
+		require.NotEmpty(t, errs)
+		assert.Contains(t, errs[0].Error(), "min <= max")
+	})
+
+	t.Run("reject invalid empty ratio and distribution", func(t *testing.T) {
+		errs := schema.Validate(newSchema(0, &schema.Cardinality{Min: 1, Max: 5, EmptyRatio: 1.5, Distribution: "pareto"}))
+		assert.Len(t, errs, 2)
+	})
+
+	t.Run("a fixed row count must be within reach of min and max", func(t *testing.T) {
+		assert.Empty(t, schema.Validate(newSchema(30, &schema.Cardinality{Min: 1, Max: 3})))
+		assert.Empty(t, schema.Validate(newSchema(5, &schema.Cardinality{Min: 1, Max: 3, EmptyRatio: 0.2})))
+
+		errs := schema.Validate(newSchema(100, &schema.Cardinality{Min: 1, Max: 3}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "table orders: row_count 100 cannot be met by 10 customers rows with 1 to 3 children each, which allows 10 to 30 rows")
+
+		errs = schema.Validate(newSchema(5, &schema.Cardinality{Min: 1, Max: 3}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "row_count 5 cannot be met")
+	})
+}
+
+func TestValidateHierarchy(t *testing.T) {
+	newSchema := func(nullable bool, h *schema.Hierarchy) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"categories": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "parent_id", Type: "integer", Nullable: nullable},
+						{Name: "path", Type: "ltree"},
+					},
+					ForeignKeys: []*schema.ForeignKey{{
+						Columns:           []string{"parent_id"},
+						ReferencedTable:   "categories",
+						ReferencedColumns: []string{"id"},
+						Hierarchy:         h,
+					}},
+					RowCount: 50,
+				},
+			},
+		}
+	}
+
+	t.Run("valid hierarchy", func(t *testing.T) {
+		errs := schema.Validate(newSchema(true, &schema.Hierarchy{Roots: 3, MaxDepth: 4, PathColumn: "path"}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("parent column must be nullable", func(t *testing.T) {
+		errs := schema.Validate(newSchema(false, &schema.Hierarchy{}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "must be nullable")
+	})
+
+	t.Run("path column must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(true, &schema.Hierarchy{PathColumn: "lineage"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "lineage")
+	})
+
+	t.Run("fan_out distribution is checked like a cardinality", func(t *testing.T) {
+		alpha := 0.5
+		errs := schema.Validate(newSchema(true, &schema.Hierarchy{FanOut: &schema.Cardinality{Min: 1, Max: 4, Distribution: "zipf", Alpha: &alpha}}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "hierarchy fan_out zipf alpha must be greater than 1, got 0.5")
+
+		errs = schema.Validate(newSchema(true, &schema.Hierarchy{FanOut: &schema.Cardinality{Min: 1, Max: 4, Distribution: "pareto"}}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "hierarchy fan_out: unknown distribution 'pareto'")
+	})
+}
+
+
+func TestValidateCycleBreak(t *testing.T) {
+	newSchema := func(nullable bool, cycleBreak string) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"users": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "default_team_id", Type: "integer", Nullable: nullable},
+					},
+					PrimaryKey: []string{"id"},
+					ForeignKeys: []*schema.ForeignKey{{
+						Columns:           []string{"default_team_id"},
+						ReferencedTable:   "teams",
+						ReferencedColumns: []string{"id"},
+						CycleBreak:        cycleBreak,
+					}},
+					RowCount: 10,
+				},
+				"teams": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "owner_id", Type: "integer"},
+					},
+					PrimaryKey: []string{"id"},
+					ForeignKeys: []*schema.ForeignKey{{
+						Columns:           []string{"owner_id"},
+						ReferencedTable:   "users",
+						ReferencedColumns: []string{"id"},
+					}},
+					RowCount: 3,
+				},
+			},
+		}
+	}
+
+	t.Run("update breaks the cycle", func(t *testing.T) {
+		assert.Empty(t, schema.Validate(newSchema(true, schema.CycleBreakUpdate)))
+	})
+
+	t.Run("deferred breaks the cycle", func(t *testing.T) {
+		assert.Empty(t, schema.Validate(newSchema(false, schema.CycleBreakDeferred)))
+	})
+
+	t.Run("update requires a nullable column", func(t *testing.T) {
+		errs := schema.Validate(newSchema(false, schema.CycleBreakUpdate))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "must be nullable")
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		errs := schema.Validate(newSchema(true, "later"))
+		require.NotEmpty(t, errs)
+		assert.Contains(t, errs[0].Error(), "unknown cycle_break")
+	})
+
+	t.Run("unbroken cycle is still rejected", func(t *testing.T) {
+		errs := schema.Validate(newSchema(true, ""))
+		require.NotEmpty(t, errs)
+		assert.Contains(t, errs[0].Error(), "cycle_break")
+	})
+}
+
+func TestValidateJunction(t *testing.T) {
+	newSchema := func(j *schema.Junction) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"products": {Columns: []*schema.Column{{Name: "id", Type: "serial"}}, RowCount: 10},
+				"tags":     {Columns: []*schema.Column{{Name: "id", Type: "serial"}}, RowCount: 10},
+				"product_tags": {
+					Columns: []*schema.Column{
+						{Name: "product_id", Type: "integer"},
+						{Name: "tag_id", Type: "integer"},
+					},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"product_id"}, ReferencedTable: "products", ReferencedColumns: []string{"id"}},
+						{Columns: []string{"tag_id"}, ReferencedTable: "tags", ReferencedColumns: []string{"id"}},
+					},
+					Junction: j,
+				},
+			},
+		}
+	}
+
+	t.Run("row count may be derived", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Junction{
+			Left:  schema.JunctionSide{Columns: []string{"product_id"}, Degree: &schema.Cardinality{Min: 1, Max: 3}},
+			Right: schema.JunctionSide{Columns: []string{"tag_id"}},
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("sides must match foreign keys", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Junction{
+			Left:  schema.JunctionSide{Columns: []string{"product_id"}},
+			Right: schema.JunctionSide{Columns: []string{"label_id"}},
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "does not match a foreign key")
+	})
+
+	t.Run("sides must differ", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Junction{
+			Left:  schema.JunctionSide{Columns: []string{"tag_id"}},
+			Right: schema.JunctionSide{Columns: []string{"tag_id"}},
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "different foreign keys")
+	})
+
+	t.Run("degree distribution is checked like a cardinality", func(t *testing.T) {
+		alpha := 0.5
+		errs := schema.Validate(newSchema(&schema.Junction{
+			Left:  schema.JunctionSide{Columns: []string{"product_id"}, Degree: &schema.Cardinality{Min: 1, Max: 3, Distribution: "zipf", Alpha: &alpha}},
+			Right: schema.JunctionSide{Columns: []string{"tag_id"}, Degree: &schema.Cardinality{Min: 0, Max: 5, Distribution: "pareto"}},
+		}))
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "junction left degree zipf alpha must be greater than 1, got 0.5")
+		assert.Contains(t, errs[1].Error(), "junction right degree: unknown distribution 'pareto'")
+	})
+}
+
+func TestValidateLookup(t *testing.T) {
+	newSchema := func(config map[string]interface{}) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"products": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "price", Type: "numeric(10,2)"},
+					},
+					RowCount: 10,
+				},
+				"order_items": {
+					Columns: []*schema.Column{
+						{Name: "product_id", Type: "integer"},
+						{Name: "unit_price", Type: "numeric(10,2)", GeneratorConfig: config},
+					},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"product_id"}, ReferencedTable: "products", ReferencedColumns: []string{"id"}},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid lookup", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{"type": "lookup", "from": "products.price", "via": "product_id"}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("from must name a table column", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{"type": "lookup", "from": "price", "via": "product_id"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "table.column")
+	})
+
+	t.Run("via must be a foreign key column", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{"type": "lookup", "from": "products.price", "via": "unit_price"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "not a foreign key column")
+	})
+
+	t.Run("parent column must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{"type": "lookup", "from": "products.cost", "via": "product_id"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "'cost' does not exist")
+	})
+}
+
+func TestValidateAggregate(t *testing.T) {
+	newSchema := func(config map[string]interface{}) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "total", Type: "numeric(10,2)", GeneratorConfig: config},
+					},
+					PrimaryKey: []string{"id"},
+					RowCount:   10,
+				},
+				"order_items": {
+					Columns: []*schema.Column{
+						{Name: "order_id", Type: "integer"},
+						{Name: "quantity", Type: "integer"},
+						{Name: "unit_price", Type: "numeric(10,2)"},
+					},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"order_id"}, ReferencedTable: "orders", ReferencedColumns: []string{"id"}},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid aggregate", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "aggregate", "function": "sum", "from": "order_items", "via": "order_id", "value": "quantity * unit_price",
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "aggregate", "function": "median", "from": "order_items", "via": "order_id", "value": "quantity",
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "unknown aggregate function")
+	})
+
+	t.Run("via must reference the table", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "aggregate", "function": "count", "from": "order_items", "via": "quantity",
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "is not a foreign key referencing 'orders'")
+	})
+
+	t.Run("value columns must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "aggregate", "function": "sum", "from": "order_items", "via": "order_id", "value": "quantity * discount",
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "'discount' does not exist")
+	})
+}
+
+func TestValidateTemporal(t *testing.T) {
+	newSchema := func(created, updated *schema.Temporal) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"customers": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "created_at", Type: "timestamp"},
+					},
+					RowCount: 10,
+				},
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "customer_id", Type: "integer"},
+						{Name: "created_at", Type: "timestamp", Temporal: created},
+						{Name: "updated_at", Type: "timestamp", Temporal: updated},
+					},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"customer_id"}, ReferencedTable: "customers", ReferencedColumns: []string{"id"}},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid same-row and parent references", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Temporal{After: "customers.created_at", Within: "30d"},
+			&schema.Temporal{After: "created_at", Within: "12h"},
+		))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("same-row column must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(nil, &schema.Temporal{After: "shipped_at"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "'shipped_at' does not exist")
+	})
+
+	t.Run("parent reference needs a foreign key", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Temporal{After: "products.created_at"}, nil))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "needs a foreign key to 'products'")
+	})
+
+	t.Run("parent column must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Temporal{After: "customers.signup_at"}, nil))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "does not exist in table 'customers'")
+	})
+
+	t.Run("within must be a positive duration", func(t *testing.T) {
+		errs := schema.Validate(newSchema(nil, &schema.Temporal{After: "created_at", Within: "soon"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "invalid 'within'")
+
+		errs = schema.Validate(newSchema(nil, &schema.Temporal{After: "created_at", Within: "-1d"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "must be positive")
+	})
+
+	t.Run("same-row references cannot form a cycle", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Temporal{After: "updated_at"},
+			&schema.Temporal{After: "created_at"},
+		))
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "form a cycle")
+	})
+}
+
+func TestValidateSelection(t *testing.T) {
+	newSchema := func(selection *schema.Selection, cardinality *schema.Cardinality) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"accounts": {
+					Columns:  []*schema.Column{{Name: "id", Type: "serial"}},
+					RowCount: 10,
+				},
+				"events": {
+					Columns: []*schema.Column{{Name: "account_id", Type: "integer"}},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"account_id"}, ReferencedTable: "accounts", ReferencedColumns: []string{"id"},
+							Selection: selection, Cardinality: cardinality},
+					},
+					RowCount: 100,
+				},
+			},
+		}
+	}
+	alpha := func(v float64) *float64 { return &v }
+
+	t.Run("valid distributions", func(t *testing.T) {
+		for _, sel := range []*schema.Selection{
+			{Distribution: "uniform"},
+			{Distribution: "zipf", Alpha: alpha(1.2)},
+			{Distribution: "pareto"},
+			{Distribution: "hotspot", Top: 0.01, Share: 0.5},
+		} {
+			assert.Empty(t, schema.Validate(newSchema(sel, nil)), sel.Distribution)
+		}
+	})
+
+	t.Run("unknown distribution", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Selection{Distribution: "lognormal"}, nil))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "unknown selection distribution")
+	})
+
+	t.Run("alpha must be greater than 1", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Selection{Distribution: "pareto", Alpha: alpha(0.8)}, nil))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "pareto alpha must be greater than 1")
+	})
+
+	t.Run("hotspot needs top and share", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Selection{Distribution: "hotspot", Share: 1.5}, nil))
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "hotspot top")
+		assert.Contains(t, errs[1].Error(), "hotspot share")
+	})
+
+	t.Run("cannot be combined with cardinality", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Selection{Distribution: "zipf"}, &schema.Cardinality{Min: 1, Max: 10}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "cannot be combined with cardinality")
+	})
+}
+
+func TestValidatePolymorphic(t *testing.T) {
+	newSchema := func(poly *schema.Polymorphic) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"posts": {
+					Columns:    []*schema.Column{{Name: "id", Type: "serial"}},
+					PrimaryKey: []string{"id"},
+					RowCount:   10,
+				},
+				"tags": {
+					Columns:  []*schema.Column{{Name: "name", Type: "text"}},
+					RowCount: 10,
+				},
+				"comments": {
+					Columns: []*schema.Column{
+						{Name: "commentable_type", Type: "varchar(50)"},
+						{Name: "commentable_id", Type: "integer"},
+					},
+					Polymorphic: []*schema.Polymorphic{poly},
+					RowCount:    10,
+				},
+			},
+		}
+	}
+	weight := func(v float64) *float64 { return &v }
+
+	t.Run("valid polymorphic reference", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Polymorphic{
+			TypeColumn: "commentable_type", IDColumn: "commentable_id",
+			Targets: []*schema.PolymorphicTarget{{Table: "posts", Weight: weight(2)}, {Table: "tags", Column: "name"}},
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("columns must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Polymorphic{
+			TypeColumn: "kind", IDColumn: "commentable_id",
+			Targets: []*schema.PolymorphicTarget{{Table: "posts"}},
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "polymorphic column 'kind' does not exist")
+	})
+
+	t.Run("targets must exist and have a key column", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Polymorphic{
+			TypeColumn: "commentable_type", IDColumn: "commentable_id",
+			Targets: []*schema.PolymorphicTarget{{Table: "videos"}, {Table: "tags"}, {Table: "posts", Column: "slug"}},
+		}))
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "'videos' does not exist")
+		assert.Contains(t, errs[1].Error(), "no single-column primary key")
+		assert.Contains(t, errs[2].Error(), "'slug' does not exist in table 'posts'")
+	})
+
+	t.Run("needs distinct targets with positive weights", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Polymorphic{
+			TypeColumn: "commentable_type", IDColumn: "commentable_id",
+			Targets: []*schema.PolymorphicTarget{{Table: "posts", Weight: weight(0)}, {Table: "posts"}},
+		}))
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "weight must be positive")
+		assert.Contains(t, errs[1].Error(), "lists type 'posts' twice")
+
+		errs = schema.Validate(newSchema(&schema.Polymorphic{TypeColumn: "commentable_type", IDColumn: "commentable_id"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "needs at least one target")
+	})
+}
+
+func TestValidateParentSubset(t *testing.T) {
+	newSchema := func(fk *schema.ForeignKey, nullable bool) *schema.Schema {
+		fk.Columns = []string{"product_id"}
+		fk.ReferencedTable = "products"
+		fk.ReferencedColumns = []string{"id"}
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"products": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "status", Type: "text"},
+					},
+					RowCount: 10,
+				},
+				"orders": {
+					Columns:     []*schema.Column{{Name: "product_id", Type: "integer", Nullable: nullable}},
+					ForeignKeys: []*schema.ForeignKey{fk},
+					RowCount:    10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid null rate and where", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.ForeignKey{
+			NullRate: 0.8,
+			Where:    map[string]interface{}{"status": []interface{}{"active", "preorder"}},
+		}, true))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("null rate needs nullable columns", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.ForeignKey{NullRate: 0.5}, false))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "null_rate needs nullable columns")
+	})
+
+	t.Run("null rate must be a share", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.ForeignKey{NullRate: 80}, true))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "between 0 and 1")
+	})
+
+	t.Run("where columns must exist in the parent", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.ForeignKey{Where: map[string]interface{}{"state": "active"}}, false))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "where column 'state' does not exist in table 'products'")
+	})
+
+	t.Run("not combined with cardinality", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.ForeignKey{
+			Where:       map[string]interface{}{"status": "active"},
+			Cardinality: &schema.Cardinality{Min: 1, Max: 3},
+		}, false))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "not supported with cardinality")
+	})
+}
+
+func TestValidateExpression(t *testing.T) {
+	newSchema := func(columns ...*schema.Column) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"orders": {
+					Columns: append([]*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "price", Type: "numeric(10,2)"},
+						{Name: "created_at", Type: "timestamp"},
+					}, columns...),
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid expressions", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Column{Name: "total", Type: "numeric(10,2)", Expression: "round(price * 1.2, 2)"},
+			&schema.Column{Name: "due_at", Type: "timestamp", Expression: "add_days(created_at, 30)"},
+			&schema.Column{Name: "label", Type: "text", Expression: "'#' || id || ' due ' || date(due_at)"},
+		))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Column{Name: "total", Type: "numeric(10,2)", Expression: "price *"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "invalid expression")
+	})
+
+	t.Run("referenced columns must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Column{Name: "total", Type: "numeric(10,2)", Expression: "price * quantity"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "expression column 'quantity' does not exist")
+	})
+
+	t.Run("cannot be combined with a generator", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Column{Name: "total", Type: "integer", Expression: "price", GeneratorType: "integer_range"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "cannot be combined with a generator")
+	})
+
+	t.Run("expressions and temporal constraints cannot form a cycle", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Column{Name: "due_at", Type: "timestamp", Expression: "add_days(shipped_at, 30)"},
+			&schema.Column{Name: "shipped_at", Type: "timestamp", Temporal: &schema.Temporal{After: "due_at"}},
+		))
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "form a cycle")
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	newSchema := func(rules ...*schema.BusinessRule) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"customers": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "tier", Type: "varchar(10)"},
+					},
+					RowCount: 10,
+				},
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "customer_id", Type: "integer"},
+						{Name: "amount", Type: "integer"},
+						{Name: "priority", Type: "varchar(10)", Rules: rules},
+					},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"customer_id"}, ReferencedTable: "customers", ReferencedColumns: []string{"id"}},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+	value := func(v interface{}) map[string]interface{} {
+		return map[string]interface{}{"value": v}
+	}
+
+	t.Run("valid rules", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.BusinessRule{Condition: map[string]interface{}{"amount": map[string]interface{}{"gt": 100.0}}, Then: value("high")},
+			&schema.BusinessRule{
+				Condition: map[string]interface{}{"customers.tier": "gold"},
+				Then:      map[string]interface{}{"weights": map[string]interface{}{"high": 1.0, "normal": 1.0}},
+				Else:      map[string]interface{}{"distribution": map[string]interface{}{"type": "weighted", "weights": map[string]interface{}{"low": 1.0}}},
+			},
+		))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.BusinessRule{Condition: map[string]interface{}{"amount": map[string]interface{}{"above": 1.0}}, Then: value("high")}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "rule 1: invalid condition: unknown operator 'above'")
+	})
+
+	t.Run("condition columns must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.BusinessRule{Condition: map[string]interface{}{"total": 1.0}, Then: value("high")},
+			&schema.BusinessRule{Condition: map[string]interface{}{"customers.segment": "b2b"}, Then: value("high")},
+			&schema.BusinessRule{Condition: map[string]interface{}{"products.price": 1.0}, Then: value("high")},
+		))
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "condition column 'total' does not exist")
+		assert.Contains(t, errs[1].Error(), "'segment' does not exist in table 'customers'")
+		assert.Contains(t, errs[2].Error(), "needs a foreign key to 'products'")
+	})
+
+	t.Run("invalid actions", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.BusinessRule{Condition: map[string]interface{}{}, Then: map[string]interface{}{"min": 1.0}},
+			&schema.BusinessRule{Condition: map[string]interface{}{}, Then: map[string]interface{}{"distribution": map[string]interface{}{"type": "zipf", "alpha": 0.5}}},
+			&schema.BusinessRule{Condition: map[string]interface{}{}, Then: map[string]interface{}{"pick": "x"}},
+		))
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "'min' and 'max' must be set together")
+		assert.Contains(t, errs[1].Error(), "alpha' greater than 1")
+		assert.Contains(t, errs[2].Error(), "action needs one of")
+	})
+
+	t.Run("weights must be non-negative numbers, not all zero", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.BusinessRule{Condition: map[string]interface{}{}, Then: map[string]interface{}{"weights": map[string]interface{}{"a": "x", "b": 0.0}}},
+			&schema.BusinessRule{Condition: map[string]interface{}{}, Then: map[string]interface{}{"weights": map[string]interface{}{"a": -1.0, "b": 2.0}}},
+			&schema.BusinessRule{Condition: map[string]interface{}{}, Then: map[string]interface{}{"distribution": map[string]interface{}{"type": "weighted", "weights": map[string]interface{}{"a": 0.0, "b": 0.0}}}},
+		))
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), `weight of "a" must be a non-negative number, got x`)
+		assert.Contains(t, errs[1].Error(), `weight of "a" must be a non-negative number, got -1`)
+		assert.Contains(t, errs[2].Error(), "weights must not all be zero")
+	})
+
+	t.Run("else must be on the last rule", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.BusinessRule{Condition: map[string]interface{}{"amount": 1.0}, Then: value("high"), Else: value("low")},
+			&schema.BusinessRule{Condition: map[string]interface{}{"amount": 2.0}, Then: value("normal")},
+		))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "rules after it never apply")
+	})
+
+	t.Run("a rule cannot read its own column", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.BusinessRule{Condition: map[string]interface{}{"priority": "high"}, Then: value("high")}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "form a cycle")
+	})
+}
+
+func TestValidateCorrelations(t *testing.T) {
+	normal := func(mean, stdDev float64) *schema.DistributionConfig {
+		return &schema.DistributionConfig{Type: "normal", Mean: &mean, StdDev: &stdDev}
+	}
+	newSchema := func(corr *schema.Correlation) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"people": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "name", Type: "varchar(50)"},
+						{Name: "height", Type: "numeric(5,1)"},
+						{Name: "weight", Type: "integer"},
+						{Name: "bmi", Type: "real", Expression: "weight / (height * height / 10000)"},
+					},
+					Correlations: []*schema.Correlation{corr},
+					RowCount:     10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid correlation", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Correlation{
+			Columns: []string{"height", "weight"},
+			Marginals: map[string]*schema.DistributionConfig{
+				"height": normal(170, 10),
+				"weight": {Type: "empirical", Values: []float64{60, 70, 80}},
+			},
+			Matrix: [][]float64{{1, 0.7}, {0.7, 1}},
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("columns must be numeric, generated columns", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Correlation{
+			Columns:   []string{"name", "bmi", "age"},
+			Marginals: map[string]*schema.DistributionConfig{"name": normal(0, 1), "bmi": normal(0, 1), "age": normal(0, 1)},
+			Matrix:    [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		}))
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "column 'name' has type varchar(50), only numeric columns can be correlated")
+		assert.Contains(t, errs[1].Error(), "column 'bmi' cannot also have a generator, expression or rules")
+		assert.Contains(t, errs[2].Error(), "column 'age' does not exist")
+	})
+
+	t.Run("marginals and matrix", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Correlation{
+			Columns: []string{"height", "weight"},
+			Marginals: map[string]*schema.DistributionConfig{
+				"height": {Type: "gamma"},
+				"bmi":    normal(22, 3),
+			},
+			Matrix: [][]float64{{1, 0.7}, {0.6, 1}},
+		}))
+		require.Len(t, errs, 4)
+		assert.Contains(t, errs[0].Error(), "column 'height': unknown marginal type 'gamma'")
+		assert.Contains(t, errs[1].Error(), "column 'weight' has no marginal distribution")
+		assert.Contains(t, errs[2].Error(), "marginal for 'bmi', which is not one of its columns")
+		assert.Contains(t, errs[3].Error(), "invalid matrix: matrix is not symmetric")
+	})
+
+	t.Run("ragged and short matrices are rejected", func(t *testing.T) {
+		for message, matrix := range map[string][][]float64{
+			"invalid matrix: matrix row 2 has 0 values, expected 2": {{1, 0}, {}},
+			"invalid matrix: matrix has 1 rows, expected 2":         {{1, 0}},
+		} {
+			errs := schema.Validate(newSchema(&schema.Correlation{
+				Columns:   []string{"height", "weight"},
+				Marginals: map[string]*schema.DistributionConfig{"height": normal(170, 10), "weight": normal(70, 10)},
+				Matrix:    matrix,
+			}))
+			require.Len(t, errs, 1, message)
+			assert.Contains(t, errs[0].Error(), message)
+		}
+	})
+}
+
+func TestValidateConditional(t *testing.T) {
+	newSchema := func(config map[string]interface{}) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"customers": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "country", Type: "char(2)"},
+					},
+					RowCount: 10,
+				},
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "customer_id", Type: "integer"},
+						{Name: "channel", Type: "varchar(10)"},
+						{Name: "currency", Type: "char(3)", GeneratorConfig: config},
+					},
+					ForeignKeys: []*schema.ForeignKey{
+						{Columns: []string{"customer_id"}, ReferencedTable: "customers", ReferencedColumns: []string{"id"}},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+	weights := map[string]interface{}{"*": map[string]interface{}{"USD": 1.0}}
+
+	t.Run("valid conditional weights", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "conditional", "given": []interface{}{"customers.country", "channel"},
+			"weights": map[string]interface{}{
+				"JP|web": map[string]interface{}{"JPY": 95.0, "USD": 5.0},
+				"*":      map[string]interface{}{"USD": 1.0},
+			},
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("given columns must be readable", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "conditional", "given": []interface{}{"region", "products.category", "customers.tier"}, "weights": weights,
+		}))
+		require.Len(t, errs, 3)
+		assert.Contains(t, errs[0].Error(), "given column 'region' does not exist")
+		assert.Contains(t, errs[1].Error(), "'products.category' needs a foreign key to 'products'")
+		assert.Contains(t, errs[2].Error(), "'tier' does not exist in table 'customers'")
+	})
+
+	t.Run("invalid table", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{"type": "conditional", "weights": weights}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "table orders, column currency: conditional 'given' must list")
+	})
+
+	t.Run("cannot depend on itself", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "conditional", "given": []interface{}{"currency"}, "weights": weights,
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "form a cycle")
+	})
+}
+
+func TestValidateLifecycle(t *testing.T) {
+	hours := func(mean, stdDev float64) *schema.DistributionConfig {
+		return &schema.DistributionConfig{Type: "normal", Mean: &mean, StdDev: &stdDev}
+	}
+	newSchema := func(lifecycle *schema.Lifecycle) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "created_at", Type: "timestamp"},
+						{Name: "status", Type: "varchar(20)", Lifecycle: lifecycle},
+						{Name: "paid_at", Type: "timestamp", Nullable: true},
+						{Name: "shipped_at", Type: "timestamp"},
+						{Name: "note", Type: "text", Expression: "'x'"},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid lifecycle", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Lifecycle{
+			Start: "created_at", Initial: "pending",
+			States: map[string]*schema.LifecycleState{
+				"pending":   {Transitions: map[string]float64{"paid": 0.7, "cancelled": 0.2}, Dwell: hours(2, 1)},
+				"paid":      {Timestamp: "paid_at"},
+				"cancelled": {},
+			},
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("invalid states", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Lifecycle{
+			Start: "created_at", Initial: "new",
+			States: map[string]*schema.LifecycleState{
+				"pending": {Transitions: map[string]float64{"paid": 0.7, "lost": 0.5}},
+				"paid":    {Transitions: map[string]float64{"pending": 1}, Dwell: hours(1, 0)},
+			},
+		}))
+		require.Len(t, errs, 6)
+		assert.Contains(t, errs[0].Error(), "lifecycle initial state 'new' is not one of its states")
+		assert.Contains(t, errs[1].Error(), "state 'paid': invalid dwell: normal distribution needs a positive 'std_dev'")
+		assert.Contains(t, errs[2].Error(), "state 'pending' moves on to unknown state 'lost'")
+		assert.Contains(t, errs[3].Error(), "transition probabilities add up to 1.2, more than 1")
+		assert.Contains(t, errs[4].Error(), "state 'pending' needs a 'dwell' distribution")
+		assert.Contains(t, errs[5].Error(), "state 'paid' can be reached again")
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		errs := schema.Validate(newSchema(&schema.Lifecycle{
+			Start: "placed_at", Initial: "pending",
+			States: map[string]*schema.LifecycleState{
+				"pending": {Timestamp: "note", Transitions: map[string]float64{"paid": 1}, Dwell: hours(2, 1)},
+				"paid":    {Timestamp: "shipped_at", Transitions: map[string]float64{"shipped": 1}, Dwell: hours(2, 1)},
+				"shipped": {Timestamp: "shipped_at"},
+				"lost":    {Timestamp: "lost_at"},
+			},
+		}))
+		require.Len(t, errs, 5)
+		assert.Contains(t, errs[0].Error(), "lifecycle start 'placed_at' does not exist")
+		assert.Contains(t, errs[1].Error(), "state 'lost' timestamp column 'lost_at' does not exist")
+		assert.Contains(t, errs[2].Error(), "state 'paid' timestamp column 'shipped_at' must be nullable")
+		assert.Contains(t, errs[3].Error(), "state 'pending' timestamp column 'note' cannot also have a generator, expression")
+		assert.Contains(t, errs[4].Error(), "state 'shipped' timestamp column 'shipped_at' is already filled")
+	})
+}
+
+func TestValidateTuples(t *testing.T) {
+	newSchema := func(tuples ...*schema.Tuple) *schema.Schema {
+		mean, stdDev := 10.0, 1.0
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"customers": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "name", Type: "varchar(100)"},
+						{Name: "email", Type: "varchar(100)"},
+						{Name: "city", Type: "varchar(50)"},
+						{Name: "zip", Type: "varchar(10)", Expression: "'00000'"},
+						{Name: "lat", Type: "numeric(9,6)"},
+						{Name: "lng", Type: "numeric(9,6)"},
+					},
+					Correlations: []*schema.Correlation{{
+						Columns: []string{"lat", "lng"},
+						Marginals: map[string]*schema.DistributionConfig{
+							"lat": {Type: "normal", Mean: &mean, StdDev: &stdDev},
+							"lng": {Type: "normal", Mean: &mean, StdDev: &stdDev},
+						},
+						Matrix: [][]float64{{1, 0}, {0, 1}},
+					}},
+					Tuples:   tuples,
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid tuples", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Tuple{Generator: "person", Columns: map[string]string{"full_name": "name", "email": "email"}},
+			&schema.Tuple{Generator: "geo", Countries: []string{"FR"}, Columns: map[string]string{"city": "city"}},
+		))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("generators and roles must exist", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Tuple{Generator: "vehicle", Columns: map[string]string{"make": "name"}},
+			&schema.Tuple{Generator: "person", Columns: map[string]string{"nickname": "name"}, Countries: []string{"FR"}},
+			&schema.Tuple{Generator: "credit_card"},
+		))
+		require.Len(t, errs, 4)
+		assert.Contains(t, errs[0].Error(), "tuple 1: unknown generator 'vehicle' (use address, credit_card, geo, person)")
+		assert.Contains(t, errs[1].Error(), "tuple 2 (person): 'countries' only applies to address and geo")
+		assert.Contains(t, errs[2].Error(), "tuple 2 (person): unknown role 'nickname'")
+		assert.Contains(t, errs[3].Error(), "tuple 3 (credit_card): needs 'columns'")
+	})
+
+	t.Run("columns are filled once", func(t *testing.T) {
+		errs := schema.Validate(newSchema(
+			&schema.Tuple{Generator: "address", Columns: map[string]string{"city": "city", "latitude": "lat", "postal_code": "zip", "street": "line1"}},
+			&schema.Tuple{Generator: "geo", Columns: map[string]string{"city": "city"}},
+		))
+		require.Len(t, errs, 4)
+		assert.Contains(t, errs[0].Error(), "column 'lat' is already filled by a tuple or correlation")
+		assert.Contains(t, errs[1].Error(), "column 'zip' cannot also have a generator, expression, rules or lifecycle")
+		assert.Contains(t, errs[2].Error(), "column 'line1' does not exist")
+		assert.Contains(t, errs[3].Error(), "tuple 2 (geo): column 'city' is already filled")
+	})
+}
+
+func TestValidateDistribution(t *testing.T) {
+	newSchema := func(config map[string]interface{}) *schema.Schema {
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"orders": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "amount", Type: "numeric(10,2)", GeneratorConfig: config},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid distributions", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type": "distribution",
+			"distribution": map[string]interface{}{
+				"type": "empirical", "null_rate": 0.1,
+				"bins": []interface{}{map[string]interface{}{"min": 0.0, "max": 10.0, "weight": 1.0}},
+			},
+		}))
+		assert.Empty(t, errs)
+	})
+
+	t.Run("invalid distributions", func(t *testing.T) {
+		errs := schema.Validate(newSchema(map[string]interface{}{
+			"type":         "distribution",
+			"distribution": map[string]interface{}{"type": "gamma"},
+		}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "table orders, column amount: unknown distribution type 'gamma' (use weighted, normal, poisson, zipf or empirical)")
+
+		errs = schema.Validate(newSchema(map[string]interface{}{"type": "distribution"}))
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "'distribution' expects an object")
+	})
+}
+
+func TestValidateText(t *testing.T) {
+	newSchema := func(config map[string]interface{}) *schema.Schema {
+		config["type"] = "text"
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"reviews": {
+					Columns: []*schema.Column{
+						{Name: "id", Type: "serial"},
+						{Name: "body", Type: "varchar(500)", GeneratorConfig: config},
+					},
+					RowCount: 10,
+				},
+			},
+		}
+	}
+
+	t.Run("valid text", func(t *testing.T) {
+		for _, config := range []map[string]interface{}{
+			{"mode": "paragraphs", "count": map[string]interface{}{"min": 1.0, "max": 3.0}, "sentences": 4.0},
+			{"mode": "markov", "corpus": "reviews.txt", "order": 3.0},
+			{"mode": "review", "sentiment": map[string]interface{}{"positive": 3.0, "negative": 1.0}, "max_length": 200.0},
+		} {
+			assert.Empty(t, schema.Validate(newSchema(config)))
+		}
+	})
+
+	t.Run("invalid text", func(t *testing.T) {
+		for message, config := range map[string]map[string]interface{}{
+			"unknown text mode 'poem' (use words, sentences, paragraphs, markov, product_title, review, support_ticket)": {"mode": "poem"},
+			"text 'count': counts must be whole numbers from 1, with 'min' up to 'max'":                                  {"count": map[string]interface{}{"min": 3.0, "max": 1.0}},
+			"text 'words': expects a number or {\"min\": ..., \"max\": ...}":                                              {"words": "many"},
+			"markov text needs a 'corpus' file":                                                                          {"mode": "markov"},
+			"markov 'order' must be a whole number from 1 to 4":                                                          {"mode": "markov", "corpus": "a.txt", "order": 7.0},
+			"'sentiment' applies to reviews":                                                                             {"sentiment": map[string]interface{}{"positive": 1.0}},
+			"'sentiment' maps positive, neutral and negative to weights, got happy: 1":                                   {"mode": "review", "sentiment": map[string]interface{}{"happy": 1.0}},
+			"text 'max_length' must be a positive whole number":                                                          {"max_length": 0.0},
+		} {
+			errs := schema.Validate(newSchema(config))
+			require.Len(t, errs, 1, message)
+			assert.Contains(t, errs[0].Error(), "table reviews, column body: "+message)
+		}
+	})
+
+	t.Run("length limit comes from the column type", func(t *testing.T) {
+		for columnType, length := range map[string]int{"varchar(40)": 40, "character varying(12)": 12, "CHAR(3)": 3, "text": 0} {
+			assert.Equal(t, length, (&schema.Column{Type: columnType}).MaxLength(), columnType)
+		}
+		config, err := (&schema.Column{Type: "varchar(40)", GeneratorConfig: map[string]interface{}{"type": "text", "max_length": 100.0}}).Text()
+		require.NoError(t, err)
+		assert.Equal(t, 40, config.MaxLength)
+	})
+}
+
+func TestValidateDictionary(t *testing.T) {
+	newSchema := func(configs ...map[string]interface{}) *schema.Schema {
+		columns := []*schema.Column{{Name: "id", Type: "serial"}}
+		for i, config := range configs {
+			config["type"] = "dictionary"
+			columns = append(columns, &schema.Column{Name: "col" + string(rune('1'+i)), Type: "text", GeneratorConfig: config})
+		}
+		return &schema.Schema{
+			Version:  "1.0",
+			Database: schema.DatabaseConfig{Name: "testdb"},
+			Tables: map[string]*schema.Table{
+				"flights": {Columns: columns, RowCount: 10},
+			},
+		}
+	}
+
+	t.Run("valid dictionaries", func(t *testing.T) {
+		assert.Empty(t, schema.Validate(newSchema(
+			map[string]interface{}{"file": "codes.txt", "replacement": false},
+			map[string]interface{}{"file": "airports.csv", "column": "iata", "weight": "passengers"},
+			map[string]interface{}{"file": "airports.csv", "column": "name", "weight": "passengers"},
+			map[string]interface{}{"file": "airports.csv", "column": "iata", "draw": "destination"},
+		)))
+	})
+
+	t.Run("invalid dictionaries", func(t *testing.T) {
+		for message, configs := range map[string][]map[string]interface{}{
+			"column col1: dictionary needs a 'file' of reference data":                                {{"column": "code"}},
+			"column col1: dictionary 'weight' must be a non-empty string":                             {{"file": "a.csv", "weight": 2.0}},
+			"column col1: dictionary 'column' and 'weight' need a CSV or TSV file with a header line": {{"file": "codes.txt", "column": "code"}},
+			"column col1: dictionary 'replacement' must be true or false":                             {{"file": "a.csv", "replacement": "no"}},
+			"column col2: columns reading the same row of a.csv must agree on 'weight' and 'replacement'": {
+				{"file": "a.csv", "column": "code"},
+				{"file": "a.csv", "column": "name", "replacement": false},
+			},
+		} {
+			errs := schema.Validate(newSchema(configs...))
+			require.Len(t, errs, 1, message)
+			assert.Contains(t, errs[0].Error(), "table flights, "+message)
+		}
+	})
+}